@@ -0,0 +1,146 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldpath
+
+import (
+	"math"
+	"strconv"
+
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+const (
+	// bloomBitsPerPath and bloomHashes give a false positive rate of
+	// about 1%.
+	bloomBitsPerPath = 10
+	bloomHashes      = 7
+)
+
+// bloomFilter is a bloom filter of paths, used by Set.Has to quickly reject
+// paths that are not members of large sets.
+type bloomFilter struct {
+	bits []uint64
+}
+
+func newBloomFilter(paths int) *bloomFilter {
+	words := (paths*bloomBitsPerPath + 63) / 64
+	if words == 0 {
+		words = 1
+	}
+	return &bloomFilter{bits: make([]uint64, words)}
+}
+
+// locations calls f with the bit locations of the path with hash h.
+func (b *bloomFilter) locations(h uint64, f func(word int, mask uint64)) {
+	n := uint64(len(b.bits) * 64)
+	h1, h2 := h&0xffffffff, h>>32|1
+	for i := uint64(0); i < bloomHashes; i++ {
+		bit := (h1 + i*h2) % n
+		f(int(bit/64), 1<<(bit%64))
+	}
+}
+
+func (b *bloomFilter) insert(p Path) {
+	b.locations(hashPath(p), func(word int, mask uint64) {
+		b.bits[word] |= mask
+	})
+}
+
+// mayHave returns false if p is definitely not in the filter.
+func (b *bloomFilter) mayHave(p Path) bool {
+	found := true
+	b.locations(hashPath(p), func(word int, mask uint64) {
+		if b.bits[word]&mask == 0 {
+			found = false
+		}
+	})
+	return found
+}
+
+const (
+	fnvOffset = 14695981039346656037
+	fnvPrime  = 1099511628211
+)
+
+// hashPath returns the FNV-1a hash of p.
+func hashPath(p Path) uint64 {
+	h := uint64(fnvOffset)
+	hashString := func(s string) {
+		for i := 0; i < len(s); i++ {
+			h ^= uint64(s[i])
+			h *= fnvPrime
+		}
+		// Separate consecutive strings.
+		h ^= 0xff
+		h *= fnvPrime
+	}
+	// Values that are equal must hash the same, e.g. 1 and 1.0.
+	hashValue := func(v value.Value) {
+		switch {
+		case v.IsString():
+			hashString(v.AsString())
+		case v.IsInt():
+			hashString(strconv.FormatUint(math.Float64bits(float64(v.AsInt())), 16))
+		case v.IsFloat():
+			hashString(strconv.FormatUint(math.Float64bits(v.AsFloat()), 16))
+		case v.IsBool():
+			hashString(strconv.FormatBool(v.AsBool()))
+		case v.IsNull():
+			hashString("null")
+		default:
+			// Lists and maps are not hashed.
+			hashString("")
+		}
+	}
+	for _, pe := range p {
+		switch {
+		case pe.FieldName != nil:
+			hashString("f")
+			hashString(*pe.FieldName)
+		case pe.Key != nil:
+			hashString("k")
+			for _, field := range *pe.Key {
+				hashString(field.Name)
+				hashValue(field.Value)
+			}
+		case pe.Value != nil:
+			hashString("v")
+			hashValue(*pe.Value)
+		case pe.Index != nil:
+			hashString("i")
+			for i := uint64(*pe.Index); ; i >>= 8 {
+				h ^= i & 0xff
+				h *= fnvPrime
+				if i < 0x100 {
+					break
+				}
+			}
+		}
+	}
+	return h
+}
+
+// BuildBloom builds a bloom filter of the members of s, that Has then uses
+// to quickly reject paths that are not members. This is worth it for large
+// sets that are queried a lot. Paths inserted in s with Insert are added to
+// the filter, but the filter must be rebuilt if the subsets of s are
+// modified directly.
+func (s *Set) BuildBloom() {
+	b := newBloomFilter(s.Size())
+	s.Iterate(b.insert)
+	s.bloom = b
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldpath
+
+import (
+	"fmt"
+	"testing"
+)
+
+// largeSet returns a set of n paths of various kinds, along with the paths.
+func largeSet(n int) (*Set, []Path) {
+	paths := make([]Path, 0, n)
+	for i := 0; len(paths) < n; i++ {
+		name := fmt.Sprintf("item-%d", i)
+		paths = append(paths,
+			MakePathOrDie("spec", "containers", KeyByFields("name", name), "image"),
+			MakePathOrDie("spec", "ports", i, "port"),
+			MakePathOrDie("metadata", "labels", name),
+		)
+	}
+	paths = paths[:n]
+	return NewSet(paths...), paths
+}
+
+// missingPaths returns n paths that are not in sets built by largeSet.
+func missingPaths(n int) []Path {
+	paths := make([]Path, n)
+	for i := range paths {
+		name := fmt.Sprintf("missing-%d", i)
+		paths[i] = MakePathOrDie("spec", "containers", KeyByFields("name", name), "image")
+	}
+	return paths
+}
+
+func TestSetBloom(t *testing.T) {
+	s, paths := largeSet(10000)
+	missing := missingPaths(10000)
+	s.BuildBloom()
+
+	for _, p := range paths {
+		if !s.Has(p) {
+			t.Fatalf("expected %v to be in the set", p)
+		}
+	}
+	for _, p := range missing {
+		if s.Has(p) {
+			t.Fatalf("expected %v not to be in the set", p)
+		}
+	}
+	// Parents are not members.
+	if s.Has(MakePathOrDie("spec", "containers")) {
+		t.Errorf("expected .spec.containers not to be in the set")
+	}
+
+	// Inserted paths are added to the filter.
+	for _, p := range missing[:100] {
+		s.Insert(p)
+	}
+	for _, p := range missing[:100] {
+		if !s.Has(p) {
+			t.Fatalf("expected inserted %v to be in the set", p)
+		}
+	}
+
+	falsePositives := 0
+	for _, p := range missing[100:] {
+		if s.bloom.mayHave(p) {
+			falsePositives++
+		}
+	}
+	if rate := float64(falsePositives) / float64(len(missing)-100); rate > 0.05 {
+		t.Errorf("expected a false positive rate below 5%%, got %v", rate)
+	}
+}
+
+func TestSetBloomEmpty(t *testing.T) {
+	s := NewSet()
+	s.BuildBloom()
+	if s.Has(MakePathOrDie("a")) {
+		t.Errorf("expected empty set not to have .a")
+	}
+	s.Insert(MakePathOrDie("a"))
+	if !s.Has(MakePathOrDie("a")) {
+		t.Errorf("expected set to have .a")
+	}
+}
+
+func BenchmarkSetHasMissing(b *testing.B) {
+	s, _ := largeSet(10000)
+	missing := missingPaths(1000)
+	b.Run("Tree", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			s.Has(missing[i%len(missing)])
+		}
+	})
+	withBloom, _ := largeSet(10000)
+	withBloom.BuildBloom()
+	b.Run("Bloom", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			withBloom.Has(missing[i%len(missing)])
+		}
+	})
+}
+
+func TestSetBloomEqualKeys(t *testing.T) {
+	s := NewSet(MakePathOrDie("ports", KeyByFields("port", 80)))
+	s.BuildBloom()
+	// Keys compare by value, so the float key is a member too.
+	if !s.Has(MakePathOrDie("ports", KeyByFields("port", 80.0))) {
+		t.Errorf("expected set to have the port with a float key")
+	}
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldpath
+
+import (
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// CompareSets compares old and new without a schema, and returns the paths
+// of the fields that were added, removed and modified. Maps are compared
+// field by field, and lists item by item using their index. Added and
+// removed sets contain the paths of the whole subtrees that were added or
+// removed, while modified only contains the paths of values that exist on
+// both sides but differ, either because they are different scalars or
+// because their kind changed.
+func CompareSets(old, new value.Value) (added, removed, modified *Set) {
+	return CompareSetsKeyed(old, new, nil)
+}
+
+// CompareSetsKeyed is like CompareSets, but compares the lists listed in
+// listKeys as associative lists: their items are matched by the given key
+// fields rather than by index, so reordering them isn't a change. Lists are
+// named by the field names leading to them joined with dots, ignoring list
+// items, as in Set.Rename: "spec.containers" names the containers list,
+// and "spec.containers.ports" the ports list of every container. Lists
+// whose items can't all be keyed, e.g. because they miss a key field or
+// have duplicate keys, are compared by index.
+func CompareSetsKeyed(old, new value.Value, listKeys map[string][]string) (added, removed, modified *Set) {
+	c := setComparer{
+		listKeys: listKeys,
+		added:    NewSet(),
+		removed:  NewSet(),
+		modified: NewSet(),
+	}
+	c.compare(old, new)
+	return c.added, c.removed, c.modified
+}
+
+type setComparer struct {
+	listKeys map[string][]string
+	path     Path
+	added    *Set
+	removed  *Set
+	modified *Set
+}
+
+func (c *setComparer) compare(old, new value.Value) {
+	switch {
+	case old.IsMap() && new.IsMap():
+		oldMap, newMap := old.AsMap(), new.AsMap()
+		oldMap.Iterate(func(key string, o value.Value) bool {
+			c.push(PathElement{FieldName: &key})
+			if n, ok := newMap.Get(key); ok {
+				c.compare(o, n)
+			} else {
+				c.insertAll(c.removed, o)
+			}
+			c.pop()
+			return true
+		})
+		newMap.Iterate(func(key string, n value.Value) bool {
+			if !oldMap.Has(key) {
+				c.push(PathElement{FieldName: &key})
+				c.insertAll(c.added, n)
+				c.pop()
+			}
+			return true
+		})
+	case old.IsList() && new.IsList():
+		oldList, newList := old.AsList(), new.AsList()
+		oldPEs, oldKeyed := c.listItemPathElements(oldList)
+		newPEs, newKeyed := c.listItemPathElements(newList)
+		if oldKeyed && newKeyed {
+			c.compareKeyedItems(oldList, newList, oldPEs, newPEs)
+			return
+		}
+		for i := 0; i < oldList.Length() || i < newList.Length(); i++ {
+			i := i
+			c.push(PathElement{Index: &i})
+			switch {
+			case i >= newList.Length():
+				c.insertAll(c.removed, oldList.At(i))
+			case i >= oldList.Length():
+				c.insertAll(c.added, newList.At(i))
+			default:
+				c.compare(oldList.At(i), newList.At(i))
+			}
+			c.pop()
+		}
+	default:
+		if len(c.path) != 0 && !value.Equals(old, new) {
+			c.modified.Insert(c.path.Copy())
+		}
+	}
+}
+
+// compareKeyedItems compares the items of lists that are keyed, whose path
+// elements are given.
+func (c *setComparer) compareKeyedItems(oldList, newList value.List, oldPEs, newPEs []PathElement) {
+	oldItems := MakePathElementValueMap(len(oldPEs))
+	for i, pe := range oldPEs {
+		oldItems.Insert(pe, oldList.At(i))
+	}
+	newItems := MakePathElementValueMap(len(newPEs))
+	for i, pe := range newPEs {
+		newItems.Insert(pe, newList.At(i))
+	}
+	for i, pe := range oldPEs {
+		c.push(pe)
+		if n, ok := newItems.Get(pe); ok {
+			c.compare(oldList.At(i), n)
+		} else {
+			c.insertAll(c.removed, oldList.At(i))
+		}
+		c.pop()
+	}
+	for i, pe := range newPEs {
+		if _, ok := oldItems.Get(pe); !ok {
+			c.push(pe)
+			c.insertAll(c.added, newList.At(i))
+			c.pop()
+		}
+	}
+}
+
+// listItemPathElements returns the path elements that refer to the items of
+// l by key, and false if the list at the current path isn't keyed or some
+// of its items can't be keyed.
+func (c *setComparer) listItemPathElements(l value.List) ([]PathElement, bool) {
+	keys, ok := c.listKeys[c.fieldNames()]
+	if !ok || len(keys) == 0 {
+		return nil, false
+	}
+	pes := make([]PathElement, l.Length())
+	seen := MakePathElementSet(l.Length())
+	for i := range pes {
+		item := l.At(i)
+		if !item.IsMap() {
+			return nil, false
+		}
+		m := item.AsMap()
+		fields := make(value.FieldList, 0, len(keys))
+		for _, key := range keys {
+			v, ok := m.Get(key)
+			if !ok {
+				return nil, false
+			}
+			fields = append(fields, value.Field{Name: key, Value: v})
+		}
+		fields.Sort()
+		pes[i] = PathElement{Key: &fields}
+		if seen.Has(pes[i]) {
+			return nil, false
+		}
+		seen.Insert(pes[i])
+	}
+	return pes, true
+}
+
+// fieldNames returns the field names of the current path joined with dots.
+func (c *setComparer) fieldNames() string {
+	names := ""
+	for _, pe := range c.path {
+		if pe.FieldName == nil {
+			continue
+		}
+		if names != "" {
+			names += "."
+		}
+		names += *pe.FieldName
+	}
+	return names
+}
+
+// insertAll inserts the current path and the paths of all the fields and
+// items within v in set.
+func (c *setComparer) insertAll(set *Set, v value.Value) {
+	set.Insert(c.path.Copy())
+	switch {
+	case v.IsMap():
+		v.AsMap().Iterate(func(key string, child value.Value) bool {
+			c.push(PathElement{FieldName: &key})
+			c.insertAll(set, child)
+			c.pop()
+			return true
+		})
+	case v.IsList():
+		l := v.AsList()
+		pes, keyed := c.listItemPathElements(l)
+		for i := 0; i < l.Length(); i++ {
+			i := i
+			if keyed {
+				c.push(pes[i])
+			} else {
+				c.push(PathElement{Index: &i})
+			}
+			c.insertAll(set, l.At(i))
+			c.pop()
+		}
+	}
+}
+
+func (c *setComparer) push(pe PathElement) {
+	c.path = append(c.path, pe)
+}
+
+func (c *setComparer) pop() {
+	c.path = c.path[:len(c.path)-1]
+}
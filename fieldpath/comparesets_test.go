@@ -0,0 +1,182 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldpath
+
+import "testing"
+
+func TestCompareSets(t *testing.T) {
+	table := []struct {
+		name     string
+		old      string
+		new      string
+		added    *Set
+		removed  *Set
+		modified *Set
+	}{
+		{
+			name:     "same",
+			old:      `{"a": {"b": 1}, "c": [1, 2]}`,
+			new:      `{"a": {"b": 1}, "c": [1, 2]}`,
+			added:    NewSet(),
+			removed:  NewSet(),
+			modified: NewSet(),
+		}, {
+			name: "nested-maps",
+			old:  `{"a": {"b": 1, "c": {"d": "x"}}, "e": true}`,
+			new:  `{"a": {"b": 2, "f": {"g": "y"}}, "e": true}`,
+			added: NewSet(
+				MakePathOrDie("a", "f"),
+				MakePathOrDie("a", "f", "g"),
+			),
+			removed: NewSet(
+				MakePathOrDie("a", "c"),
+				MakePathOrDie("a", "c", "d"),
+			),
+			modified: NewSet(
+				MakePathOrDie("a", "b"),
+			),
+		}, {
+			name: "lists",
+			old:  `{"a": [{"name": "x", "value": 1}, {"name": "y"}], "b": [1, 2]}`,
+			new:  `{"a": [{"name": "x", "value": 2}], "b": [1, 2, [3]]}`,
+			added: NewSet(
+				MakePathOrDie("b", 2),
+				MakePathOrDie("b", 2, 0),
+			),
+			removed: NewSet(
+				MakePathOrDie("a", 1),
+				MakePathOrDie("a", 1, "name"),
+			),
+			modified: NewSet(
+				MakePathOrDie("a", 0, "value"),
+			),
+		}, {
+			name:    "kind-change",
+			old:     `{"a": {"b": 1}, "c": "d"}`,
+			new:     `{"a": [1], "c": {"d": 1}}`,
+			added:   NewSet(),
+			removed: NewSet(),
+			modified: NewSet(
+				MakePathOrDie("a"),
+				MakePathOrDie("c"),
+			),
+		},
+	}
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed, modified := CompareSets(mustParse(t, tt.old), mustParse(t, tt.new))
+			if !added.Equals(tt.added) {
+				t.Errorf("expected added\n%v\ngot\n%v", tt.added, added)
+			}
+			if !removed.Equals(tt.removed) {
+				t.Errorf("expected removed\n%v\ngot\n%v", tt.removed, removed)
+			}
+			if !modified.Equals(tt.modified) {
+				t.Errorf("expected modified\n%v\ngot\n%v", tt.modified, modified)
+			}
+		})
+	}
+}
+
+func TestCompareSetsKeyed(t *testing.T) {
+	listKeys := map[string][]string{
+		"spec.containers":       {"name"},
+		"spec.containers.ports": {"port", "protocol"},
+	}
+	old := `{"spec": {"containers": [
+  {"name": "a", "image": "a:1", "ports": [{"port": 80, "protocol": "TCP"}, {"port": 53, "protocol": "UDP"}]},
+  {"name": "b", "image": "b:1"}
+]}}`
+	table := []struct {
+		name     string
+		new      string
+		added    *Set
+		removed  *Set
+		modified *Set
+	}{
+		{
+			name: "reordered",
+			new: `{"spec": {"containers": [
+  {"name": "b", "image": "b:1"},
+  {"name": "a", "image": "a:1", "ports": [{"port": 53, "protocol": "UDP"}, {"port": 80, "protocol": "TCP"}]}
+]}}`,
+			added:    NewSet(),
+			removed:  NewSet(),
+			modified: NewSet(),
+		}, {
+			name: "modified-item",
+			new: `{"spec": {"containers": [
+  {"name": "b", "image": "b:2"},
+  {"name": "a", "image": "a:1", "ports": [{"port": 80, "protocol": "TCP"}, {"port": 53, "protocol": "UDP"}]}
+]}}`,
+			added:   NewSet(),
+			removed: NewSet(),
+			modified: NewSet(
+				MakePathOrDie("spec", "containers", KeyByFields("name", "b"), "image"),
+			),
+		}, {
+			name: "added-and-removed-items",
+			new: `{"spec": {"containers": [
+  {"name": "a", "image": "a:1", "ports": [{"port": 80, "protocol": "UDP"}, {"port": 53, "protocol": "UDP"}]},
+  {"name": "c", "image": "c:1"}
+]}}`,
+			added: NewSet(
+				MakePathOrDie("spec", "containers", KeyByFields("name", "a"), "ports", KeyByFields("port", 80, "protocol", "UDP")),
+				MakePathOrDie("spec", "containers", KeyByFields("name", "a"), "ports", KeyByFields("port", 80, "protocol", "UDP"), "port"),
+				MakePathOrDie("spec", "containers", KeyByFields("name", "a"), "ports", KeyByFields("port", 80, "protocol", "UDP"), "protocol"),
+				MakePathOrDie("spec", "containers", KeyByFields("name", "c")),
+				MakePathOrDie("spec", "containers", KeyByFields("name", "c"), "name"),
+				MakePathOrDie("spec", "containers", KeyByFields("name", "c"), "image"),
+			),
+			removed: NewSet(
+				MakePathOrDie("spec", "containers", KeyByFields("name", "a"), "ports", KeyByFields("port", 80, "protocol", "TCP")),
+				MakePathOrDie("spec", "containers", KeyByFields("name", "a"), "ports", KeyByFields("port", 80, "protocol", "TCP"), "port"),
+				MakePathOrDie("spec", "containers", KeyByFields("name", "a"), "ports", KeyByFields("port", 80, "protocol", "TCP"), "protocol"),
+				MakePathOrDie("spec", "containers", KeyByFields("name", "b")),
+				MakePathOrDie("spec", "containers", KeyByFields("name", "b"), "name"),
+				MakePathOrDie("spec", "containers", KeyByFields("name", "b"), "image"),
+			),
+			modified: NewSet(),
+		}, {
+			name: "missing-key",
+			new: `{"spec": {"containers": [
+  {"name": "a", "image": "a:1", "ports": [{"port": 80, "protocol": "TCP"}, {"port": 53, "protocol": "UDP"}]},
+  {"image": "b:1"}
+]}}`,
+			// Containers are compared by index.
+			added: NewSet(),
+			removed: NewSet(
+				MakePathOrDie("spec", "containers", 1, "name"),
+			),
+			modified: NewSet(),
+		},
+	}
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed, modified := CompareSetsKeyed(mustParse(t, old), mustParse(t, tt.new), listKeys)
+			if !added.Equals(tt.added) {
+				t.Errorf("expected added\n%v\ngot\n%v", tt.added, added)
+			}
+			if !removed.Equals(tt.removed) {
+				t.Errorf("expected removed\n%v\ngot\n%v", tt.removed, removed)
+			}
+			if !modified.Equals(tt.modified) {
+				t.Errorf("expected modified\n%v\ngot\n%v", tt.modified, modified)
+			}
+		})
+	}
+}
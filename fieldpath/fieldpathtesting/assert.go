@@ -0,0 +1,63 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fieldpathtesting provides helpers to test code that builds field
+// sets.
+package fieldpathtesting
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+// AssertSetEquals fails t if got and want don't contain the same paths. The
+// failure only lists the paths that are missing from got and the paths
+// that got has unexpectedly, rather than both sets, so that large sets that
+// nearly match are easy to tell apart. A nil set is the same as an empty
+// set.
+func AssertSetEquals(t testing.TB, got, want *fieldpath.Set) {
+	t.Helper()
+	if got == nil {
+		got = fieldpath.NewSet()
+	}
+	if want == nil {
+		want = fieldpath.NewSet()
+	}
+	if got.Equals(want) {
+		return
+	}
+	t.Errorf("sets differ:%v%v",
+		describePaths("missing", want.Difference(got)),
+		describePaths("unexpected", got.Difference(want)))
+}
+
+// describePaths lists the paths of s, one per line, after a heading.
+func describePaths(heading string, s *fieldpath.Set) string {
+	if s.Empty() {
+		return ""
+	}
+	b := strings.Builder{}
+	b.WriteString("\n")
+	b.WriteString(heading)
+	b.WriteString(":")
+	s.Iterate(func(p fieldpath.Path) {
+		b.WriteString("\n  ")
+		b.WriteString(p.String())
+	})
+	return b.String()
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldpathtesting
+
+import (
+	"fmt"
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+var _P = fieldpath.MakePathOrDie
+
+// recordingTB records the failures reported to it.
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertSetEquals(t *testing.T) {
+	want := fieldpath.NewSet(
+		_P("metadata", "labels", "app"),
+		_P("spec", "replicas"),
+		_P("spec", "containers", fieldpath.KeyByFields("name", "a"), "image"),
+		_P("spec", "containers", fieldpath.KeyByFields("name", "b"), "image"),
+	)
+
+	r := &recordingTB{TB: t}
+	AssertSetEquals(r, want.Union(fieldpath.NewSet()), want)
+	AssertSetEquals(r, nil, fieldpath.NewSet())
+	if len(r.failures) != 0 {
+		t.Fatalf("expected equal sets to pass, got %v", r.failures)
+	}
+
+	got := fieldpath.NewSet(
+		_P("metadata", "labels", "app"),
+		_P("metadata", "labels", "tier"),
+		_P("spec", "replicas"),
+		_P("spec", "containers", fieldpath.KeyByFields("name", "a"), "image"),
+	)
+	AssertSetEquals(r, got, want)
+	expected := `sets differ:
+missing:
+  .spec.containers[name="b"].image
+unexpected:
+  .metadata.labels.tier`
+	if len(r.failures) != 1 || r.failures[0] != expected {
+		t.Errorf("expected failure:\n%v\ngot:\n%v", expected, r.failures)
+	}
+
+	r.failures = nil
+	AssertSetEquals(r, nil, fieldpath.NewSet(_P("spec")))
+	expected = `sets differ:
+missing:
+  .spec`
+	if len(r.failures) != 1 || r.failures[0] != expected {
+		t.Errorf("expected failure:\n%v\ngot:\n%v", expected, r.failures)
+	}
+}
@@ -121,7 +121,7 @@ func (w *objectWalker) GuessBestListPathElement(index int, item value.Value) Pat
 			continue
 		}
 		// only accept primitive/scalar types as keys.
-		if f.IsNull() || f.IsMap() || f.IsList() {
+		if !f.IsScalar() {
 			continue
 		}
 		keys = append(keys, value.Field{Name: name, Value: f})
@@ -16,15 +16,36 @@ package fieldpath
 import (
 	"fmt"
 	"strings"
+	"time"
+
+	"sigs.k8s.io/structured-merge-diff/v4/schema"
 )
 
 // APIVersion describes the version of an object or of a fieldset.
 type APIVersion string
 
+// Operation is the type of operation that last wrote a VersionedSet.
+type Operation string
+
+const (
+	// ApplyOperation is the operation of a manager that applied its
+	// configuration.
+	ApplyOperation = Operation("Apply")
+	// UpdateOperation is the operation of a manager that updated the
+	// object.
+	UpdateOperation = Operation("Update")
+)
+
 type VersionedSet interface {
 	Set() *Set
 	APIVersion() APIVersion
 	Applied() bool
+	// Operation returns ApplyOperation if the set was applied, and
+	// UpdateOperation otherwise.
+	Operation() Operation
+	// Time returns when the set was last written, or the zero time if
+	// unknown.
+	Time() time.Time
 }
 
 // VersionedSet associates a version to a set.
@@ -32,6 +53,7 @@ type versionedSet struct {
 	set        *Set
 	apiVersion APIVersion
 	applied    bool
+	time       time.Time
 }
 
 func NewVersionedSet(set *Set, apiVersion APIVersion, applied bool) VersionedSet {
@@ -42,6 +64,17 @@ func NewVersionedSet(set *Set, apiVersion APIVersion, applied bool) VersionedSet
 	}
 }
 
+// NewVersionedSetAt is like NewVersionedSet, but also records when the
+// set was written.
+func NewVersionedSetAt(set *Set, apiVersion APIVersion, applied bool, t time.Time) VersionedSet {
+	return versionedSet{
+		set:        set,
+		apiVersion: apiVersion,
+		applied:    applied,
+		time:       t,
+	}
+}
+
 func (v versionedSet) Set() *Set {
 	return v.set
 }
@@ -54,12 +87,23 @@ func (v versionedSet) Applied() bool {
 	return v.applied
 }
 
+func (v versionedSet) Operation() Operation {
+	if v.applied {
+		return ApplyOperation
+	}
+	return UpdateOperation
+}
+
+func (v versionedSet) Time() time.Time {
+	return v.time
+}
+
 // ManagedFields is a map from manager to VersionedSet (what they own in
 // what version).
 type ManagedFields map[string]VersionedSet
 
 // Equals returns true if the two managedfields are the same, false
-// otherwise.
+// otherwise. The time of the sets is not compared.
 func (lhs ManagedFields) Equals(rhs ManagedFields) bool {
 	if len(lhs) != len(rhs) {
 		return false
@@ -132,12 +176,34 @@ func (lhs ManagedFields) Difference(rhs ManagedFields) ManagedFields {
 	return diff
 }
 
+// CapToSchema returns a copy of the managed fields where the set of each
+// manager is capped to the paths that can be resolved in the schema from
+// type tr (see Set.CapToSchema). Managers left with an empty set are
+// dropped. The sets are expected to be of the version the schema describes.
+func (lhs ManagedFields) CapToSchema(sc *schema.Schema, tr schema.TypeRef) (ManagedFields, error) {
+	if _, ok := sc.Resolve(tr); !ok {
+		return nil, fmt.Errorf("no type found matching: %v", tr)
+	}
+	out := ManagedFields{}
+	for manager, set := range lhs {
+		capped := set.Set().CapToSchema(sc, tr)
+		if capped.Empty() {
+			continue
+		}
+		out[manager] = NewVersionedSetAt(capped, set.APIVersion(), set.Applied(), set.Time())
+	}
+	return out, nil
+}
+
 func (lhs ManagedFields) String() string {
 	s := strings.Builder{}
 	for k, v := range lhs {
 		fmt.Fprintf(&s, "%s:\n", k)
 		fmt.Fprintf(&s, "- Applied: %v\n", v.Applied())
 		fmt.Fprintf(&s, "- APIVersion: %v\n", v.APIVersion())
+		if t := v.Time(); !t.IsZero() {
+			fmt.Fprintf(&s, "- Time: %v\n", t.Format(time.RFC3339))
+		}
 		fmt.Fprintf(&s, "- Set: %v\n", v.Set())
 	}
 	return s.String()
@@ -22,6 +22,8 @@ import (
 	"testing"
 
 	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/schema"
+	yaml "sigs.k8s.io/yaml/goyaml.v2"
 )
 
 var (
@@ -284,3 +286,67 @@ func TestManagersDifference(t *testing.T) {
 		})
 	}
 }
+
+func TestManagersCapToSchema(t *testing.T) {
+	sc := &schema.Schema{}
+	if err := yaml.Unmarshal([]byte(`types:
+- name: type
+  map:
+    fields:
+      - name: numeric
+        type:
+          scalar: numeric
+      - name: string
+        type:
+          scalar: string
+`), sc); err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	name := "type"
+	tr := schema.TypeRef{NamedType: &name}
+
+	managers := fieldpath.ManagedFields{
+		"removed": fieldpath.NewVersionedSet(
+			_NS(_P("bool"), _P("obsolete", "value")),
+			"v1",
+			true,
+		),
+		"mixed": fieldpath.NewVersionedSet(
+			_NS(_P("numeric"), _P("bool")),
+			"v1",
+			false,
+		),
+		"current": fieldpath.NewVersionedSet(
+			_NS(_P("string")),
+			"v1",
+			true,
+		),
+	}
+	expected := fieldpath.ManagedFields{
+		"mixed": fieldpath.NewVersionedSet(
+			_NS(_P("numeric")),
+			"v1",
+			false,
+		),
+		"current": fieldpath.NewVersionedSet(
+			_NS(_P("string")),
+			"v1",
+			true,
+		),
+	}
+	got, err := managers.CapToSchema(sc, tr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.Equals(expected) {
+		t.Errorf("expected:\n%v\ngot:\n%v", expected, got)
+	}
+	if _, ok := managers["removed"]; !ok {
+		t.Errorf("expected the original managers to be left untouched")
+	}
+
+	missing := "missing"
+	if _, err := managers.CapToSchema(sc, schema.TypeRef{NamedType: &missing}); err == nil {
+		t.Errorf("expected an error for a missing type")
+	}
+}
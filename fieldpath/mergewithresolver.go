@@ -0,0 +1,78 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldpath
+
+import (
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// MergeWithResolver merges rhs into lhs without the help of a schema. Maps
+// are merged recursively, key by key. Everything else (scalars, lists, or
+// values whose types differ on each side) is treated as a leaf: if only one
+// side is set, that side is kept, if both sides are equal, lhs is kept, and
+// otherwise resolve is called with the path of the leaf to decide which value
+// ends up in the result. If resolve returns nil, the leaf is dropped.
+//
+// The path passed to resolve will be reused so make a copy if you wish to
+// keep it.
+func MergeWithResolver(lhs, rhs value.Value, resolve func(path Path, l, r value.Value) value.Value) value.Value {
+	w := resolvingMerger{
+		allocator: value.NewFreelistAllocator(),
+		resolve:   resolve,
+	}
+	out, ok := w.merge(Path{}, lhs, rhs)
+	if !ok {
+		return nil
+	}
+	return value.NewValueInterface(out)
+}
+
+type resolvingMerger struct {
+	allocator value.Allocator
+	resolve   func(path Path, l, r value.Value) value.Value
+}
+
+func (w *resolvingMerger) merge(path Path, lhs, rhs value.Value) (interface{}, bool) {
+	switch {
+	case lhs == nil && rhs == nil:
+		return nil, false
+	case lhs == nil:
+		return rhs.Unstructured(), true
+	case rhs == nil:
+		return lhs.Unstructured(), true
+	case lhs.IsMap() && rhs.IsMap():
+		lm := lhs.AsMapUsing(w.allocator)
+		defer w.allocator.Free(lm)
+		rm := rhs.AsMapUsing(w.allocator)
+		defer w.allocator.Free(rm)
+		out := make(map[string]interface{}, lm.Length())
+		value.MapZipUsing(w.allocator, lm, rm, value.Unordered, func(key string, l, r value.Value) bool {
+			if v, ok := w.merge(append(path, PathElement{FieldName: &key}), l, r); ok {
+				out[key] = v
+			}
+			return true
+		})
+		return out, true
+	case value.EqualsUsing(w.allocator, lhs, rhs):
+		return lhs.Unstructured(), true
+	}
+	v := w.resolve(path, lhs, rhs)
+	if v == nil {
+		return nil, false
+	}
+	return v.Unstructured(), true
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldpath
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+	yaml "sigs.k8s.io/yaml/goyaml.v2"
+)
+
+func TestMergeWithResolver(t *testing.T) {
+	maxNumeric := func(_ Path, l, r value.Value) value.Value {
+		if !(l.IsInt() || l.IsFloat()) || !(r.IsInt() || r.IsFloat()) {
+			return r
+		}
+		if value.Compare(l, r) > 0 {
+			return l
+		}
+		return r
+	}
+	preferRHS := func(_ Path, _, r value.Value) value.Value {
+		return r
+	}
+
+	table := []struct {
+		name     string
+		lhs      string
+		rhs      string
+		resolve  func(Path, value.Value, value.Value) value.Value
+		expected string
+		conflict *Set
+	}{
+		{
+			name:     "max-numeric",
+			lhs:      `{"a": 5, "b": {"c": 1.5, "d": "x"}, "e": [1, 2]}`,
+			rhs:      `{"a": 3, "b": {"c": 2, "f": true}, "e": [1, 2]}`,
+			resolve:  maxNumeric,
+			expected: `{"a": 5, "b": {"c": 2, "d": "x", "f": true}, "e": [1, 2]}`,
+			conflict: NewSet(MakePathOrDie("a"), MakePathOrDie("b", "c")),
+		},
+		{
+			name:     "prefer-rhs",
+			lhs:      `{"a": 5, "b": {"c": "old", "d": "x"}, "e": [1, 2]}`,
+			rhs:      `{"a": 3, "b": {"c": "new"}, "e": [3]}`,
+			resolve:  preferRHS,
+			expected: `{"a": 3, "b": {"c": "new", "d": "x"}, "e": [3]}`,
+			conflict: NewSet(MakePathOrDie("a"), MakePathOrDie("b", "c"), MakePathOrDie("e")),
+		},
+		{
+			name:     "type-mismatch",
+			lhs:      `{"a": {"b": 1}}`,
+			rhs:      `{"a": "b"}`,
+			resolve:  preferRHS,
+			expected: `{"a": "b"}`,
+			conflict: NewSet(MakePathOrDie("a")),
+		},
+		{
+			name: "drop-leaf",
+			lhs:  `{"a": 1, "b": 2}`,
+			rhs:  `{"a": 2, "b": 2}`,
+			resolve: func(Path, value.Value, value.Value) value.Value {
+				return nil
+			},
+			expected: `{"b": 2}`,
+			conflict: NewSet(MakePathOrDie("a")),
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			conflicts := NewSet()
+			resolve := func(p Path, l, r value.Value) value.Value {
+				conflicts.Insert(p.Copy())
+				return tt.resolve(p, l, r)
+			}
+			got := MergeWithResolver(mustParse(t, tt.lhs), mustParse(t, tt.rhs), resolve)
+			if expected := mustParse(t, tt.expected); !value.Equals(got, expected) {
+				t.Errorf("expected %v, got %v", value.ToString(expected), value.ToString(got))
+			}
+			if !conflicts.Equals(tt.conflict) {
+				t.Errorf("expected resolver to be called for\n%v\nbut got\n%v", tt.conflict, conflicts)
+			}
+		})
+	}
+}
+
+func mustParse(t *testing.T, s string) value.Value {
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("couldn't parse: %v", err)
+	}
+	return value.NewValueInterface(v)
+}
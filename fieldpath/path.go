@@ -48,6 +48,15 @@ func (fp Path) Equals(fp2 Path) bool {
 	return true
 }
 
+// HasPrefix returns true if the first elements of fp are equivalent to
+// prefix. Every path has the empty path as a prefix, and itself.
+func (fp Path) HasPrefix(prefix Path) bool {
+	if len(fp) < len(prefix) {
+		return false
+	}
+	return fp[:len(prefix)].Equals(prefix)
+}
+
 // Less provides a lexical order for Paths.
 func (fp Path) Compare(rhs Path) int {
 	i := 0
@@ -78,6 +87,47 @@ func (fp Path) Copy() Path {
 	return new
 }
 
+// Parent returns a copy of the path without its last element, and whether
+// the path had a parent at all (i.e. was not empty).
+func (fp Path) Parent() (Path, bool) {
+	if len(fp) == 0 {
+		return nil, false
+	}
+	return fp[:len(fp)-1].Copy(), true
+}
+
+// Last returns a copy of the last element of the path, and false if the path
+// is empty. The copy doesn't share storage with the path.
+func (fp Path) Last() (PathElement, bool) {
+	if len(fp) == 0 {
+		return PathElement{}, false
+	}
+	return copyPathElement(fp[len(fp)-1]), true
+}
+
+// copyPathElement returns a copy of pe whose pointers don't alias pe's.
+func copyPathElement(pe PathElement) PathElement {
+	var out PathElement
+	if pe.FieldName != nil {
+		name := *pe.FieldName
+		out.FieldName = &name
+	}
+	if pe.Key != nil {
+		key := make(value.FieldList, len(*pe.Key))
+		copy(key, *pe.Key)
+		out.Key = &key
+	}
+	if pe.Value != nil {
+		v := *pe.Value
+		out.Value = &v
+	}
+	if pe.Index != nil {
+		index := *pe.Index
+		out.Index = &index
+	}
+	return out
+}
+
 // MakePath constructs a Path. The parts may be PathElements, ints, strings.
 func MakePath(parts ...interface{}) (Path, error) {
 	var fp Path
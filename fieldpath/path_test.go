@@ -62,3 +62,85 @@ func TestPathString(t *testing.T) {
 		})
 	}
 }
+
+func TestPathParentAndLast(t *testing.T) {
+	key := KeyByFields("name", "a", "port", 80)
+	fp := MakePathOrDie("spec", "containers", key)
+
+	parent, ok := fp.Parent()
+	if !ok {
+		t.Fatalf("expected %v to have a parent", fp)
+	}
+	if e := MakePathOrDie("spec", "containers"); !parent.Equals(e) {
+		t.Errorf("expected parent %v, got %v", e, parent)
+	}
+	last, ok := fp.Last()
+	if !ok {
+		t.Fatalf("expected %v to have a last element", fp)
+	}
+	if e := (PathElement{Key: key}); !last.Equals(e) {
+		t.Errorf("expected last element %v, got %v", e, last)
+	}
+	// Modifying the last element must not affect the original path.
+	(*last.Key)[0].Value = _V("b")
+	if e := MakePathOrDie("spec", "containers", KeyByFields("name", "a", "port", 80)); !fp.Equals(e) {
+		t.Errorf("expected %v to be unchanged, got %v", e, fp)
+	}
+	indexed := MakePathOrDie("spec", 0)
+	last, _ = indexed.Last()
+	*last.Index = 1
+	if e := MakePathOrDie("spec", 0); !indexed.Equals(e) {
+		t.Errorf("expected %v to be unchanged, got %v", e, indexed)
+	}
+
+	// Appending to the parent must not affect the original path.
+	parent = append(parent, PathElement{FieldName: strptr("other")})
+	if e := MakePathOrDie("spec", "containers", key); !fp.Equals(e) {
+		t.Errorf("expected %v to be unchanged, got %v", e, fp)
+	}
+
+	grandparent, ok := MakePathOrDie("spec").Parent()
+	if !ok || len(grandparent) != 0 {
+		t.Errorf("expected single element path to have an empty parent, got %v, %v", grandparent, ok)
+	}
+	if _, ok := (Path{}).Parent(); ok {
+		t.Errorf("expected empty path to have no parent")
+	}
+	if _, ok := (Path{}).Last(); ok {
+		t.Errorf("expected empty path to have no last element")
+	}
+}
+
+func TestPathEqualsAndHasPrefix(t *testing.T) {
+	base := MakePathOrDie("spec", "containers", KeyByFields("name", "a"), 0)
+	table := []struct {
+		name      string
+		other     Path
+		equals    bool
+		hasPrefix bool
+	}{
+		{"same", MakePathOrDie("spec", "containers", KeyByFields("name", "a"), 0), true, true},
+		{"empty", Path{}, false, true},
+		{"parent", MakePathOrDie("spec", "containers", KeyByFields("name", "a")), false, true},
+		{"longer", MakePathOrDie("spec", "containers", KeyByFields("name", "a"), 0, "x"), false, false},
+		{"field-mismatch", MakePathOrDie("spec", "initContainers", KeyByFields("name", "a"), 0), false, false},
+		{"key-mismatch", MakePathOrDie("spec", "containers", KeyByFields("name", "b"), 0), false, false},
+		{"index-mismatch", MakePathOrDie("spec", "containers", KeyByFields("name", "a"), 1), false, false},
+		{"index-vs-field", MakePathOrDie("spec", "containers", KeyByFields("name", "a"), "0"), false, false},
+		{"key-vs-field", MakePathOrDie("spec", "containers", "name"), false, false},
+		{"key-vs-index", MakePathOrDie("spec", "containers", 0), false, false},
+	}
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := base.Equals(tt.other); got != tt.equals {
+				t.Errorf("expected %v.Equals(%v) to be %v", base, tt.other, tt.equals)
+			}
+			if got := tt.other.Equals(base); got != tt.equals {
+				t.Errorf("expected %v.Equals(%v) to be %v", tt.other, base, tt.equals)
+			}
+			if got := base.HasPrefix(tt.other); got != tt.hasPrefix {
+				t.Errorf("expected %v.HasPrefix(%v) to be %v", base, tt.other, tt.hasPrefix)
+			}
+		})
+	}
+}
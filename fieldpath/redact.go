@@ -0,0 +1,103 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldpath
+
+import (
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// Redact returns a copy of v where the values at the paths of secrets are
+// replaced by replacement, e.g. to log objects without their secret data.
+// No schema is needed: map fields are matched by name, and list items by
+// index, by key fields (for items that are maps) or by value. Paths of
+// secrets that don't exist in v are ignored.
+func Redact(v value.Value, secrets *Set, replacement value.Value) value.Value {
+	if v == nil || secrets == nil {
+		return v
+	}
+	return value.NewValueInterface(redact(v, secrets, replacement.Unstructured()))
+}
+
+func redact(v value.Value, secrets *Set, replacement interface{}) interface{} {
+	switch {
+	case v.IsMap():
+		out := map[string]interface{}{}
+		v.AsMap().Iterate(func(key string, child value.Value) bool {
+			out[key] = redactChild(PathElement{FieldName: &key}, child, secrets, replacement)
+			return true
+		})
+		return out
+	case v.IsList():
+		l := v.AsList()
+		out := make([]interface{}, l.Length())
+		for i := range out {
+			child := l.At(i)
+			out[i] = child.Unstructured()
+			// Find the path element that refers to the item, if any.
+			var pe *PathElement
+			match := func(candidate PathElement) {
+				if pe == nil && matchesListItem(candidate, i, child) {
+					candidate := candidate
+					pe = &candidate
+				}
+			}
+			secrets.Members.Iterate(match)
+			secrets.Children.Iterate(match)
+			if pe != nil {
+				out[i] = redactChild(*pe, child, secrets, replacement)
+			}
+		}
+		return out
+	default:
+		return v.Unstructured()
+	}
+}
+
+// redactChild redacts child, found at pe.
+func redactChild(pe PathElement, child value.Value, secrets *Set, replacement interface{}) interface{} {
+	if secrets.Members.Has(pe) {
+		return replacement
+	}
+	if childSecrets, ok := secrets.Children.Get(pe); ok {
+		return redact(child, childSecrets, replacement)
+	}
+	return child.Unstructured()
+}
+
+// matchesListItem returns true if pe refers to item, the i-th item of a
+// list.
+func matchesListItem(pe PathElement, i int, item value.Value) bool {
+	switch {
+	case pe.Index != nil:
+		return *pe.Index == i
+	case pe.Value != nil:
+		return value.Equals(*pe.Value, item)
+	case pe.Key != nil:
+		if !item.IsMap() {
+			return false
+		}
+		m := item.AsMap()
+		for _, field := range *pe.Key {
+			fv, ok := m.Get(field.Name)
+			if !ok || !value.Equals(field.Value, fv) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
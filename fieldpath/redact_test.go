@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldpath
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+func TestRedact(t *testing.T) {
+	const document = `{
+  "kind": "Secret",
+  "data": {"password": "hunter2", "username": "admin"},
+  "spec": {
+    "token": {"value": "abc", "expires": 10},
+    "env": [{"name": "KEY", "value": "s3cr3t"}, {"name": "HOME", "value": "/root"}],
+    "args": ["--verbose", "--password=hunter2"]
+  }
+}`
+	object := mustParse(t, document)
+	secrets := NewSet(
+		MakePathOrDie("data", "password"),
+		MakePathOrDie("spec", "token"),
+		MakePathOrDie("spec", "env", KeyByFields("name", "KEY"), "value"),
+		MakePathOrDie("spec", "args", 1),
+		MakePathOrDie("spec", "missing"),
+	)
+	expected := mustParse(t, `{
+  "kind": "Secret",
+  "data": {"password": "<redacted>", "username": "admin"},
+  "spec": {
+    "token": "<redacted>",
+    "env": [{"name": "KEY", "value": "<redacted>"}, {"name": "HOME", "value": "/root"}],
+    "args": ["--verbose", "<redacted>"]
+  }
+}`)
+
+	got := Redact(object, secrets, value.NewValueInterface("<redacted>"))
+	if !value.Equals(expected, got) {
+		t.Errorf("expected\n%v\ngot\n%v", value.ToString(expected), value.ToString(got))
+	}
+	if !value.Equals(object, mustParse(t, document)) {
+		t.Errorf("expected object to be unchanged, got %v", value.ToString(object))
+	}
+}
+
+func TestRedactSetItems(t *testing.T) {
+	object := mustParse(t, `{"tokens": ["public", "private"]}`)
+	secrets := NewSet(MakePathOrDie("tokens", value.NewValueInterface("private")))
+	expected := mustParse(t, `{"tokens": ["public", null]}`)
+	if got := Redact(object, secrets, value.NewValueInterface(nil)); !value.Equals(expected, got) {
+		t.Errorf("expected\n%v\ngot\n%v", value.ToString(expected), value.ToString(got))
+	}
+}
@@ -124,6 +124,9 @@ func SerializePathElement(pe PathElement) (string, error) {
 func serializePathElementToWriter(w io.Writer, pe PathElement) error {
 	stream := writePool.BorrowStream(w)
 	defer writePool.ReturnStream(stream)
+	// Writing the separator shrinks the buffer of the stream, keep it to
+	// restore its full capacity once done.
+	buf := stream.Buffer()
 	switch {
 	case pe.FieldName != nil:
 		if _, err := stream.Write(peFieldSepBytes); err != nil {
@@ -157,12 +160,11 @@ func serializePathElementToWriter(w io.Writer, pe PathElement) error {
 	default:
 		return errors.New("invalid PathElement")
 	}
-	b := stream.Buffer()
 	err := stream.Flush()
 	// Help jsoniter manage its buffers--without this, the next
 	// use of the stream is likely to require an allocation. Look
 	// at the jsoniter stream code to understand why. They were probably
 	// optimizing for folks using the buffer directly.
-	stream.SetBuffer(b[:0])
+	stream.SetBuffer(buf[:0])
 	return err
 }
@@ -79,16 +79,26 @@ func (r *reusableBuilder) reset() *bytes.Buffer {
 func (s *Set) emitContentsV1(includeSelf bool, stream *jsoniter.Stream, r *reusableBuilder) error {
 	mi, ci := 0, 0
 	first := true
-	preWrite := func() {
+	// preWrite flushes the stream as needed before each entry, so that
+	// sets with many members don't get buffered entirely.
+	preWrite := func() error {
+		if len(stream.Buffer()) > 4096 {
+			if err := stream.Flush(); err != nil {
+				return err
+			}
+		}
 		if first {
 			first = false
-			return
+			return nil
 		}
 		stream.WriteMore()
+		return nil
 	}
 
 	if includeSelf && !(len(s.Members.members) == 0 && len(s.Children.members) == 0) {
-		preWrite()
+		if err := preWrite(); err != nil {
+			return err
+		}
 		stream.WriteObjectField(".")
 		stream.WriteEmptyObject()
 	}
@@ -98,7 +108,9 @@ func (s *Set) emitContentsV1(includeSelf bool, stream *jsoniter.Stream, r *reusa
 		cpe := s.Children.members[ci].pathElement
 
 		if c := mpe.Compare(cpe); c < 0 {
-			preWrite()
+			if err := preWrite(); err != nil {
+				return err
+			}
 			if err := serializePathElementToWriter(r.reset(), mpe); err != nil {
 				return err
 			}
@@ -106,7 +118,9 @@ func (s *Set) emitContentsV1(includeSelf bool, stream *jsoniter.Stream, r *reusa
 			stream.WriteEmptyObject()
 			mi++
 		} else if c > 0 {
-			preWrite()
+			if err := preWrite(); err != nil {
+				return err
+			}
 			if err := serializePathElementToWriter(r.reset(), cpe); err != nil {
 				return err
 			}
@@ -118,7 +132,9 @@ func (s *Set) emitContentsV1(includeSelf bool, stream *jsoniter.Stream, r *reusa
 			stream.WriteObjectEnd()
 			ci++
 		} else {
-			preWrite()
+			if err := preWrite(); err != nil {
+				return err
+			}
 			if err := serializePathElementToWriter(r.reset(), cpe); err != nil {
 				return err
 			}
@@ -136,7 +152,9 @@ func (s *Set) emitContentsV1(includeSelf bool, stream *jsoniter.Stream, r *reusa
 	for mi < len(s.Members.members) {
 		mpe := s.Members.members[mi]
 
-		preWrite()
+		if err := preWrite(); err != nil {
+			return err
+		}
 		if err := serializePathElementToWriter(r.reset(), mpe); err != nil {
 			return err
 		}
@@ -148,7 +166,9 @@ func (s *Set) emitContentsV1(includeSelf bool, stream *jsoniter.Stream, r *reusa
 	for ci < len(s.Children.members) {
 		cpe := s.Children.members[ci].pathElement
 
-		preWrite()
+		if err := preWrite(); err != nil {
+			return err
+		}
 		if err := serializePathElementToWriter(r.reset(), cpe); err != nil {
 			return err
 		}
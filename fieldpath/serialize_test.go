@@ -19,6 +19,7 @@ package fieldpath
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
 	"strings"
 	"testing"
 )
@@ -87,3 +88,34 @@ func TestDropUnknown(t *testing.T) {
 		t.Errorf("Failed;\ngot:  %s\nwant: %s\n", b, expect)
 	}
 }
+
+func BenchmarkSetToJSONStream(b *testing.B) {
+	wide := NewSet()
+	for i := 0; i < 50000; i++ {
+		wide.Insert(MakePathOrDie("items", KeyByFields("name", fmt.Sprintf("item-%v", i))))
+	}
+	deep := NewSet()
+	for i := 0; i < 5000; i++ {
+		item := KeyByFields("name", fmt.Sprintf("item-%v", i))
+		for _, field := range []string{"image", "command", "args", "workingDir"} {
+			deep.Insert(MakePathOrDie("spec", "template", "spec", "containers", item, field))
+		}
+		deep.Insert(MakePathOrDie("spec", "template", "spec", "containers", item, "resources", "limits", "cpu"))
+	}
+	for _, bc := range []struct {
+		name string
+		set  *Set
+	}{
+		{"wide", wide},
+		{"deep", deep},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := bc.set.ToJSONStream(ioutil.Discard); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -35,6 +35,9 @@ type Set struct {
 	// members of the set. Appearance in this list does not imply membership.
 	// Note: this is a tree, not an arbitrary graph.
 	Children SetNodeMap
+
+	// bloom, if set, has all the members of the set (see BuildBloom).
+	bloom *bloomFilter
 }
 
 // NewSet makes a set from a list of paths.
@@ -54,6 +57,9 @@ func (s *Set) Insert(p Path) {
 		// track top-level ownership.
 		return
 	}
+	if s.bloom != nil {
+		s.bloom.insert(p)
+	}
 	for {
 		if len(p) == 1 {
 			s.Members.Insert(p[0])
@@ -138,6 +144,63 @@ func (s *Set) EnsureNamedFieldsAreMembers(sc *schema.Schema, tr schema.TypeRef)
 	}
 }
 
+// CapToSchema returns the subset of s made of the paths that can be resolved
+// in the schema from type tr: fields must be declared by their map, or
+// allowed by its element type, and list elements must be found in lists,
+// with keys made of the list's key fields. Paths that go through a type
+// missing from the schema are dropped.
+func (s *Set) CapToSchema(sc *schema.Schema, tr schema.TypeRef) *Set {
+	atom, _ := sc.Resolve(tr)
+	members := PathElementSet{}
+	for _, pe := range s.Members.members {
+		if _, ok := resolveChildType(atom, pe); ok {
+			members.members = append(members.members, pe)
+		}
+	}
+	return &Set{
+		Members:  members,
+		Children: *s.Children.CapToSchema(sc, tr),
+	}
+}
+
+// resolveChildType returns the type of the child pe of a value of type
+// atom, and false if pe can't be found in such a value.
+func resolveChildType(atom schema.Atom, pe PathElement) (schema.TypeRef, bool) {
+	switch {
+	case pe.FieldName != nil:
+		if atom.Map == nil {
+			return schema.TypeRef{}, false
+		}
+		if sf, ok := atom.Map.FindField(*pe.FieldName); ok {
+			return sf.Type, true
+		}
+		return atom.Map.ElementType, atom.Map.ElementType != schema.TypeRef{}
+	case pe.Key != nil:
+		if atom.List == nil || len(atom.List.Keys) == 0 {
+			return schema.TypeRef{}, false
+		}
+		for _, f := range *pe.Key {
+			found := false
+			for _, key := range atom.List.Keys {
+				if key == f.Name {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return schema.TypeRef{}, false
+			}
+		}
+		return atom.List.ElementType, true
+	case pe.Value != nil, pe.Index != nil:
+		if atom.List == nil {
+			return schema.TypeRef{}, false
+		}
+		return atom.List.ElementType, true
+	}
+	return schema.TypeRef{}, false
+}
+
 // MakePrefixMatcherOrDie is the same as PrefixMatcher except it panics if parts can't be
 // turned into a SetMatcher.
 func MakePrefixMatcherOrDie(parts ...interface{}) *SetMatcher {
@@ -335,6 +398,26 @@ func (s *Set) Size() int {
 	return s.Members.Size() + s.Children.Size()
 }
 
+// LeafCount returns the number of leaf members of the set, i.e. the size
+// of Leaves, without building it.
+func (s *Set) LeafCount() int {
+	count := 0
+	ic := 0
+	for _, member := range s.Members.members {
+		for ic < len(s.Children.members) && s.Children.members[ic].pathElement.Less(member) {
+			ic++
+		}
+		if ic < len(s.Children.members) && s.Children.members[ic].pathElement.Equals(member) {
+			continue
+		}
+		count++
+	}
+	for _, n := range s.Children.members {
+		count += n.set.LeafCount()
+	}
+	return count
+}
+
 // Empty returns true if there are no members of the set. It is a separate
 // function from Size since it's common to check whether size > 0, and
 // potentially much faster to return as soon as a single element is found.
@@ -351,6 +434,9 @@ func (s *Set) Has(p Path) bool {
 		// No one owns "the entire object"
 		return false
 	}
+	if s.bloom != nil && !s.bloom.mayHave(p) {
+		return false
+	}
 	for {
 		if len(p) == 1 {
 			return s.Members.Has(p[0])
@@ -378,6 +464,56 @@ func (s *Set) String() string {
 	return strings.Join(elements, "\n")
 }
 
+// StringIndented returns the set as a tree, one path element per line in
+// sorted order, with the elements of children indented under their parent
+// element by one more indent. Elements that are only prefixes of members,
+// rather than members themselves, end with a colon, e.g.
+//
+//	.spec:
+//	  .containers:
+//	    [name="app"]
+//	      .image
+//	  .replicas
+func (s *Set) StringIndented(indent string) string {
+	b := strings.Builder{}
+	s.writeIndented(&b, indent, "")
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (s *Set) writeIndented(b *strings.Builder, indent, prefix string) {
+	members, children := s.Members.members, s.Children.members
+	for len(members) > 0 || len(children) > 0 {
+		var pe PathElement
+		var child *Set
+		member := false
+		switch {
+		case len(children) == 0:
+			pe, member, members = members[0], true, members[1:]
+		case len(members) == 0:
+			pe, child, children = children[0].pathElement, children[0].set, children[1:]
+		default:
+			switch c := members[0].Compare(children[0].pathElement); {
+			case c < 0:
+				pe, member, members = members[0], true, members[1:]
+			case c > 0:
+				pe, child, children = children[0].pathElement, children[0].set, children[1:]
+			default:
+				pe, member, members = members[0], true, members[1:]
+				child, children = children[0].set, children[1:]
+			}
+		}
+		b.WriteString(prefix)
+		b.WriteString(pe.String())
+		if !member {
+			b.WriteString(":")
+		}
+		b.WriteString("\n")
+		if child != nil {
+			child.writeIndented(b, indent, prefix+indent)
+		}
+	}
+}
+
 // Iterate calls f once for each field that is a member of the set (preorder
 // DFS). The path passed to f will be reused so make a copy if you wish to keep
 // it.
@@ -385,6 +521,17 @@ func (s *Set) Iterate(f func(Path)) {
 	s.iteratePrefix(Path{}, f)
 }
 
+// ByDepth returns the paths of the set grouped by their number of
+// elements, e.g. to render the set as a tree one level at a time. Within a
+// group, paths are in the order of Iterate.
+func (s *Set) ByDepth() map[int][]Path {
+	depths := map[int][]Path{}
+	s.Iterate(func(p Path) {
+		depths[len(p)] = append(depths[len(p)], p.Copy())
+	})
+	return depths
+}
+
 func (s *Set) iteratePrefix(prefix Path, f func(Path)) {
 	s.Members.Iterate(func(pe PathElement) { f(append(prefix, pe)) })
 	s.Children.iteratePrefix(prefix, f)
@@ -402,6 +549,13 @@ func (s *Set) WithPrefix(pe PathElement) *Set {
 
 // Leaves returns a set containing only the leaf paths
 // of a set.
+//
+// The leaves are also the frontier of the set: its topmost members that
+// imply everything below them, i.e. whose subtrees are owned as a whole.
+// Only scalars and atomic maps and lists imply their subtree, and sets hold
+// them as members without children. Owning a granular map, or an
+// associative list item, doesn't imply owning its fields, so the frontier
+// continues with the members below it.
 func (s *Set) Leaves() *Set {
 	leaves := PathElementSet{}
 	im := 0
@@ -434,6 +588,89 @@ outer:
 	}
 }
 
+// Rename returns a copy of the set with fields renamed. renames maps the
+// old name of a field to its new name, where the old name is the list of
+// field names leading to the field joined with dots, ignoring list items:
+// "replicas" renames the top-level field, "spec.containers.image" renames
+// the image field of every item of the containers list. Other path
+// elements, including the keys of list items, are left intact.
+func (s *Set) Rename(renames map[string]string) *Set {
+	out := NewSet()
+	s.Iterate(func(p Path) {
+		renamed := make(Path, len(p))
+		prefix := ""
+		for i, pe := range p {
+			renamed[i] = pe
+			if pe.FieldName == nil {
+				continue
+			}
+			if prefix != "" {
+				prefix += "."
+			}
+			// Renames are looked up by old names.
+			prefix += *pe.FieldName
+			if name, ok := renames[prefix]; ok {
+				renamed[i] = PathElement{FieldName: &name}
+			}
+		}
+		out.Insert(renamed)
+	})
+	return out
+}
+
+// Filter returns a copy of the set with only the paths for which keep
+// returns true. The paths that are dropped don't affect the paths below
+// them, which are kept or dropped on their own. The path passed to keep is
+// reused so make a copy if you wish to keep it.
+func (s *Set) Filter(keep func(p Path) bool) *Set {
+	return s.filterPrefix(Path{}, keep)
+}
+
+func (s *Set) filterPrefix(prefix Path, keep func(Path) bool) *Set {
+	out := &Set{}
+	for _, pe := range s.Members.members {
+		if keep(append(prefix, pe)) {
+			out.Members.members = append(out.Members.members, pe)
+		}
+	}
+	for _, n := range s.Children.members {
+		if c := n.set.filterPrefix(append(prefix, n.pathElement), keep); !c.Empty() {
+			out.Children.members = append(out.Children.members, setNode{
+				pathElement: n.pathElement,
+				set:         c,
+			})
+		}
+	}
+	return out
+}
+
+// PathMatcher is a predicate on paths, to be given to Set.Filter.
+type PathMatcher func(p Path) bool
+
+// MatchPrefix returns a PathMatcher that matches prefix and the paths
+// below it.
+func MatchPrefix(prefix Path) PathMatcher {
+	return func(p Path) bool {
+		return p.HasPrefix(prefix)
+	}
+}
+
+// MatchLeafField returns a PathMatcher that matches the paths whose last
+// element is the field name.
+func MatchLeafField(name string) PathMatcher {
+	return func(p Path) bool {
+		last, ok := p.Last()
+		return ok && last.FieldName != nil && *last.FieldName == name
+	}
+}
+
+// Not returns a PathMatcher that matches the paths that m doesn't match.
+func (m PathMatcher) Not() PathMatcher {
+	return func(p Path) bool {
+		return !m(p)
+	}
+}
+
 // setNode is a pair of PathElement / Set, for the purpose of expressing
 // nested set membership.
 type setNode struct {
@@ -670,6 +907,30 @@ func (s *SetNodeMap) EnsureNamedFieldsAreMembers(sc *schema.Schema, tr schema.Ty
 	}
 }
 
+// CapToSchema returns a SetNodeMap with only the children that can be
+// resolved in the schema, and that aren't empty once capped themselves.
+func (s *SetNodeMap) CapToSchema(sc *schema.Schema, tr schema.TypeRef) *SetNodeMap {
+	var out sortedSetNode
+	atom, _ := sc.Resolve(tr)
+	for _, member := range s.members {
+		childType, ok := resolveChildType(atom, member.pathElement)
+		if !ok {
+			continue
+		}
+		childSet := member.set.CapToSchema(sc, childType)
+		if !childSet.Empty() {
+			out = append(out, setNode{
+				pathElement: member.pathElement,
+				set:         childSet,
+			})
+		}
+	}
+
+	return &SetNodeMap{
+		members: out,
+	}
+}
+
 // FilterIncludeMatches returns a SetNodeMap with only the field paths that match the matcher.
 func (s *SetNodeMap) FilterIncludeMatches(pattern *SetMatcher) *SetNodeMap {
 	if pattern.wildcard {
@@ -251,6 +251,51 @@ func TestSetIterSize(t *testing.T) {
 	}
 }
 
+func TestSetByDepth(t *testing.T) {
+	s := NewSet(
+		MakePathOrDie("foo", 0, "bar", "baz"),
+		MakePathOrDie("foo", 0, "bar", "zot"),
+		MakePathOrDie("foo", 0, "bar"),
+		MakePathOrDie("foo", 0),
+		MakePathOrDie("foo", 1, "bar", "baz"),
+		MakePathOrDie("foo", 1, "bar"),
+		MakePathOrDie("qux", KeyByFields("name", "first")),
+		MakePathOrDie("qux", KeyByFields("name", "first"), "bar"),
+		MakePathOrDie("qux", KeyByFields("name", "second"), "bar"),
+	)
+	// List items own both themselves and their sub-fields, so they appear
+	// at multiple depths.
+	expected := map[int][]Path{
+		2: {
+			MakePathOrDie("foo", 0),
+			MakePathOrDie("qux", KeyByFields("name", "first")),
+		},
+		3: {
+			MakePathOrDie("foo", 0, "bar"),
+			MakePathOrDie("foo", 1, "bar"),
+			MakePathOrDie("qux", KeyByFields("name", "first"), "bar"),
+			MakePathOrDie("qux", KeyByFields("name", "second"), "bar"),
+		},
+		4: {
+			MakePathOrDie("foo", 0, "bar", "baz"),
+			MakePathOrDie("foo", 0, "bar", "zot"),
+			MakePathOrDie("foo", 1, "bar", "baz"),
+		},
+	}
+	got := s.ByDepth()
+	if len(got) != len(expected) {
+		t.Fatalf("expected depths %v, got %v", expected, got)
+	}
+	for depth, paths := range expected {
+		if !NewSet(got[depth]...).Equals(NewSet(paths...)) || len(got[depth]) != len(paths) {
+			t.Errorf("expected paths at depth %v:\n%v\ngot:\n%v", depth, paths, got[depth])
+		}
+	}
+	if got := NewSet().ByDepth(); len(got) != 0 {
+		t.Errorf("expected no depths for an empty set, got %v", got)
+	}
+}
+
 func TestSetEquals(t *testing.T) {
 	table := []struct {
 		a     *Set
@@ -558,9 +603,150 @@ func TestSetLeaves(t *testing.T) {
 			if got := tt.input.Leaves(); !tt.expected.Equals(got) {
 				t.Errorf("expected %v, got %v for input %v", tt.expected, got, tt.input)
 			}
+			if got := tt.input.LeafCount(); got != tt.expected.Size() {
+				t.Errorf("expected %v leaves, got %v for input %v", tt.expected.Size(), got, tt.input)
+			}
+		})
+	}
+
+}
+
+func TestSetLeavesFrontier(t *testing.T) {
+	// A manager owning an atomic selector, a list item it created with
+	// its fields, and a single field of a shared container.
+	s := NewSet(
+		_P("spec"),
+		_P("spec", "selector"),
+		_P("spec", "template", "spec", "containers", KeyByFields("name", "a")),
+		_P("spec", "template", "spec", "containers", KeyByFields("name", "a"), "image"),
+		_P("spec", "template", "spec", "volumes", KeyByFields("name", "v")),
+		_P("spec", "template", "spec", "volumes", KeyByFields("name", "v"), "name"),
+		_P("spec", "template", "spec", "volumes", KeyByFields("name", "v"), "secret", "name"),
+		_P("status", "replicas"),
+	)
+	// Owning the spec or a list item doesn't imply owning their fields,
+	// unlike the atomic selector, so the frontier goes down to the owned
+	// fields while the full enumeration also has their parents.
+	expected := NewSet(
+		_P("spec", "selector"),
+		_P("spec", "template", "spec", "containers", KeyByFields("name", "a"), "image"),
+		_P("spec", "template", "spec", "volumes", KeyByFields("name", "v"), "name"),
+		_P("spec", "template", "spec", "volumes", KeyByFields("name", "v"), "secret", "name"),
+		_P("status", "replicas"),
+	)
+	got := s.Leaves()
+	if !got.Equals(expected) {
+		t.Errorf("expected frontier:\n%v\ngot:\n%v", expected, got)
+	}
+	if s.Size() <= got.Size() {
+		t.Errorf("expected the frontier to be smaller than the set")
+	}
+	// Every path of the set is on the frontier or an ancestor of it.
+	s.Iterate(func(p Path) {
+		found := false
+		got.Iterate(func(f Path) {
+			if len(f) >= len(p) && f[:len(p)].Equals(p) {
+				found = true
+			}
 		})
+		if !found {
+			t.Errorf("path %v isn't on the frontier or above it", p)
+		}
+	})
+
+	// A granular list item without owned fields is on the frontier.
+	s = NewSet(
+		_P("containers", KeyByFields("name", "a")),
+		_P("containers", KeyByFields("name", "b")),
+		_P("containers", KeyByFields("name", "b"), "image"),
+	)
+	expected = NewSet(
+		_P("containers", KeyByFields("name", "a")),
+		_P("containers", KeyByFields("name", "b"), "image"),
+	)
+	if got := s.Leaves(); !got.Equals(expected) {
+		t.Errorf("expected frontier:\n%v\ngot:\n%v", expected, got)
 	}
+}
 
+func TestSetRename(t *testing.T) {
+	input := NewSet(
+		_P("replicas"),
+		_P("spec"),
+		_P("spec", "replicas"),
+		_P("spec", "containers", KeyByFields("name", "a"), "name"),
+		_P("spec", "containers", KeyByFields("name", "a"), "image"),
+		_P("spec", "containers", KeyByFields("name", "b"), "image", "tag"),
+		_P("spec", "ports", 0, "name"),
+	)
+	renames := map[string]string{
+		"spec":                  "template",
+		"spec.replicas":         "size",
+		"spec.containers.image": "img",
+		"spec.containers.name":  "id",
+		"spec.ports.name":       "label",
+	}
+	// List keys keep their field names.
+	expected := NewSet(
+		_P("replicas"),
+		_P("template"),
+		_P("template", "size"),
+		_P("template", "containers", KeyByFields("name", "a"), "id"),
+		_P("template", "containers", KeyByFields("name", "a"), "img"),
+		_P("template", "containers", KeyByFields("name", "b"), "img", "tag"),
+		_P("template", "ports", 0, "label"),
+	)
+	if got := input.Rename(renames); !expected.Equals(got) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if got := input.Rename(nil); !input.Equals(got) {
+		t.Errorf("expected %v, got %v", input, got)
+	}
+}
+
+func TestSetFilter(t *testing.T) {
+	s := NewSet(
+		_P("spec"),
+		_P("spec", "replicas"),
+		_P("spec", "containers", KeyByFields("name", "a"), "name"),
+		_P("spec", "containers", KeyByFields("name", "a"), "image"),
+		_P("status"),
+		_P("status", "replicas"),
+		_P("status", "conditions", KeyByFields("type", "Ready"), "status"),
+	)
+
+	withoutStatus := NewSet(
+		_P("spec"),
+		_P("spec", "replicas"),
+		_P("spec", "containers", KeyByFields("name", "a"), "name"),
+		_P("spec", "containers", KeyByFields("name", "a"), "image"),
+	)
+	if got := s.Filter(MatchPrefix(_P("status")).Not()); !got.Equals(withoutStatus) {
+		t.Errorf("expected %v, got %v", withoutStatus, got)
+	}
+
+	replicas := NewSet(
+		_P("spec", "replicas"),
+		_P("status", "replicas"),
+	)
+	if got := s.Filter(MatchLeafField("replicas")); !got.Equals(replicas) {
+		t.Errorf("expected %v, got %v", replicas, got)
+	}
+
+	// Dropping a member keeps the paths below it.
+	children := s.Difference(NewSet(_P("spec"), _P("status")))
+	if got := s.Filter(func(p Path) bool { return len(p) > 1 }); !got.Equals(children) {
+		t.Errorf("expected %v, got %v", children, got)
+	}
+
+	// Filters compose with the other set operations.
+	if got := s.Filter(MatchLeafField("replicas")).Union(withoutStatus); !got.Equals(withoutStatus.Union(NewSet(_P("status", "replicas")))) {
+		t.Errorf("unexpected union %v", got)
+	}
+
+	if got := s.Filter(func(Path) bool { return false }); !got.Empty() {
+		t.Errorf("expected an empty set, got %v", got)
+	}
 }
 
 func TestSetDifference(t *testing.T) {
@@ -731,6 +917,79 @@ func TestEnsureNamedFieldsAreMembers(t *testing.T) {
 	}
 }
 
+var cappingSchema = func() (*schema.Schema, schema.TypeRef) {
+	sc := &schema.Schema{}
+	name := "type"
+	err := yaml.Unmarshal([]byte(`types:
+- name: type
+  map:
+    fields:
+      - name: value
+        type:
+          scalar: numeric
+      - name: list
+        type:
+          list:
+            elementRelationship: associative
+            keys: ["name"]
+            elementType:
+              namedType: type
+      - name: set
+        type:
+          list:
+            elementRelationship: associative
+            elementType:
+              scalar: string
+      - name: labels
+        type:
+          map:
+            elementType:
+              scalar: string
+      - name: missing
+        type:
+          namedType: missing
+`), &sc)
+	if err != nil {
+		panic(err)
+	}
+	return sc, schema.TypeRef{NamedType: &name}
+}
+
+func TestSetCapToSchema(t *testing.T) {
+	set := NewSet(
+		_P("value"),
+		_P("removed"),
+		_P("removed", "value"),
+		_P("value", "child"),
+		_P("list", KeyByFields("name", "a"), "value"),
+		_P("list", KeyByFields("name", "a"), "removed"),
+		_P("list", KeyByFields("id", "a"), "value"),
+		_P("list", 0),
+		_P("set", _V("a")),
+		_P("labels", "app"),
+		_P("missing", "value"),
+	)
+	expected := NewSet(
+		_P("value"),
+		_P("list", KeyByFields("name", "a"), "value"),
+		_P("list", 0),
+		_P("set", _V("a")),
+		_P("labels", "app"),
+	)
+	got := set.CapToSchema(cappingSchema())
+	if !got.Equals(expected) {
+		t.Errorf("expected %v, got %v (missing: %v/superfluous: %v)",
+			expected,
+			got,
+			expected.Difference(got),
+			got.Difference(expected),
+		)
+	}
+	if got := NewSet(_P("removed", "value")).CapToSchema(cappingSchema()); !got.Empty() {
+		t.Errorf("expected an empty set, got %v", got)
+	}
+}
+
 func TestSetNodeMapIterate(t *testing.T) {
 	set := &SetNodeMap{}
 	toAdd := 5
@@ -1010,3 +1269,49 @@ func TestFilterByPattern(t *testing.T) {
 		})
 	}
 }
+
+func TestSetSizesDontAllocate(t *testing.T) {
+	s := NewSet(
+		_P("root", KeyByFields("name", "a")),
+		_P("root", KeyByFields("name", "a"), "name"),
+		_P("root", KeyByFields("name", "a"), "value", "b"),
+		_P("root", "x", "y"),
+	)
+	if allocs := testing.AllocsPerRun(100, func() { s.Size() }); allocs != 0 {
+		t.Errorf("expected Size not to allocate, got %v allocations", allocs)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { s.LeafCount() }); allocs != 0 {
+		t.Errorf("expected LeafCount not to allocate, got %v allocations", allocs)
+	}
+}
+
+func TestSetStringIndented(t *testing.T) {
+	s := NewSet(
+		_P("spec", "replicas"),
+		_P("spec", "containers", KeyByFields("name", "b"), "image"),
+		_P("spec", "containers", KeyByFields("name", "a")),
+		_P("spec", "containers", KeyByFields("name", "a"), "image"),
+		_P("spec", "containers", KeyByFields("name", "a"), "ports", 0),
+		_P("metadata", "labels", "app"),
+		_P("kind"),
+	)
+	expected := `.kind
+.metadata:
+  .labels:
+    .app
+.spec:
+  .containers:
+    [name="a"]
+      .image
+      .ports:
+        [0]
+    [name="b"]:
+      .image
+  .replicas`
+	if got := s.StringIndented("  "); got != expected {
+		t.Errorf("expected:\n%v\ngot:\n%v", expected, got)
+	}
+	if got := NewSet().StringIndented("  "); got != "" {
+		t.Errorf("expected an empty string for an empty set, got %q", got)
+	}
+}
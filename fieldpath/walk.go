@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldpath
+
+import (
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// Walk calls visit for v and each value within it, depth first, along with
+// their path from v: the fields of maps, in no particular order, and the
+// items of lists, by index. The children of a map or list are skipped if
+// visit returns false for it, e.g. to avoid descending into large subtrees
+// that aren't of interest. The path passed to visit will be reused so make
+// a copy if you wish to keep it.
+//
+// Walk lives in fieldpath rather than value, since value can't depend on
+// fieldpath.
+func Walk(v value.Value, visit func(path Path, v value.Value) (descend bool)) {
+	w := valueWalker{allocator: value.NewFreelistAllocator(), visit: visit}
+	w.walk(Path{}, v)
+}
+
+type valueWalker struct {
+	allocator value.Allocator
+	visit     func(Path, value.Value) bool
+}
+
+func (w *valueWalker) walk(path Path, v value.Value) {
+	if !w.visit(path, v) {
+		return
+	}
+	switch {
+	case v.IsList():
+		l := v.AsListUsing(w.allocator)
+		defer w.allocator.Free(l)
+		iter := l.RangeUsing(w.allocator)
+		defer w.allocator.Free(iter)
+		for iter.Next() {
+			i, item := iter.Item()
+			w.walk(append(path, PathElement{Index: &i}), item)
+		}
+	case v.IsMap():
+		m := v.AsMapUsing(w.allocator)
+		defer w.allocator.Free(m)
+		m.IterateUsing(w.allocator, func(k string, val value.Value) bool {
+			w.walk(append(path, PathElement{FieldName: &k}), val)
+			return true
+		})
+	}
+}
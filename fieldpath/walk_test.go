@@ -0,0 +1,108 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldpath
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+func TestWalk(t *testing.T) {
+	v, err := value.FromYAML([]byte(`
+kind: Pod
+spec:
+  replicas: 1
+  containers:
+  - name: a
+    args: [x]
+status:
+  phase: Running
+  conditions:
+  - type: Ready
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	walk := func(skip string) []string {
+		var visited []string
+		Walk(v, func(p Path, v value.Value) bool {
+			visited = append(visited, p.String())
+			return p.String() != skip
+		})
+		sort.Strings(visited)
+		return visited
+	}
+
+	all := []string{
+		"",
+		".kind",
+		".spec",
+		".spec.containers",
+		".spec.containers[0]",
+		".spec.containers[0].args",
+		".spec.containers[0].args[0]",
+		".spec.containers[0].name",
+		".spec.replicas",
+		".status",
+		".status.conditions",
+		".status.conditions[0]",
+		".status.conditions[0].type",
+		".status.phase",
+	}
+	if got := walk(""); !reflect.DeepEqual(got, []string{""}) {
+		t.Errorf("expected only the root to be visited, got %v", got)
+	}
+	if got := walk("none"); !reflect.DeepEqual(got, all) {
+		t.Errorf("expected %v, got %v", all, got)
+	}
+	// The siblings of a skipped map are still visited.
+	expected := []string{
+		"",
+		".kind",
+		".spec",
+		".spec.containers",
+		".spec.containers[0]",
+		".spec.containers[0].args",
+		".spec.containers[0].args[0]",
+		".spec.containers[0].name",
+		".spec.replicas",
+		".status",
+	}
+	if got := walk(".status"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	// Skipping a list item skips its fields only.
+	expected = []string{
+		"",
+		".kind",
+		".spec",
+		".spec.containers",
+		".spec.containers[0]",
+		".spec.replicas",
+		".status",
+		".status.conditions",
+		".status.conditions[0]",
+		".status.conditions[0].type",
+		".status.phase",
+	}
+	if got := walk(".spec.containers[0]"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
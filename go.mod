@@ -5,6 +5,7 @@ require (
 	github.com/google/gofuzz v1.0.0
 	github.com/json-iterator/go v1.1.12
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
+	google.golang.org/protobuf v1.27.1
 	sigs.k8s.io/yaml v1.4.0
 )
 
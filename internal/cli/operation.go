@@ -111,7 +111,7 @@ func (m merge) Execute(w io.Writer) error {
 
 	out, err := lhs.Merge(rhs)
 	if err != nil {
-		return err
+		return fmt.Errorf("unable to merge %q into %q:\n%v", m.rhs, m.lhs, err)
 	}
 
 	yaml, err := value.ToYAML(out.AsValue())
@@ -0,0 +1,75 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge
+
+import (
+	"fmt"
+	"sort"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+// canonicalManager returns the name that manager is an alias of, if any.
+func (s *Updater) canonicalManager(manager string) string {
+	if canonical, ok := s.managerAliases[manager]; ok {
+		return canonical
+	}
+	return manager
+}
+
+// canonicalizeManagers renames the managers that are aliases to their
+// canonical name, and merges the sets of the managers that end up with the
+// same name. Merged sets are only applied if all of them were applied, and
+// keep the latest time. Sets of different versions can't be merged.
+func (s *Updater) canonicalizeManagers(managers fieldpath.ManagedFields) (fieldpath.ManagedFields, error) {
+	if len(s.managerAliases) == 0 {
+		return managers, nil
+	}
+	// Iterate in order so that errors are deterministic.
+	names := make([]string, 0, len(managers))
+	for manager := range managers {
+		names = append(names, manager)
+	}
+	sort.Strings(names)
+	canonicalized := make(fieldpath.ManagedFields, len(managers))
+	merged := map[string]string{}
+	for _, manager := range names {
+		set := managers[manager]
+		canonical := s.canonicalManager(manager)
+		other, ok := canonicalized[canonical]
+		if !ok {
+			canonicalized[canonical] = set
+			merged[canonical] = manager
+			continue
+		}
+		if other.APIVersion() != set.APIVersion() {
+			return nil, fmt.Errorf("managers %q and %q are both %q but manage fields of different versions (%v and %v)",
+				merged[canonical], manager, canonical, other.APIVersion(), set.APIVersion())
+		}
+		t := other.Time()
+		if set.Time().After(t) {
+			t = set.Time()
+		}
+		canonicalized[canonical] = fieldpath.NewVersionedSetAt(
+			other.Set().Union(set.Set()),
+			set.APIVersion(),
+			other.Applied() && set.Applied(),
+			t,
+		)
+	}
+	return canonicalized, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/merge"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+func TestApplyIfMatches(t *testing.T) {
+	updater := buildUpdater(merge.UpdaterBuilder{})
+	parse := objectParser(t, leafFieldsParser, "v1")
+	live := parse(`{"numeric": 1, "string": "a"}`)
+	managers := fieldpath.ManagedFields{
+		"controller": fieldpath.NewVersionedSet(_NS(_P("numeric"), _P("string")), "v1", false),
+	}
+	config := parse(`{"bool": true}`)
+
+	t.Run("matching", func(t *testing.T) {
+		object, newManagers, conflicts, matched, err := updater.ApplyIfMatches(live, parse(`{"string": "a", "numeric": 1}`), config, "v1", managers.Copy(), "applier", false)
+		if err != nil || conflicts != nil {
+			t.Fatalf("Failed to apply: %v, %v", err, conflicts)
+		}
+		if !matched {
+			t.Fatal("expected the precondition to match")
+		}
+		if expected := parse(`{"numeric": 1, "string": "a", "bool": true}`); !value.Equals(object.AsValue(), expected.AsValue()) {
+			t.Errorf("expected object %v, got %v", expected, object)
+		}
+		if got := newManagers["applier"].Set(); !got.Equals(_NS(_P("bool"))) {
+			t.Errorf("expected applier to own .bool, got %v", got)
+		}
+	})
+
+	t.Run("mismatching", func(t *testing.T) {
+		original := managers.Copy()
+		object, newManagers, conflicts, matched, err := updater.ApplyIfMatches(live, parse(`{"numeric": 2, "string": "a"}`), config, "v1", original, "applier", false)
+		if err != nil || conflicts != nil {
+			t.Fatalf("Failed to apply: %v, %v", err, conflicts)
+		}
+		if matched {
+			t.Fatal("expected the precondition not to match")
+		}
+		if object != nil {
+			t.Errorf("expected no object, got %v", object)
+		}
+		if !newManagers.Equals(managers) || !original.Equals(managers) {
+			t.Errorf("expected managers to be unchanged, got %v", newManagers)
+		}
+	})
+
+	t.Run("conflicting", func(t *testing.T) {
+		_, _, conflicts, matched, err := updater.ApplyIfMatches(live, live, parse(`{"numeric": 3}`), "v1", managers.Copy(), "applier", false)
+		if err != nil {
+			t.Fatalf("Failed to apply: %v", err)
+		}
+		if !matched {
+			t.Fatal("expected the precondition to match")
+		}
+		expected := merge.Conflicts{{Manager: "controller", Path: _P("numeric")}}
+		if !conflicts.Equals(expected) {
+			t.Errorf("expected conflicts %v, got %v", expected, conflicts)
+		}
+	})
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge
+
+import (
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+// ManagedFieldsCompressor reduces the size of managed fields before they
+// are persisted, and restores them when they are read back. Expand must
+// undo Compress for the same object.
+type ManagedFieldsCompressor interface {
+	// Compress returns a smaller set equivalent to set for object.
+	Compress(object *typed.TypedValue, set *fieldpath.Set) (*fieldpath.Set, error)
+	// Expand returns the set that was compressed into set for object.
+	Expand(object *typed.TypedValue, set *fieldpath.Set) (*fieldpath.Set, error)
+}
+
+// CompressManagedFields compresses the managed fields of object with the
+// compressor of the updater, usually before persisting them. Each manager's
+// set is compressed against the object converted to its version. Without a
+// compressor, managers are returned unchanged.
+func (s *Updater) CompressManagedFields(object *typed.TypedValue, managers fieldpath.ManagedFields) (fieldpath.ManagedFields, error) {
+	if s.compressor == nil {
+		return managers, nil
+	}
+	return s.transformManagedFields(object, managers, s.compressor.Compress)
+}
+
+// ExpandManagedFields reverts CompressManagedFields, usually after reading
+// managed fields back. Without a compressor, managers are returned
+// unchanged.
+func (s *Updater) ExpandManagedFields(object *typed.TypedValue, managers fieldpath.ManagedFields) (fieldpath.ManagedFields, error) {
+	if s.compressor == nil {
+		return managers, nil
+	}
+	return s.transformManagedFields(object, managers, s.compressor.Expand)
+}
+
+func (s *Updater) transformManagedFields(object *typed.TypedValue, managers fieldpath.ManagedFields, transform func(*typed.TypedValue, *fieldpath.Set) (*fieldpath.Set, error)) (fieldpath.ManagedFields, error) {
+	result := fieldpath.ManagedFields{}
+	for manager, versionedSet := range managers {
+		tv, err := s.Converter.Convert(object, versionedSet.APIVersion())
+		if s.Converter.IsMissingVersionError(err) {
+			// The set can't be compressed without the object, keep it as is.
+			result[manager] = versionedSet
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		set, err := transform(tv, versionedSet.Set())
+		if err != nil {
+			return nil, err
+		}
+		result[manager] = fieldpath.NewVersionedSetAt(set, versionedSet.APIVersion(), versionedSet.Applied(), versionedSet.Time())
+	}
+	return result, nil
+}
+
+// subtreeMarker replaces the content of a fully owned subtree in sets
+// compressed by SubtreeCompressor. Negative indices can't appear in
+// objects, so the marker can't be confused with an actual field.
+var subtreeMarker = func() fieldpath.PathElement {
+	i := -1
+	return fieldpath.PathElement{Index: &i}
+}()
+
+// SubtreeCompressor is a ManagedFieldsCompressor that collapses the
+// subtrees of the object that a set fully owns, i.e. where the set has
+// exactly the fields of the object (see typed.TypedValue.ToFieldSet).
+type SubtreeCompressor struct{}
+
+var _ ManagedFieldsCompressor = SubtreeCompressor{}
+
+// Compress replaces the fields of fully owned subtrees with a marker.
+func (SubtreeCompressor) Compress(object *typed.TypedValue, set *fieldpath.Set) (*fieldpath.Set, error) {
+	objectSet, err := object.ToFieldSet()
+	if err != nil {
+		return nil, err
+	}
+	out := fieldpath.NewSet()
+	compressSubtrees(fieldpath.Path{}, set, objectSet, out)
+	return out, nil
+}
+
+func compressSubtrees(prefix fieldpath.Path, set, objectSet, out *fieldpath.Set) {
+	set.Members.Iterate(func(pe fieldpath.PathElement) {
+		out.Insert(append(prefix.Copy(), pe))
+	})
+	set.Children.Iterate(func(pe fieldpath.PathElement) {
+		child, _ := set.Children.Get(pe)
+		path := append(prefix.Copy(), pe)
+		objectChild, ok := objectSet.Children.Get(pe)
+		if !ok {
+			objectChild = fieldpath.NewSet()
+		}
+		// Collapsing a single field wouldn't save anything.
+		if child.Size() > 1 && child.Equals(objectChild) {
+			out.Insert(append(path, subtreeMarker))
+			return
+		}
+		compressSubtrees(path, child, objectChild, out)
+	})
+}
+
+// Expand replaces the markers of fully owned subtrees with the fields of
+// the object.
+func (SubtreeCompressor) Expand(object *typed.TypedValue, set *fieldpath.Set) (*fieldpath.Set, error) {
+	objectSet, err := object.ToFieldSet()
+	if err != nil {
+		return nil, err
+	}
+	out := fieldpath.NewSet()
+	expandSubtrees(fieldpath.Path{}, set, objectSet, out)
+	return out, nil
+}
+
+func expandSubtrees(prefix fieldpath.Path, set, objectSet, out *fieldpath.Set) {
+	set.Members.Iterate(func(pe fieldpath.PathElement) {
+		if !pe.Equals(subtreeMarker) {
+			out.Insert(append(prefix.Copy(), pe))
+			return
+		}
+		objectSet.Iterate(func(p fieldpath.Path) {
+			out.Insert(append(prefix.Copy(), p...))
+		})
+	})
+	set.Children.Iterate(func(pe fieldpath.PathElement) {
+		child, _ := set.Children.Get(pe)
+		objectChild, ok := objectSet.Children.Get(pe)
+		if !ok {
+			objectChild = fieldpath.NewSet()
+		}
+		expandSubtrees(append(prefix.Copy(), pe), child, objectChild, out)
+	})
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge_test
+
+import (
+	"bytes"
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	. "sigs.k8s.io/structured-merge-diff/v4/internal/fixture"
+	"sigs.k8s.io/structured-merge-diff/v4/merge"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+// roundTrip serializes and deserializes managed fields, like they would be
+// when persisted.
+func roundTrip(t *testing.T, managers fieldpath.ManagedFields) fieldpath.ManagedFields {
+	t.Helper()
+	out := fieldpath.ManagedFields{}
+	for manager, versionedSet := range managers {
+		data, err := versionedSet.Set().ToJSON()
+		if err != nil {
+			t.Fatalf("Failed to serialize %v: %v", manager, err)
+		}
+		set := &fieldpath.Set{}
+		if err := set.FromJSON(bytes.NewReader(data)); err != nil {
+			t.Fatalf("Failed to deserialize %v: %v", manager, err)
+		}
+		out[manager] = fieldpath.NewVersionedSet(set, versionedSet.APIVersion(), versionedSet.Applied())
+	}
+	return out
+}
+
+func TestCompressManagedFields(t *testing.T) {
+	builder := merge.UpdaterBuilder{
+		Converter: &specificVersionConverter{
+			AcceptedVersions: []fieldpath.APIVersion{"v1"},
+		},
+		Compressor: merge.SubtreeCompressor{},
+	}
+	updater := builder.BuildUpdater()
+	state := State{
+		Updater: updater,
+		Parser:  nestedTypeParser,
+	}
+	if err := state.Apply(typed.YAMLObject(`
+mapOfMaps:
+  a:
+    b: x
+    c: w
+  d:
+    e: z
+listOfMaps:
+- name: a
+  value:
+    b: x
+    c: w
+`), "v1", "applier", false); err != nil {
+		t.Fatalf("Failed to apply: %v", err)
+	}
+	if err := state.Update(typed.YAMLObject(`
+mapOfMaps:
+  a:
+    b: x
+    c: w
+  d:
+    e: z
+    f: w
+listOfMaps:
+- name: a
+  value:
+    b: x
+    c: w
+struct:
+  name: foo
+  value: 1
+`), "v1", "controller"); err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+
+	compressed, err := updater.CompressManagedFields(state.Live, state.Managers)
+	if err != nil {
+		t.Fatalf("Failed to compress: %v", err)
+	}
+	for manager, versionedSet := range state.Managers {
+		if compressed[manager].Set().Size() >= versionedSet.Set().Size() {
+			t.Errorf("expected %v to be compressed, got\n%v\nfrom\n%v", manager, compressed[manager].Set(), versionedSet.Set())
+		}
+	}
+
+	expanded, err := updater.ExpandManagedFields(state.Live, roundTrip(t, compressed))
+	if err != nil {
+		t.Fatalf("Failed to expand: %v", err)
+	}
+	if !expanded.Equals(state.Managers) {
+		t.Errorf("expected expanded managed fields to be\n%v\ngot\n%v", state.Managers, expanded)
+	}
+}
+
+func TestCompressManagedFieldsNoop(t *testing.T) {
+	updater := &merge.Updater{Converter: &specificVersionConverter{
+		AcceptedVersions: []fieldpath.APIVersion{"v1"},
+	}}
+	managers := fieldpath.ManagedFields{
+		"manager": fieldpath.NewVersionedSet(_NS(_P("a"), _P("b")), "v1", true),
+	}
+	compressed, err := updater.CompressManagedFields(nil, managers)
+	if err != nil {
+		t.Fatalf("Failed to compress: %v", err)
+	}
+	expanded, err := updater.ExpandManagedFields(nil, compressed)
+	if err != nil {
+		t.Fatalf("Failed to expand: %v", err)
+	}
+	if !compressed.Equals(managers) || !expanded.Equals(managers) {
+		t.Errorf("expected managed fields to be unchanged, got %v and %v", compressed, expanded)
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	. "sigs.k8s.io/structured-merge-diff/v4/internal/fixture"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+var computedParser = func() Parser {
+	parser, err := typed.NewParser(`types:
+- name: type
+  map:
+    fields:
+      - name: spec
+        type:
+          map:
+            fields:
+            - name: replicas
+              type:
+                scalar: numeric
+      - name: status
+        type:
+          map:
+            fields:
+            - name: phase
+              type:
+                scalar: string
+            - name: observedGeneration
+              type:
+                scalar: numeric
+              computed: true
+`)
+	if err != nil {
+		panic(err)
+	}
+	return SameVersionParser{T: parser.Type("type")}
+}()
+
+func TestComputedFields(t *testing.T) {
+	tests := map[string]TestCase{
+		"applier_does_not_own_computed_field": {
+			Ops: []Operation{
+				Update{
+					Manager: "controller",
+					Object: `
+						status:
+						  phase: Running
+						  observedGeneration: 1
+					`,
+					APIVersion: "v1",
+				},
+				Apply{
+					Manager: "applier",
+					Object: `
+						spec:
+						  replicas: 3
+						status:
+						  observedGeneration: 5
+					`,
+					APIVersion: "v1",
+				},
+			},
+			Object: `
+				spec:
+				  replicas: 3
+				status:
+				  phase: Running
+				  observedGeneration: 1
+			`,
+			APIVersion: "v1",
+			Managed: fieldpath.ManagedFields{
+				"controller": fieldpath.NewVersionedSet(
+					_NS(
+						_P("status"),
+						_P("status", "phase"),
+					),
+					"v1",
+					false,
+				),
+				"applier": fieldpath.NewVersionedSet(
+					_NS(
+						_P("spec", "replicas"),
+					),
+					"v1",
+					true,
+				),
+			},
+		},
+		"updating_computed_field_does_not_conflict": {
+			Ops: []Operation{
+				Apply{
+					Manager: "applier",
+					Object: `
+						spec:
+						  replicas: 3
+						status:
+						  observedGeneration: 5
+					`,
+					APIVersion: "v1",
+				},
+				Update{
+					Manager: "controller",
+					Object: `
+						spec:
+						  replicas: 3
+						status:
+						  observedGeneration: 2
+					`,
+					APIVersion: "v1",
+				},
+				Apply{
+					Manager: "applier",
+					Object: `
+						spec:
+						  replicas: 4
+					`,
+					APIVersion: "v1",
+				},
+			},
+			Object: `
+				spec:
+				  replicas: 4
+				status:
+				  observedGeneration: 2
+			`,
+			APIVersion: "v1",
+			Managed: fieldpath.ManagedFields{
+				"applier": fieldpath.NewVersionedSet(
+					_NS(
+						_P("spec", "replicas"),
+					),
+					"v1",
+					true,
+				),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := test.Test(computedParser); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
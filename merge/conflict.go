@@ -22,6 +22,21 @@ import (
 	"strings"
 
 	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+// ConflictReason explains why a field conflicts with its manager.
+type ConflictReason string
+
+const (
+	// ValueDiffers means that the value of the field is being changed.
+	ValueDiffers = ConflictReason("ValueDiffers")
+	// OwnershipClaim means that the field is being set while the manager
+	// owns it without it being present in the object.
+	OwnershipClaim = ConflictReason("OwnershipClaim")
+	// AtomicReplace means that an atomic map or list is being changed,
+	// and is replaced as a whole.
+	AtomicReplace = ConflictReason("AtomicReplace")
 )
 
 // Conflict is a conflict on a specific field with the current manager of
@@ -30,6 +45,10 @@ import (
 type Conflict struct {
 	Manager string
 	Path    fieldpath.Path
+	// Reason explains the conflict. It is empty for conflicts that
+	// weren't detected by the Updater, e.g. built by
+	// ConflictsFromManagers, and isn't compared by Equals.
+	Reason ConflictReason
 }
 
 // Conflict is an error.
@@ -40,7 +59,7 @@ func (c Conflict) Error() string {
 	return fmt.Sprintf("conflict with %q: %v", c.Manager, c.Path)
 }
 
-// Equals returns true if c == c2
+// Equals returns true if c == c2, regardless of their reasons.
 func (c Conflict) Equals(c2 Conflict) bool {
 	if c.Manager != c2.Manager {
 		return false
@@ -104,6 +123,42 @@ func (c Conflicts) ToSet() *fieldpath.Set {
 	return set
 }
 
+// ByManager groups the paths of the conflicts by manager.
+func (c Conflicts) ByManager() map[string]*fieldpath.Set {
+	byManager := map[string]*fieldpath.Set{}
+	for _, conflict := range []Conflict(c) {
+		set, ok := byManager[conflict.Manager]
+		if !ok {
+			set = fieldpath.NewSet()
+			byManager[conflict.Manager] = set
+		}
+		set.Insert(conflict.Path)
+	}
+	return byManager
+}
+
+// ByPath groups the managers of the conflicts by the string
+// representation of the conflicting path. Managers are sorted and
+// deduplicated.
+func (c Conflicts) ByPath() map[string][]string {
+	byPath := map[string][]string{}
+	for _, conflict := range []Conflict(c) {
+		path := conflict.Path.String()
+		byPath[path] = append(byPath[path], conflict.Manager)
+	}
+	for path, managers := range byPath {
+		sort.Strings(managers)
+		unique := managers[:0]
+		for i, manager := range managers {
+			if i == 0 || manager != managers[i-1] {
+				unique = append(unique, manager)
+			}
+		}
+		byPath[path] = unique
+	}
+	return byPath
+}
+
 // ConflictsFromManagers creates a list of conflicts given Managers sets.
 func ConflictsFromManagers(sets fieldpath.ManagedFields) Conflicts {
 	conflicts := []Conflict{}
@@ -119,3 +174,31 @@ func ConflictsFromManagers(sets fieldpath.ManagedFields) Conflicts {
 
 	return conflicts
 }
+
+// conflictsWithReasons creates a list of conflicts given the conflicting
+// sets of managers, along with the comparison and the new object at the
+// version of each set, which explain the conflicts.
+func conflictsWithReasons(sets fieldpath.ManagedFields, comparisons map[fieldpath.APIVersion]*typed.Comparison, objects map[fieldpath.APIVersion]*typed.TypedValue) Conflicts {
+	conflicts := []Conflict{}
+
+	for manager, set := range sets {
+		compare := comparisons[set.APIVersion()]
+		atomic := objects[set.APIVersion()].AtomicFields(set.Set())
+		set.Set().Iterate(func(p fieldpath.Path) {
+			reason := ValueDiffers
+			switch {
+			case atomic.Has(p):
+				reason = AtomicReplace
+			case compare.Added.Has(p):
+				reason = OwnershipClaim
+			}
+			conflicts = append(conflicts, Conflict{
+				Manager: manager,
+				Path:    p.Copy(),
+				Reason:  reason,
+			})
+		})
+	}
+
+	return conflicts
+}
@@ -17,10 +17,12 @@ limitations under the License.
 package merge_test
 
 import (
+	"reflect"
 	"testing"
 
 	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
 	"sigs.k8s.io/structured-merge-diff/v4/merge"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
 	"sigs.k8s.io/structured-merge-diff/v4/value"
 )
 
@@ -93,6 +95,82 @@ func TestToSet(t *testing.T) {
 	}
 }
 
+func TestByManager(t *testing.T) {
+	sets := fieldpath.ManagedFields{
+		"Bob": fieldpath.NewVersionedSet(
+			_NS(
+				_P("key"),
+				_P("list", _KBF("key", "a", "id", 2), "id"),
+			),
+			"v1",
+			false,
+		),
+		"Alice": fieldpath.NewVersionedSet(
+			_NS(
+				_P("key"),
+				_P("value"),
+			),
+			"v1",
+			false,
+		),
+	}
+	conflicts := merge.ConflictsFromManagers(sets)
+	// Duplicated conflicts are only reported once.
+	conflicts = append(conflicts, merge.Conflict{Manager: "Bob", Path: _P("key")})
+	actual := conflicts.ByManager()
+	if len(actual) != len(sets) {
+		t.Fatalf("expected %v managers, got %v", len(sets), actual)
+	}
+	for manager, set := range sets {
+		if !set.Set().Equals(actual[manager]) {
+			t.Errorf("expected\n%v\nfor %v, but got\n%v\n", set.Set(), manager, actual[manager])
+		}
+	}
+	if got := (merge.Conflicts{}).ByManager(); len(got) != 0 {
+		t.Errorf("expected no managers, got %v", got)
+	}
+}
+
+func TestByPath(t *testing.T) {
+	conflicts := merge.ConflictsFromManagers(fieldpath.ManagedFields{
+		"Bob": fieldpath.NewVersionedSet(
+			_NS(
+				_P("key"),
+				_P("list", _KBF("key", "a", "id", 2), "id"),
+			),
+			"v1",
+			false,
+		),
+		"Alice": fieldpath.NewVersionedSet(
+			_NS(
+				_P("key"),
+				_P("value"),
+				_P("list", _KBF("key", "a", "id", 2), "id"),
+			),
+			"v1",
+			false,
+		),
+		"Carol": fieldpath.NewVersionedSet(
+			_NS(
+				_P("key"),
+			),
+			"v1",
+			false,
+		),
+	})
+	// Duplicated conflicts are only reported once.
+	conflicts = append(conflicts, merge.Conflict{Manager: "Bob", Path: _P("key")})
+	expected := map[string][]string{
+		".key":                   {"Alice", "Bob", "Carol"},
+		".value":                 {"Alice"},
+		`.list[id=2,key="a"].id`: {"Alice", "Bob"},
+	}
+	actual := conflicts.ByPath()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("expected\n%v\n, but got\n%v\n", expected, actual)
+	}
+}
+
 func TestConflictsFromManagers(t *testing.T) {
 	got := merge.ConflictsFromManagers(fieldpath.ManagedFields{
 		"Bob": fieldpath.NewVersionedSet(
@@ -111,3 +189,65 @@ func TestConflictsFromManagers(t *testing.T) {
 		t.Errorf("Got %v, wanted %v", got.Error(), wanted)
 	}
 }
+
+func TestConflictReasons(t *testing.T) {
+	parser, err := typed.NewParser(`types:
+- name: root
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    - name: value
+      type:
+        scalar: numeric
+    - name: atomicList
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+`)
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+	parse := objectParser(t, parser, "root")
+	updater := buildUpdater(merge.UpdaterBuilder{})
+	live := parse(`{"name": "a", "atomicList": ["a"]}`)
+	managers := fieldpath.ManagedFields{
+		"owner": fieldpath.NewVersionedSet(
+			// The owner owns value, although it's not in the object.
+			_NS(_P("name"), _P("value"), _P("atomicList")),
+			"v1",
+			true,
+		),
+	}
+
+	_, _, err = updater.Apply(live, parse(`{"name": "b", "value": 1, "atomicList": ["a", "b"]}`), "v1", managers, "applier", false)
+	conflicts, ok := err.(merge.Conflicts)
+	if !ok {
+		t.Fatalf("expected conflicts, got %v", err)
+	}
+	expected := map[string]merge.ConflictReason{
+		".name":       merge.ValueDiffers,
+		".value":      merge.OwnershipClaim,
+		".atomicList": merge.AtomicReplace,
+	}
+	if len(conflicts) != len(expected) {
+		t.Fatalf("expected %v conflicts, got %v", len(expected), conflicts)
+	}
+	for _, c := range conflicts {
+		if c.Manager != "owner" {
+			t.Errorf("expected conflict with owner, got %v", c.Manager)
+		}
+		if e := expected[c.Path.String()]; c.Reason != e {
+			t.Errorf("expected reason %v for %v, got %v", e, c.Path, c.Reason)
+		}
+	}
+
+	// The reason isn't compared.
+	c := merge.Conflict{Manager: "owner", Path: _P("name"), Reason: merge.ValueDiffers}
+	if !c.Equals(merge.Conflict{Manager: "owner", Path: _P("name")}) {
+		t.Errorf("expected conflicts to be equal regardless of their reason")
+	}
+}
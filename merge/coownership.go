@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+// AtomicCoownership is a warning about an atomic map or list owned by more
+// than one manager. Since atomic fields are all-or-nothing, managers sharing
+// them usually indicates a modeling error.
+type AtomicCoownership struct {
+	Path     fieldpath.Path
+	Managers []string
+}
+
+// String formats the warning.
+func (w AtomicCoownership) String() string {
+	managers := make([]string, len(w.Managers))
+	for i, manager := range w.Managers {
+		managers[i] = fmt.Sprintf("%q", manager)
+	}
+	return fmt.Sprintf("atomic field %v is owned by multiple managers: %v", w.Path, strings.Join(managers, ", "))
+}
+
+// atomicCoownership lists the atomic fields of object that are owned by
+// more than one manager, sorted by path. Managers are only compared with
+// managers of the same version.
+func (s *Updater) atomicCoownership(object *typed.TypedValue, managers fieldpath.ManagedFields) []AtomicCoownership {
+	byVersion := map[fieldpath.APIVersion][]string{}
+	for manager, set := range managers {
+		byVersion[set.APIVersion()] = append(byVersion[set.APIVersion()], manager)
+	}
+
+	var warnings []AtomicCoownership
+	for version, names := range byVersion {
+		if len(names) < 2 {
+			continue
+		}
+		versioned, err := s.Converter.Convert(object, version)
+		if err != nil {
+			// Warnings are best effort.
+			continue
+		}
+		sort.Strings(names)
+		owners := map[string]*AtomicCoownership{}
+		for _, manager := range names {
+			versioned.AtomicFields(managers[manager].Set()).Iterate(func(p fieldpath.Path) {
+				key := p.String()
+				if _, ok := owners[key]; !ok {
+					owners[key] = &AtomicCoownership{Path: p.Copy()}
+				}
+				owners[key].Managers = append(owners[key].Managers, manager)
+			})
+		}
+		for _, w := range owners {
+			if len(w.Managers) > 1 {
+				warnings = append(warnings, *w)
+			}
+		}
+	}
+	sort.Slice(warnings, func(i, j int) bool {
+		return warnings[i].Path.Compare(warnings[j].Path) < 0
+	})
+	return warnings
+}
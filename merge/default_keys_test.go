@@ -225,6 +225,73 @@ func TestDefaultKeysFlatErrors(t *testing.T) {
 	}
 }
 
+func TestDefaultKeysPreflightApply(t *testing.T) {
+	tests := map[string]struct {
+		config   typed.YAMLObject
+		expected string
+	}{
+		"apply_missing_undefaulted_defaulted_key": {
+			config: `
+				containerPorts:
+				- protocol: TCP
+			`,
+			expected: `.containerPorts: element 0: associative list with keys has an element that omits key field "port" (and doesn't have default value)`,
+		},
+		"apply_missing_defaulted_key_ambiguous_A": {
+			config: `
+				containerPorts:
+				- port: 80
+				- port: 80
+			`,
+			expected: `.containerPorts: element 1: key [port=80,protocol="TCP"] is ambiguous because key fields ["protocol"] are defaulted`,
+		},
+		"apply_missing_defaulted_key_ambiguous_B": {
+			config: `
+				containerPorts:
+				- port: 80
+				- port: 80
+				  protocol: TCP
+			`,
+			expected: `.containerPorts: element 1: key [port=80,protocol="TCP"] is ambiguous because key fields ["protocol"] are defaulted`,
+		},
+		"apply_duplicate_explicit_keys": {
+			config: `
+				containerPorts:
+				- port: 80
+				  protocol: TCP
+				- port: 80
+				  protocol: TCP
+			`,
+			expected: `.containerPorts: duplicate entries for key [port=80,protocol="TCP"]`,
+		},
+		"apply_missing_defaulted_key": {
+			config: `
+				containerPorts:
+				- port: 80
+				- port: 80
+				  protocol: UDP
+			`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			errs, err := portListParser.Type("v1").PreflightApply(FixTabsOrDie(test.config))
+			if err != nil {
+				t.Fatalf("Failed to parse config: %v", err)
+			}
+			if test.expected == "" {
+				if errs != nil {
+					t.Fatalf("Unexpected errors: %v", errs)
+				}
+				return
+			}
+			if errs.Error() != test.expected {
+				t.Errorf("Expected error %q, got %q", test.expected, errs.Error())
+			}
+		})
+	}
+}
+
 // bookParser sets the default value of key:
 // * "chapter" to 1
 // * "section" to "A"
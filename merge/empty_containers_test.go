@@ -0,0 +1,80 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/merge"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+func TestApplyDropEmptyContainers(t *testing.T) {
+	parse := objectParser(t, nestedTypeParser, "v1")
+	// Nobody owns the fields of the live object, e.g. because it was
+	// created without managed fields.
+	live := parse(`{
+		"struct": {},
+		"mapOfMaps": {"incidental": {}, "full": {"x": "y"}},
+		"mapOfMapsRecursive": {"a": {"b": {}}},
+		"listOfMaps": [{"name": "i", "value": {}}]
+	}`)
+	config := parse(`{"mapOfMaps": {"owned": {}}}`)
+
+	for _, tt := range []struct {
+		name     string
+		drop     bool
+		expected typed.YAMLObject
+	}{
+		{
+			name: "preserve",
+			expected: `{
+				"struct": {},
+				"mapOfMaps": {"incidental": {}, "full": {"x": "y"}, "owned": {}},
+				"mapOfMapsRecursive": {"a": {"b": {}}},
+				"listOfMaps": [{"name": "i", "value": {}}]
+			}`,
+		},
+		{
+			name: "drop",
+			drop: true,
+			// Containers left empty by dropped children are dropped too.
+			expected: `{
+				"mapOfMaps": {"full": {"x": "y"}, "owned": {}},
+				"listOfMaps": [{"name": "i"}]
+			}`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			updater := buildUpdater(merge.UpdaterBuilder{
+				DropEmptyContainers: tt.drop,
+			})
+			object, managers, err := updater.Apply(live, config, "v1", fieldpath.ManagedFields{}, "applier", false)
+			if err != nil {
+				t.Fatalf("Failed to apply: %v", err)
+			}
+			if expected := parse(tt.expected); !value.Equals(object.AsValue(), expected.AsValue()) {
+				t.Errorf("expected object\n%v\ngot\n%v", value.ToString(expected.AsValue()), value.ToString(object.AsValue()))
+			}
+			if expected := _NS(_P("mapOfMaps", "owned")); !managers["applier"].Set().Equals(expected) {
+				t.Errorf("expected applier to own %v, got %v", expected, managers["applier"].Set())
+			}
+		})
+	}
+}
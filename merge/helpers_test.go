@@ -0,0 +1,51 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	. "sigs.k8s.io/structured-merge-diff/v4/internal/fixture"
+	"sigs.k8s.io/structured-merge-diff/v4/merge"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+// objectParser returns a function that parses objects of the given type of
+// parser, and fails t if they can't be parsed.
+func objectParser(t testing.TB, parser Parser, typeName string) func(typed.YAMLObject) *typed.TypedValue {
+	pt := parser.Type(typeName)
+	return func(obj typed.YAMLObject) *typed.TypedValue {
+		t.Helper()
+		tv, err := pt.FromYAML(obj)
+		if err != nil {
+			t.Fatalf("Failed to parse %v: %v", obj, err)
+		}
+		return tv
+	}
+}
+
+// buildUpdater builds the updater of builder, which only accepts version v1
+// unless builder sets a converter.
+func buildUpdater(builder merge.UpdaterBuilder) *merge.Updater {
+	if builder.Converter == nil {
+		builder.Converter = &specificVersionConverter{
+			AcceptedVersions: []fieldpath.APIVersion{"v1"},
+		}
+	}
+	return builder.BuildUpdater()
+}
@@ -21,6 +21,7 @@ import (
 
 	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
 	. "sigs.k8s.io/structured-merge-diff/v4/internal/fixture"
+	"sigs.k8s.io/structured-merge-diff/v4/merge"
 )
 
 func TestIgnoreFilter(t *testing.T) {
@@ -473,3 +474,75 @@ func TestFilteredFieldsUsesVersions(t *testing.T) {
 		})
 	}
 }
+
+func TestConflictsWithIgnored(t *testing.T) {
+	parse := objectParser(t, structMultiversionParser, "v1")
+	updater := buildUpdater(merge.UpdaterBuilder{
+		Converter: renamingConverter{structMultiversionParser},
+	})
+	live := parse(`{"struct": {"name": "a", "scalarField_v1": "x"}}`)
+	config := parse(`{"struct": {"name": "a", "scalarField_v1": "y"}}`)
+	managers := fieldpath.ManagedFields{
+		"other": fieldpath.NewVersionedSet(_NS(_P("struct", "scalarField_v2")), "v2", false),
+	}
+
+	for _, tt := range []struct {
+		name      string
+		ignored   map[fieldpath.APIVersion]*fieldpath.Set
+		conflicts bool
+	}{
+		{
+			name:      "not ignored",
+			conflicts: true,
+		},
+		{
+			name: "ignored at the version of the conflicting manager",
+			ignored: map[fieldpath.APIVersion]*fieldpath.Set{
+				"v2": _NS(_P("struct", "scalarField_v2")),
+			},
+		},
+		{
+			name: "ignored at the applied version",
+			ignored: map[fieldpath.APIVersion]*fieldpath.Set{
+				"v1": _NS(_P("struct", "scalarField_v1")),
+			},
+		},
+		{
+			name: "other field ignored",
+			ignored: map[fieldpath.APIVersion]*fieldpath.Set{
+				"v1": _NS(_P("struct", "name")),
+			},
+			conflicts: true,
+		},
+		{
+			name: "ignored at another version only",
+			ignored: map[fieldpath.APIVersion]*fieldpath.Set{
+				"v1": _NS(_P("struct", "scalarField_v1")),
+				"v2": _NS(_P("struct", "name")),
+			},
+			conflicts: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			conflicts, err := updater.ConflictsWithIgnored(live, config, "v1", managers, "applier", tt.ignored)
+			if err != nil {
+				t.Fatalf("Failed to compute conflicts: %v", err)
+			}
+			if !tt.conflicts {
+				if len(conflicts) != 0 {
+					t.Fatalf("Expected no conflicts, got %v", conflicts)
+				}
+				return
+			}
+			expected := merge.Conflicts{{Manager: "other", Path: _P("struct", "scalarField_v2")}}
+			if !conflicts.Equals(expected) {
+				t.Fatalf("Expected conflicts %v, got %v", expected, conflicts)
+			}
+		})
+	}
+	if !managers.Equals(fieldpath.ManagedFields{
+		"other": fieldpath.NewVersionedSet(_NS(_P("struct", "scalarField_v2")), "v2", false),
+	}) {
+		t.Errorf("Expected managers to be left untouched, got %v", managers)
+	}
+}
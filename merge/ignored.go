@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+// ConflictsWithIgnored returns the conflicts that applying configObject at
+// version as manager would run into, without applying it, while ignoring
+// the paths of ignored in addition to the fields ignored by the updater.
+// Like IgnoredFields, ignored is keyed by version: the conflicts with each
+// manager are found at the version of its managed fields, and exclude the
+// paths ignored at that version. The paths ignored at version are
+// converted to the versions of the managers that ignored has no entry for,
+// so that they aren't reported as conflicts whatever the version of the
+// conflicting managers. Since sets can't be converted on their own, they
+// are converted by converting the fields of liveObject and configObject at
+// these paths, along with the key fields of the list items they are in.
+func (s *Updater) ConflictsWithIgnored(liveObject, configObject *typed.TypedValue, version fieldpath.APIVersion, managers fieldpath.ManagedFields, manager string, ignored map[fieldpath.APIVersion]*fieldpath.Set) (Conflicts, error) {
+	if s.IgnoredFields != nil && s.IgnoreFilter != nil {
+		return nil, fmt.Errorf("IgnoredFields and IgnoreFilter may not both be set")
+	}
+	ignoredAt := map[fieldpath.APIVersion]*fieldpath.Set{}
+	for v, set := range ignored {
+		ignoredAt[v] = set
+	}
+	if set, ok := ignored[version]; ok && !set.Empty() {
+		for _, managerSet := range managers {
+			v := managerSet.APIVersion()
+			if _, ok := ignoredAt[v]; ok {
+				continue
+			}
+			converted, err := s.convertIgnored(set, version, v, liveObject, configObject)
+			if err != nil {
+				return nil, err
+			}
+			ignoredAt[v] = converted
+		}
+	}
+
+	filters := map[fieldpath.APIVersion]fieldpath.Filter{}
+	for v, filter := range s.IgnoreFilter {
+		filters[v] = filter
+	}
+	for v, set := range s.IgnoredFields {
+		filters[v] = fieldpath.NewExcludeSetFilter(set)
+	}
+	for v, set := range ignoredAt {
+		if set == nil {
+			continue
+		}
+		if filter, ok := filters[v]; ok && filter != nil {
+			filters[v] = filterChain{filter, fieldpath.NewExcludeSetFilter(set)}
+		} else {
+			filters[v] = fieldpath.NewExcludeSetFilter(set)
+		}
+	}
+
+	updater := *s
+	updater.IgnoredFields = nil
+	updater.IgnoreFilter = filters
+	_, err := updater.apply(liveObject, configObject, version, managers.Copy(), manager, false)
+	if conflicts, ok := err.(Conflicts); ok {
+		return conflicts, nil
+	}
+	return nil, err
+}
+
+// convertIgnored converts the ignored paths of the objects from version to
+// to (see convertSet).
+func (s *Updater) convertIgnored(set *fieldpath.Set, version, to fieldpath.APIVersion, objects ...*typed.TypedValue) (*fieldpath.Set, error) {
+	converted := fieldpath.NewSet()
+	for _, object := range objects {
+		objectSet, err := s.convertSet(object, set, version, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert ignored fields from version %v to %v: %v", version, to, err)
+		}
+		converted = converted.Union(objectSet)
+	}
+	return converted, nil
+}
+
+// filterChain applies each of its filters in turn.
+type filterChain []fieldpath.Filter
+
+func (c filterChain) Filter(set *fieldpath.Set) *fieldpath.Set {
+	for _, filter := range c {
+		set = filter.Filter(set)
+	}
+	return set
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+// Import returns the managed fields of live, an object that isn't managed
+// yet, where baselineManager owns every field and list or map item of live
+// at version, as if it had updated them all. Later updates and applies
+// behave as if the object had always been managed: applying a different
+// value to a field owned by the baseline manager conflicts, while applying
+// the same value makes the field co-owned. Ignored fields aren't owned.
+func (s *Updater) Import(live *typed.TypedValue, version fieldpath.APIVersion, baselineManager string) (fieldpath.ManagedFields, error) {
+	set, err := live.ToFieldSet()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get field set: %v", err)
+	}
+	set, err = s.filterIgnored(set, version)
+	if err != nil {
+		return nil, err
+	}
+	managers := fieldpath.ManagedFields{}
+	if !set.Empty() {
+		managers[s.canonicalManager(baselineManager)] = fieldpath.NewVersionedSetAt(set, version, false, s.timestamp())
+	}
+	return managers, nil
+}
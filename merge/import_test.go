@@ -0,0 +1,82 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/merge"
+)
+
+func TestImport(t *testing.T) {
+	updater := buildUpdater(merge.UpdaterBuilder{
+		ReturnInputOnNoop: true,
+	})
+	parse := objectParser(t, associativeListParser, "v1")
+	live := parse(`{"list": [{"name": "a", "value": 1}, {"name": "b", "value": 2}]}`)
+
+	managers, err := updater.Import(live, "v1", "baseline")
+	if err != nil {
+		t.Fatalf("Failed to import: %v", err)
+	}
+	expected := fieldpath.ManagedFields{
+		"baseline": fieldpath.NewVersionedSet(_NS(
+			_P("list", _KBF("name", "a")),
+			_P("list", _KBF("name", "a"), "name"),
+			_P("list", _KBF("name", "a"), "value"),
+			_P("list", _KBF("name", "b")),
+			_P("list", _KBF("name", "b"), "name"),
+			_P("list", _KBF("name", "b"), "value"),
+		), "v1", false),
+	}
+	if !managers.Equals(expected) {
+		t.Fatalf("Expected managers:\n%v\ngot:\n%v", expected, managers)
+	}
+
+	// Changing a field of the baseline conflicts.
+	_, _, err = updater.Apply(live, parse(`{"list": [{"name": "a", "value": 3}]}`), "v1", managers.Copy(), "applier", false)
+	expectedConflicts := merge.Conflicts{{Manager: "baseline", Path: _P("list", _KBF("name", "a"), "value")}}
+	if conflicts, ok := err.(merge.Conflicts); !ok || !conflicts.Equals(expectedConflicts) {
+		t.Fatalf("Expected conflicts %v, got %v", expectedConflicts, err)
+	}
+
+	// Applying the same values co-owns them, and removing them from the
+	// configuration later doesn't remove them from the object.
+	object, newManagers, err := updater.Apply(live, parse(`{"list": [{"name": "a", "value": 1}]}`), "v1", managers.Copy(), "applier", false)
+	if err != nil {
+		t.Fatalf("Failed to apply: %v", err)
+	}
+	if !newManagers["baseline"].Set().Equals(expected["baseline"].Set()) {
+		t.Errorf("Expected the baseline to keep its fields, got %v", newManagers["baseline"].Set())
+	}
+	expectedApplier := _NS(
+		_P("list", _KBF("name", "a")),
+		_P("list", _KBF("name", "a"), "name"),
+		_P("list", _KBF("name", "a"), "value"),
+	)
+	if !newManagers["applier"].Set().Equals(expectedApplier) {
+		t.Errorf("Expected applier to own %v, got %v", expectedApplier, newManagers["applier"].Set())
+	}
+	object, _, err = updater.Apply(object, parse(`{}`), "v1", newManagers, "applier", false)
+	if err != nil {
+		t.Fatalf("Failed to apply: %v", err)
+	}
+	if comparison, err := object.Compare(live); err != nil || !comparison.IsSame() {
+		t.Errorf("Expected the object to be unchanged, got %v (%v)", object.AsValue(), err)
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// maxReplayRounds is the number of times DetectOwnershipInstability replays
+// its operations before giving up on them settling.
+const maxReplayRounds = 16
+
+// Op is an operation replayed by DetectOwnershipInstability.
+type Op struct {
+	// Manager is the manager running the operation.
+	Manager string
+	// Version is the version of Object, and of the live object when the
+	// operation runs.
+	Version fieldpath.APIVersion
+	// Object is the configuration applied if Apply is set. Otherwise, it
+	// holds the fields that the manager sets by updating the live object,
+	// which are merged into it, like a controller writing its own fields.
+	Object *typed.TypedValue
+	// Apply is true for applies, false for updates.
+	Apply bool
+	// Force forces the apply through conflicts. Applies that conflict
+	// leave the object unchanged, like rejected requests.
+	Force bool
+}
+
+// DetectOwnershipInstability replays ops in a loop, starting from an empty
+// object, like managers that reconcile the same object over and over, and
+// returns the paths whose ownership keeps changing once the object and its
+// managed fields have settled into a cycle. Operations that are repeated
+// without effect don't change the ownership of anything, so ownership
+// changes past that point oscillate and never converge, e.g. between
+// managers that force different values for the same field, possibly at
+// different versions. Paths are returned at the version of the managed
+// fields they were found in, which can differ for the same field. An error
+// is returned if the operations fail, or if they don't settle into a cycle
+// after a number of rounds.
+func DetectOwnershipInstability(ops []Op, u *Updater) ([]fieldpath.Path, error) {
+	if len(ops) == 0 {
+		return nil, nil
+	}
+	r := replay{updater: u, live: ops[0].Object.Empty(), managers: fieldpath.ManagedFields{}}
+	var rounds []replayState
+	for {
+		if len(rounds) == maxReplayRounds {
+			return nil, fmt.Errorf("ownership didn't settle after %d rounds", maxReplayRounds)
+		}
+		if _, err := r.round(ops); err != nil {
+			return nil, err
+		}
+		if r.seen(rounds) {
+			break
+		}
+		rounds = append(rounds, r.state())
+	}
+
+	unstable, err := r.round(ops)
+	if err != nil {
+		return nil, err
+	}
+	var paths []fieldpath.Path
+	unstable.Iterate(func(p fieldpath.Path) {
+		paths = append(paths, p.Copy())
+	})
+	return paths, nil
+}
+
+// replay runs operations on an object and its managed fields.
+type replay struct {
+	updater  *Updater
+	live     *typed.TypedValue
+	managers fieldpath.ManagedFields
+}
+
+type replayState struct {
+	live     *typed.TypedValue
+	managers fieldpath.ManagedFields
+}
+
+func (r *replay) state() replayState {
+	return replayState{live: r.live, managers: r.managers.Copy()}
+}
+
+// seen returns true if the current state is one of states.
+func (r *replay) seen(states []replayState) bool {
+	tr := r.live.TypeRef()
+	for _, s := range states {
+		if str := s.live.TypeRef(); str.Equals(&tr) && value.Equals(s.live.AsValue(), r.live.AsValue()) && s.managers.Equals(r.managers) {
+			return true
+		}
+	}
+	return false
+}
+
+// round runs ops, and returns the paths whose ownership they change.
+func (r *replay) round(ops []Op) (*fieldpath.Set, error) {
+	changed := fieldpath.NewSet()
+	for i, op := range ops {
+		before := r.managers.Copy()
+		if err := r.run(op); err != nil {
+			return nil, fmt.Errorf("operation %d (%q at %v): %v", i, op.Manager, op.Version, err)
+		}
+		for _, set := range ownershipChanges(before, r.managers) {
+			changed = changed.Union(set)
+		}
+	}
+	return changed, nil
+}
+
+func (r *replay) run(op Op) error {
+	live, err := r.updater.Converter.Convert(r.live, op.Version)
+	if err != nil {
+		return fmt.Errorf("failed to convert live object: %v", err)
+	}
+	var object *typed.TypedValue
+	var managers fieldpath.ManagedFields
+	if op.Apply {
+		object, managers, err = r.updater.Apply(live, op.Object, op.Version, r.managers.Copy(), op.Manager, op.Force)
+		if _, ok := err.(Conflicts); ok {
+			return nil
+		}
+		if err == nil && object == nil {
+			object = live
+		}
+	} else {
+		updated, mergeErr := live.Merge(op.Object)
+		if mergeErr != nil {
+			return fmt.Errorf("failed to merge object: %v", mergeErr)
+		}
+		object, managers, err = r.updater.Update(live, updated, op.Version, r.managers.Copy(), op.Manager)
+	}
+	if err != nil {
+		return err
+	}
+	r.live, r.managers = object, managers
+	return nil
+}
+
+// ownershipChanges returns the paths gained or lost by each manager
+// between before and after. Managers whose version changes lose all of
+// their paths and gain all of their new ones.
+func ownershipChanges(before, after fieldpath.ManagedFields) []*fieldpath.Set {
+	var changes []*fieldpath.Set
+	for manager, b := range before {
+		a, ok := after[manager]
+		switch {
+		case !ok:
+			changes = append(changes, b.Set())
+		case a.APIVersion() != b.APIVersion():
+			changes = append(changes, b.Set(), a.Set())
+		default:
+			changes = append(changes, b.Set().Difference(a.Set()), a.Set().Difference(b.Set()))
+		}
+	}
+	for manager, a := range after {
+		if _, ok := before[manager]; !ok {
+			changes = append(changes, a.Set())
+		}
+	}
+	return changes
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/merge"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+func TestDetectOwnershipInstability(t *testing.T) {
+	updater := &merge.Updater{Converter: renamingConverter{structMultiversionParser}}
+	parse := func(version fieldpath.APIVersion, obj typed.YAMLObject) *typed.TypedValue {
+		tv, err := structMultiversionParser.Type(string(version)).FromYAML(obj)
+		if err != nil {
+			t.Fatalf("Failed to parse %v: %v", obj, err)
+		}
+		return tv
+	}
+
+	tests := []struct {
+		name     string
+		ops      []merge.Op
+		expected *fieldpath.Set
+	}{
+		{
+			name: "stable",
+			ops: []merge.Op{
+				{Manager: "a", Version: "v1", Object: parse("v1", `{"struct": {"scalarField_v1": "a"}}`), Apply: true},
+				{Manager: "b", Version: "v2", Object: parse("v2", `{"struct": {"complexField_v2": {"name": "b"}}}`), Apply: true},
+				{Manager: "c", Version: "v3", Object: parse("v3", `{"struct": {"name": "c"}}`)},
+			},
+			expected: fieldpath.NewSet(),
+		},
+		{
+			name: "forced-applies",
+			ops: []merge.Op{
+				{Manager: "a", Version: "v1", Object: parse("v1", `{"struct": {"scalarField_v1": "a", "name": "x"}}`), Apply: true, Force: true},
+				{Manager: "b", Version: "v2", Object: parse("v2", `{"struct": {"scalarField_v2": "b", "name": "x"}}`), Apply: true, Force: true},
+			},
+			expected: _NS(
+				_P("struct", "scalarField_v1"),
+				_P("struct", "scalarField_v2"),
+			),
+		},
+		{
+			name: "update-against-apply",
+			ops: []merge.Op{
+				{Manager: "a", Version: "v1", Object: parse("v1", `{"struct": {"complexField_v1": {"name": "a"}}}`), Apply: true, Force: true},
+				{Manager: "c", Version: "v3", Object: parse("v3", `{"struct": {"complexField_v3": {"name": "c"}}}`)},
+			},
+			expected: _NS(
+				_P("struct", "complexField_v1", "name"),
+				_P("struct", "complexField_v3", "name"),
+			),
+		},
+		{
+			name: "unforced-apply",
+			ops: []merge.Op{
+				{Manager: "a", Version: "v1", Object: parse("v1", `{"struct": {"scalarField_v1": "a"}}`), Apply: true},
+				{Manager: "b", Version: "v2", Object: parse("v2", `{"struct": {"scalarField_v2": "b"}}`), Apply: true},
+			},
+			expected: fieldpath.NewSet(),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			paths, err := merge.DetectOwnershipInstability(tt.ops, updater)
+			if err != nil {
+				t.Fatalf("Failed to detect instability: %v", err)
+			}
+			if got := fieldpath.NewSet(paths...); !got.Equals(tt.expected) {
+				t.Errorf("expected unstable paths:\n%v\ngot:\n%v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 
 	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
 	. "sigs.k8s.io/structured-merge-diff/v4/internal/fixture"
+	"sigs.k8s.io/structured-merge-diff/v4/merge"
 	"sigs.k8s.io/structured-merge-diff/v4/typed"
 )
 
@@ -106,3 +107,69 @@ func TestUpdateAssociativeLists(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyReportsCreatedListItems(t *testing.T) {
+	var created *fieldpath.Set
+	builder := merge.UpdaterBuilder{
+		Converter: &specificVersionConverter{
+			AcceptedVersions: []fieldpath.APIVersion{"v1"},
+		},
+		ReportCreatedListItems: func(s *fieldpath.Set) {
+			created = s
+		},
+	}
+	state := State{
+		Updater: builder.BuildUpdater(),
+		Parser:  associativeListParser,
+	}
+
+	if err := state.Update(typed.YAMLObject(`{"list": [{"name": "a", "value": 1}]}`), "v1", "controller"); err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+	if created != nil {
+		t.Fatalf("expected updates not to report created items, got %v", created)
+	}
+	if err := state.Apply(typed.YAMLObject(`{"list": [{"name": "a", "value": 1}, {"name": "b", "value": 2}]}`), "v1", "applier", false); err != nil {
+		t.Fatalf("Failed to apply: %v", err)
+	}
+	if expected := _NS(_P("list", _KBF("name", "b"))); !created.Equals(expected) {
+		t.Errorf("expected created items %v, got %v", expected, created)
+	}
+
+	// Applying the same items again creates nothing.
+	if err := state.Apply(typed.YAMLObject(`{"list": [{"name": "a", "value": 1}, {"name": "b", "value": 2}]}`), "v1", "applier", false); err != nil {
+		t.Fatalf("Failed to apply: %v", err)
+	}
+	if !created.Empty() {
+		t.Errorf("expected no created items, got %v", created)
+	}
+}
+
+func TestApplyMaxResultSize(t *testing.T) {
+	builder := merge.UpdaterBuilder{
+		Converter: &specificVersionConverter{
+			AcceptedVersions: []fieldpath.APIVersion{"v1"},
+		},
+		MaxResultSize: 10,
+	}
+	state := State{
+		Updater: builder.BuildUpdater(),
+		Parser:  associativeListParser,
+	}
+
+	// The root, the list, and two items with their name and value.
+	if err := state.Apply(typed.YAMLObject(`{"list": [{"name": "a", "value": 1}, {"name": "b", "value": 2}]}`), "v1", "applier", false); err != nil {
+		t.Fatalf("Failed to apply under the budget: %v", err)
+	}
+	if err := state.Apply(typed.YAMLObject(`{"list": [{"name": "a", "value": 1}, {"name": "b", "value": 2}, {"name": "c", "value": 3}]}`), "v1", "applier", false); err == nil {
+		t.Fatal("expected apply over the budget to fail")
+	}
+	// The failed apply left the object untouched.
+	expected, err := associativeListParser.Type("v1").FromYAML(`{"list": [{"name": "a", "value": 1}, {"name": "b", "value": 2}]}`)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if comparison, err := state.Live.Compare(expected); err != nil || !comparison.IsSame() {
+		t.Errorf("expected object to be unchanged, got %v", state.Live)
+	}
+}
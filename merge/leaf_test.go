@@ -509,6 +509,40 @@ func TestUpdateLeaf(t *testing.T) {
 	}
 }
 
+func TestLeafCoverage(t *testing.T) {
+	state := State{
+		Updater: &merge.Updater{Converter: &specificVersionConverter{
+			AcceptedVersions: []fieldpath.APIVersion{"v1"},
+		}},
+		Parser: leafFieldsParser,
+	}
+	if err := state.Apply(typed.YAMLObject(`{"numeric": 1, "string": "a"}`), "v1", "default", false); err != nil {
+		t.Fatalf("Failed to apply: %v", err)
+	}
+	if err := state.Update(typed.YAMLObject(`{"numeric": 1, "string": "a", "bool": true}`), "v1", "controller"); err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+
+	for manager, expected := range map[string]int{"default": 2, "controller": 1} {
+		owned, total, err := typed.Coverage(state.Live, state.Managers[manager].Set())
+		if err != nil {
+			t.Fatalf("Failed to compute coverage: %v", err)
+		}
+		if owned != expected || total != 3 {
+			t.Errorf("expected %v to own %v/3 fields, got %v/%v", manager, expected, owned, total)
+		}
+	}
+
+	// Owning fields that are no longer in the object doesn't count.
+	owned, total, err := typed.Coverage(state.Live, _NS(_P("numeric"), _P("missing")))
+	if err != nil {
+		t.Fatalf("Failed to compute coverage: %v", err)
+	}
+	if owned != 1 || total != 3 {
+		t.Errorf("expected to own 1/3 fields, got %v/%v", owned, total)
+	}
+}
+
 func BenchmarkLeafConflictAcrossVersion(b *testing.B) {
 	test := TestCase{
 		Ops: []Operation{
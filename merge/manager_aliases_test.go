@@ -0,0 +1,88 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/merge"
+)
+
+func TestManagerAliases(t *testing.T) {
+	parse := objectParser(t, leafFieldsParser, "v1")
+	updater := buildUpdater(merge.UpdaterBuilder{
+		Converter: &specificVersionConverter{
+			AcceptedVersions: []fieldpath.APIVersion{"v1", "v2"},
+		},
+		ManagerAliases: map[string]string{
+			"controller-a": "controller",
+			"controller-b": "controller",
+		},
+	})
+
+	live, managers, err := updater.Apply(parse(`{}`), parse(`{"numeric": 1}`), "v1", fieldpath.ManagedFields{}, "controller-a", false)
+	if err != nil {
+		t.Fatalf("Failed to apply: %v", err)
+	}
+	live, managers, err = updater.Update(live, parse(`{"numeric": 1, "string": "a"}`), "v1", managers, "other")
+	if err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+
+	// Changing the fields of a sibling alias doesn't conflict, while the
+	// fields of other managers still do.
+	_, _, err = updater.Apply(live, parse(`{"numeric": 2, "string": "b"}`), "v1", managers, "controller-b", false)
+	if conflicts, ok := err.(merge.Conflicts); !ok || len(conflicts) != 1 || conflicts[0].Manager != "other" {
+		t.Fatalf("Expected a single conflict with other, got %v", err)
+	}
+	live, managers, err = updater.Apply(live, parse(`{"numeric": 2, "bool": true}`), "v1", managers, "controller-b", false)
+	if err != nil {
+		t.Fatalf("Failed to apply: %v", err)
+	}
+	if expected := (fieldpath.ManagedFields{
+		"controller": fieldpath.NewVersionedSet(_NS(_P("numeric"), _P("bool")), "v1", true),
+		"other":      fieldpath.NewVersionedSet(_NS(_P("string")), "v1", false),
+	}); !managers.Equals(expected) {
+		t.Fatalf("Expected managers:\n%v\ngot:\n%v", expected, managers)
+	}
+
+	// Sets owned by aliases in existing managed fields are merged.
+	_, managers, err = updater.Update(live, parse(`{"numeric": 3, "string": "a", "bool": true}`), "v1", fieldpath.ManagedFields{
+		"controller-a": fieldpath.NewVersionedSet(_NS(_P("numeric")), "v1", true),
+		"controller-b": fieldpath.NewVersionedSet(_NS(_P("bool")), "v1", true),
+		"other":        fieldpath.NewVersionedSet(_NS(_P("string")), "v1", false),
+	}, "controller-b")
+	if err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+	if expected := (fieldpath.ManagedFields{
+		"controller": fieldpath.NewVersionedSet(_NS(_P("numeric"), _P("bool")), "v1", false),
+		"other":      fieldpath.NewVersionedSet(_NS(_P("string")), "v1", false),
+	}); !managers.Equals(expected) {
+		t.Fatalf("Expected managers:\n%v\ngot:\n%v", expected, managers)
+	}
+
+	_, _, err = updater.Apply(live, parse(`{"numeric": 4}`), "v1", fieldpath.ManagedFields{
+		"controller-a": fieldpath.NewVersionedSet(_NS(_P("numeric")), "v1", true),
+		"controller-b": fieldpath.NewVersionedSet(_NS(_P("bool")), "v2", true),
+	}, "controller-a", false)
+	if err == nil || !strings.Contains(err.Error(), "different versions") {
+		t.Fatalf("Expected an error about aliases of different versions, got %v", err)
+	}
+}
@@ -1715,6 +1715,51 @@ func TestMultipleApplierAtomicMaps(t *testing.T) {
 	}
 }
 
+func TestMultipleApplierAtomicCoownershipWarning(t *testing.T) {
+	var warnings []merge.AtomicCoownership
+	builder := merge.UpdaterBuilder{
+		Converter: &specificVersionConverter{
+			AcceptedVersions: []fieldpath.APIVersion{"v1"},
+		},
+		WarnAtomicCoownership: func(w []merge.AtomicCoownership) {
+			warnings = w
+		},
+	}
+	state := State{
+		Updater: builder.BuildUpdater(),
+		Parser:  atomicMapParser,
+	}
+
+	if err := state.Apply(typed.YAMLObject(`{"atomicMap": {"field1": "a"}}`), "v1", "apply-one", false); err != nil {
+		t.Fatalf("Failed to apply: %v", err)
+	}
+	if warnings != nil {
+		t.Fatalf("expected no warnings with a single owner, got %v", warnings)
+	}
+	// Applying the same atomic map doesn't conflict, so both appliers own it.
+	if err := state.Apply(typed.YAMLObject(`{"atomicMap": {"field1": "a"}}`), "v1", "apply-two", false); err != nil {
+		t.Fatalf("Failed to apply: %v", err)
+	}
+	expected := []merge.AtomicCoownership{{
+		Path:     _P("atomicMap"),
+		Managers: []string{"apply-one", "apply-two"},
+	}}
+	if len(warnings) != len(expected) ||
+		!warnings[0].Path.Equals(expected[0].Path) ||
+		strings.Join(warnings[0].Managers, ",") != strings.Join(expected[0].Managers, ",") {
+		t.Fatalf("expected warnings %v, got %v", expected, warnings)
+	}
+
+	// Taking the map over removes the warning.
+	warnings = nil
+	if err := state.Apply(typed.YAMLObject(`{"atomicMap": {"field2": "b"}}`), "v1", "apply-three", true); err != nil {
+		t.Fatalf("Failed to apply: %v", err)
+	}
+	if warnings != nil {
+		t.Fatalf("expected no warnings after force apply, got %v", warnings)
+	}
+}
+
 func BenchmarkMultipleApplierRecursiveRealConversion(b *testing.B) {
 	test := TestCase{
 		Ops: []Operation{
@@ -0,0 +1,80 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath/fieldpathtesting"
+	"sigs.k8s.io/structured-merge-diff/v4/merge"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+func TestApplyNullMeansDelete(t *testing.T) {
+	parse := objectParser(t, nestedTypeParser, "v1")
+	config := parse(`{"struct": {"name": null, "value": 1}, "mapOfMaps": null}`)
+
+	for _, tt := range []struct {
+		name            string
+		nullMeansDelete bool
+		live            typed.YAMLObject
+		expected        typed.YAMLObject
+		expectedSet     *fieldpath.Set
+	}{
+		{
+			name:     "set",
+			live:     `{"struct": {"name": "a", "value": 1}}`,
+			expected: `{"struct": {"name": null, "value": 1}, "mapOfMaps": null}`,
+			expectedSet: _NS(
+				_P("struct", "name"),
+				_P("struct", "value"),
+				_P("mapOfMaps"),
+			),
+		},
+		{
+			name:            "delete",
+			nullMeansDelete: true,
+			live:            `{"struct": {"name": "a", "value": 1}, "mapOfMaps": {"a": {"x": "y"}}}`,
+			expected:        `{"struct": {"value": 1}}`,
+			expectedSet: _NS(
+				_P("struct", "value"),
+			),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			updater := buildUpdater(merge.UpdaterBuilder{
+				NullMeansDelete: tt.nullMeansDelete,
+			})
+			managers := fieldpath.ManagedFields{
+				"applier": fieldpath.NewVersionedSet(_NS(
+					_P("struct", "name"),
+					_P("struct", "value"),
+				), "v1", true),
+			}
+			object, managers, err := updater.Apply(parse(tt.live), config, "v1", managers, "applier", false)
+			if err != nil {
+				t.Fatalf("Failed to apply: %v", err)
+			}
+			if expected := parse(tt.expected); !value.Equals(object.AsValue(), expected.AsValue()) {
+				t.Errorf("Expected object:\n%v\ngot:\n%v", value.ToString(expected.AsValue()), value.ToString(object.AsValue()))
+			}
+			fieldpathtesting.AssertSetEquals(t, managers["applier"].Set(), tt.expectedSet)
+		})
+	}
+}
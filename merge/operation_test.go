@@ -0,0 +1,96 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge_test
+
+import (
+	"testing"
+	"time"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	. "sigs.k8s.io/structured-merge-diff/v4/internal/fixture"
+	"sigs.k8s.io/structured-merge-diff/v4/merge"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+func TestOperationAndTime(t *testing.T) {
+	now := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	builder := merge.UpdaterBuilder{
+		Converter: &specificVersionConverter{
+			AcceptedVersions: []fieldpath.APIVersion{"v1"},
+		},
+		Now: func() time.Time { return now },
+	}
+	state := State{
+		Updater: builder.BuildUpdater(),
+		Parser:  DeducedParser,
+	}
+
+	if err := state.Update(typed.YAMLObject(`{"a": 1, "c": 1}`), "v1", "updater"); err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+	updated := now
+	now = now.Add(time.Hour)
+	if err := state.Apply(typed.YAMLObject(`{"b": 1}`), "v1", "applier", false); err != nil {
+		t.Fatalf("Failed to apply: %v", err)
+	}
+
+	if got := state.Managers["updater"].Operation(); got != fieldpath.UpdateOperation {
+		t.Errorf("expected updater operation to be %v, got %v", fieldpath.UpdateOperation, got)
+	}
+	if got := state.Managers["updater"].Time(); !got.Equal(updated) {
+		t.Errorf("expected updater time to be %v, got %v", updated, got)
+	}
+	if got := state.Managers["applier"].Operation(); got != fieldpath.ApplyOperation {
+		t.Errorf("expected applier operation to be %v, got %v", fieldpath.ApplyOperation, got)
+	}
+	if got := state.Managers["applier"].Time(); !got.Equal(now) {
+		t.Errorf("expected applier time to be %v, got %v", now, got)
+	}
+
+	// Taking over a field from another manager doesn't change its time.
+	now = now.Add(time.Hour)
+	if err := state.Apply(typed.YAMLObject(`{"a": 2}`), "v1", "applier", true); err != nil {
+		t.Fatalf("Failed to apply: %v", err)
+	}
+	if got := state.Managers["updater"].Set(); !got.Equals(_NS(_P("c"))) {
+		t.Errorf("expected updater to only own .c, got %v", got)
+	}
+	if got := state.Managers["updater"].Time(); !got.Equal(updated) {
+		t.Errorf("expected updater time to be %v, got %v", updated, got)
+	}
+	if got := state.Managers["applier"].Time(); !got.Equal(now) {
+		t.Errorf("expected applier time to be %v, got %v", now, got)
+	}
+}
+
+func TestOperationWithoutTime(t *testing.T) {
+	state := State{
+		Updater: &merge.Updater{Converter: &specificVersionConverter{
+			AcceptedVersions: []fieldpath.APIVersion{"v1"},
+		}},
+		Parser: DeducedParser,
+	}
+	if err := state.Apply(typed.YAMLObject(`{"a": 1}`), "v1", "applier", false); err != nil {
+		t.Fatalf("Failed to apply: %v", err)
+	}
+	if got := state.Managers["applier"].Operation(); got != fieldpath.ApplyOperation {
+		t.Errorf("expected applier operation to be %v, got %v", fieldpath.ApplyOperation, got)
+	}
+	if got := state.Managers["applier"].Time(); !got.IsZero() {
+		t.Errorf("expected no time to be recorded, got %v", got)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+// OwnedFieldsInVersion returns the fields of live that manager owns,
+// expressed at version rather than at the version of its managed fields
+// (see convertSet). Fields that manager owns but that aren't in live are
+// not returned, whether a conversion is needed or not.
+func (s *Updater) OwnedFieldsInVersion(live *typed.TypedValue, managers fieldpath.ManagedFields, manager string, version fieldpath.APIVersion) (*fieldpath.Set, error) {
+	managerSet, ok := managers[manager]
+	if !ok {
+		return nil, fmt.Errorf("manager %q doesn't own any fields", manager)
+	}
+	set, err := s.convertSet(live, managerSet.Set(), managerSet.APIVersion(), version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert owned fields of %q: %v", manager, err)
+	}
+	return set, nil
+}
+
+// convertSet returns the fields of set, at version from, that object has,
+// expressed at version to. Since sets can't be converted on their own, the
+// fields are extracted from object converted to from, along with the list
+// items they are in and their key fields, converted to to, and collected
+// back. The list items and key fields that set doesn't have are then
+// removed, after being converted the same way.
+func (s *Updater) convertSet(object *typed.TypedValue, set *fieldpath.Set, from, to fieldpath.APIVersion) (*fieldpath.Set, error) {
+	versioned, err := s.Converter.Convert(object, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert object (%v) to version %v: %v", object.TypeRef(), from, err)
+	}
+	// extract returns the fields of versioned in items, with the list items
+	// they are in and their key fields, at version from and at version to.
+	extract := func(items *fieldpath.Set) (fromSet, toSet *fieldpath.Set, err error) {
+		extracted, err := versioned.ExtractItemsWithError(items.Leaves(), typed.WithAppendKeyFields())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to extract fields: %v", err)
+		}
+		if fromSet, err = extracted.ToFieldSet(); err != nil {
+			return nil, nil, fmt.Errorf("failed to get field set: %v", err)
+		}
+		if from == to {
+			return fromSet, fromSet, nil
+		}
+		if extracted, err = s.Converter.Convert(extracted, to); err != nil {
+			return nil, nil, fmt.Errorf("failed to convert fields to version %v: %v", to, err)
+		}
+		if toSet, err = extracted.ToFieldSet(); err != nil {
+			return nil, nil, fmt.Errorf("failed to get field set: %v", err)
+		}
+		return fromSet, toSet, nil
+	}
+	fromSet, toSet, err := extract(set)
+	if err != nil {
+		return nil, err
+	}
+	if from == to {
+		return fromSet.Intersection(set), nil
+	}
+	added := fromSet.Difference(set)
+	if added.Empty() {
+		return toSet, nil
+	}
+	// The added fields are found at version to by extracting them on their
+	// own, which adds back the fields of set they are keyed by, if any.
+	addedFrom, addedTo, err := extract(added)
+	if err != nil {
+		return nil, err
+	}
+	if keep := addedFrom.Intersection(set); !keep.Empty() {
+		_, keepTo, err := extract(keep)
+		if err != nil {
+			return nil, err
+		}
+		addedTo = addedTo.Difference(keepTo)
+	}
+	return toSet.Difference(addedTo), nil
+}
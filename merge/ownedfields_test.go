@@ -0,0 +1,178 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath/fieldpathtesting"
+	. "sigs.k8s.io/structured-merge-diff/v4/internal/fixture"
+	"sigs.k8s.io/structured-merge-diff/v4/merge"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+func TestOwnedFieldsInVersion(t *testing.T) {
+	updater := &merge.Updater{Converter: renamingConverter{structMultiversionParser}}
+	live, err := structMultiversionParser.Type("v1").FromYAML(`{
+		"struct": {
+			"name": "a",
+			"scalarField_v1": "b",
+			"complexField_v1": {"name": "c"}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	managers := fieldpath.ManagedFields{
+		"manager": fieldpath.NewVersionedSet(_NS(
+			_P("struct", "scalarField_v1"),
+			_P("struct", "complexField_v1", "name"),
+			// Not in live.
+			_P("version"),
+		), "v1", true),
+		"other": fieldpath.NewVersionedSet(_NS(_P("struct", "name")), "v1", false),
+	}
+
+	set, err := updater.OwnedFieldsInVersion(live, managers, "manager", "v2")
+	if err != nil {
+		t.Fatalf("Failed to get owned fields: %v", err)
+	}
+	fieldpathtesting.AssertSetEquals(t, set, _NS(
+		_P("struct", "scalarField_v2"),
+		_P("struct", "complexField_v2", "name"),
+	))
+
+	set, err = updater.OwnedFieldsInVersion(live, managers, "manager", "v1")
+	if err != nil {
+		t.Fatalf("Failed to get owned fields: %v", err)
+	}
+	// Fields that aren't in live aren't returned either without a
+	// conversion.
+	fieldpathtesting.AssertSetEquals(t, set, _NS(
+		_P("struct", "scalarField_v1"),
+		_P("struct", "complexField_v1", "name"),
+	))
+
+	if _, err := updater.OwnedFieldsInVersion(live, managers, "unknown", "v2"); err == nil || !strings.Contains(err.Error(), `manager "unknown" doesn't own any fields`) {
+		t.Errorf("Expected an unknown manager error, got %v", err)
+	}
+	if _, err := updater.OwnedFieldsInVersion(live, managers, "manager", "v4"); err == nil || !strings.Contains(err.Error(), "failed to convert owned fields") {
+		t.Errorf("Expected a conversion error, got %v", err)
+	}
+}
+
+var listMultiversionParser = func() Parser {
+	parser, err := typed.NewParser(`types:
+- name: v1
+  map:
+    fields:
+      - name: list
+        type:
+          list:
+            elementType:
+              namedType: item_v1
+            elementRelationship: associative
+            keys:
+            - name
+- name: item_v1
+  map:
+    fields:
+      - name: name
+        type:
+          scalar: string
+      - name: value_v1
+        type:
+          scalar: string
+      - name: other_v1
+        type:
+          scalar: string
+- name: v2
+  map:
+    fields:
+      - name: list
+        type:
+          list:
+            elementType:
+              namedType: item_v2
+            elementRelationship: associative
+            keys:
+            - name
+- name: item_v2
+  map:
+    fields:
+      - name: name
+        type:
+          scalar: string
+      - name: value_v2
+        type:
+          scalar: string
+      - name: other_v2
+        type:
+          scalar: string
+`)
+	if err != nil {
+		panic(err)
+	}
+	return parser
+}()
+
+func TestOwnedFieldsInVersionListItems(t *testing.T) {
+	updater := &merge.Updater{Converter: renamingConverter{listMultiversionParser}}
+	live, err := listMultiversionParser.Type("v1").FromYAML(`{
+		"list": [
+			{"name": "a", "value_v1": "1", "other_v1": "2"},
+			{"name": "b", "value_v1": "3", "other_v1": "4"}
+		]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	// The manager created item a, but only set a field of item b.
+	managers := fieldpath.ManagedFields{
+		"manager": fieldpath.NewVersionedSet(_NS(
+			_P("list", _KBF("name", "a")),
+			_P("list", _KBF("name", "a"), "name"),
+			_P("list", _KBF("name", "a"), "value_v1"),
+			_P("list", _KBF("name", "b"), "value_v1"),
+			// Not in live.
+			_P("list", _KBF("name", "c"), "value_v1"),
+		), "v1", true),
+	}
+
+	for version, expected := range map[fieldpath.APIVersion]*fieldpath.Set{
+		"v1": _NS(
+			_P("list", _KBF("name", "a")),
+			_P("list", _KBF("name", "a"), "name"),
+			_P("list", _KBF("name", "a"), "value_v1"),
+			_P("list", _KBF("name", "b"), "value_v1"),
+		),
+		"v2": _NS(
+			_P("list", _KBF("name", "a")),
+			_P("list", _KBF("name", "a"), "name"),
+			_P("list", _KBF("name", "a"), "value_v2"),
+			_P("list", _KBF("name", "b"), "value_v2"),
+		),
+	} {
+		set, err := updater.OwnedFieldsInVersion(live, managers, "manager", version)
+		if err != nil {
+			t.Fatalf("Failed to get owned fields at %v: %v", version, err)
+		}
+		fieldpathtesting.AssertSetEquals(t, set, expected)
+	}
+}
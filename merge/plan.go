@@ -0,0 +1,116 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+// ApplyPlan is the result of an apply that hasn't been committed yet (see
+// PrepareApply), so that callers can check it against external constraints
+// before committing it.
+type ApplyPlan struct {
+	// Object is the object resulting from the apply, like the object
+	// returned by Apply: it is nil if the apply doesn't change the live
+	// object, unless the updater returns its input on no-ops.
+	Object *typed.TypedValue
+	// Managers are the managed fields resulting from the apply.
+	Managers fieldpath.ManagedFields
+	// Conflicts are the conflicts that prevent the apply, if any. Plans
+	// with conflicts can't be committed.
+	Conflicts Conflicts
+
+	updater *Updater
+	// merged is the object resulting from the apply, even on no-ops.
+	merged *typed.TypedValue
+	// created are the list items created by the apply, if reported.
+	created *fieldpath.Set
+}
+
+// PrepareApply computes the result of applying configObject like Apply
+// does, but returns it as a plan that is only final once committed. Conflicts
+// are part of the plan, while other failures are returned as errors.
+// managers isn't modified.
+func (s *Updater) PrepareApply(liveObject, configObject *typed.TypedValue, version fieldpath.APIVersion, managers fieldpath.ManagedFields, manager string, force bool) (*ApplyPlan, error) {
+	plan, err := s.apply(liveObject, configObject, version, managers.Copy(), manager, force)
+	if c, ok := err.(Conflicts); ok {
+		return &ApplyPlan{Conflicts: c}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// PreviewApply returns the fields that applying configObject as manager
+// would give it ownership of, in addition to the fields it already owns,
+// along with the conflicts that would prevent the apply unless forced. The
+// fields are at version; if manager currently owns fields at another
+// version, they are converted to version by converting the fields of
+// liveObject. managers isn't modified.
+//
+// The applied object is only merged to find the conflicts: the hooks of
+// the updater aren't called, and empty containers aren't dropped.
+func (s *Updater) PreviewApply(liveObject, configObject *typed.TypedValue, version fieldpath.APIVersion, managers fieldpath.ManagedFields, manager string) (takeover *fieldpath.Set, conflicts Conflicts, err error) {
+	manager = s.canonicalManager(manager)
+	managers, err = s.canonicalizeManagers(managers.Copy())
+	if err != nil {
+		return nil, nil, err
+	}
+	owned := fieldpath.NewSet()
+	if managerSet, ok := managers[manager]; ok {
+		owned, err = s.convertSet(liveObject, managerSet.Set(), managerSet.APIVersion(), version)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to convert owned fields of %q: %v", manager, err)
+		}
+	}
+
+	updater := *s
+	updater.dropEmptyContainers = false
+	updater.reportCreatedListItems = nil
+	updater.tracer = nil
+	_, err = updater.apply(liveObject, configObject, version, managers, manager, false)
+	if c, ok := err.(Conflicts); ok {
+		conflicts = c
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	set, err := s.appliedSet(configObject, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	return set.Difference(owned), conflicts, nil
+}
+
+// Commit finalizes the plan, calling the hooks of the updater that report
+// on applies (see WarnAtomicCoownership and ReportCreatedListItems), and
+// returns the resulting object and managed fields, as returned by Apply.
+// Committing a plan with conflicts returns nothing.
+func (p *ApplyPlan) Commit() (*typed.TypedValue, fieldpath.ManagedFields) {
+	if len(p.Conflicts) != 0 {
+		return nil, nil
+	}
+	p.updater.warn(p.merged, p.Managers)
+	if p.updater.reportCreatedListItems != nil {
+		p.updater.reportCreatedListItems(p.created)
+	}
+	return p.Object, p.Managers
+}
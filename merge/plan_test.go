@@ -0,0 +1,136 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/merge"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+func TestPrepareApply(t *testing.T) {
+	var created *fieldpath.Set
+	updater := buildUpdater(merge.UpdaterBuilder{
+		ReportCreatedListItems: func(s *fieldpath.Set) {
+			created = s
+		},
+	})
+	parse := objectParser(t, associativeListParser, "v1")
+	live := parse(`{"list": [{"name": "a", "value": 1}]}`)
+	managers := fieldpath.ManagedFields{
+		"controller": fieldpath.NewVersionedSet(_NS(
+			_P("list", _KBF("name", "a")),
+			_P("list", _KBF("name", "a"), "name"),
+			_P("list", _KBF("name", "a"), "value"),
+		), "v1", false),
+	}
+	config := parse(`{"list": [{"name": "b", "value": 2}]}`)
+
+	expectedObject, expectedManagers, err := updater.Apply(live, config, "v1", managers.Copy(), "applier", false)
+	if err != nil {
+		t.Fatalf("Failed to apply: %v", err)
+	}
+	created = nil
+
+	original := managers.Copy()
+	plan, err := updater.PrepareApply(live, config, "v1", managers, "applier", false)
+	if err != nil {
+		t.Fatalf("Failed to prepare apply: %v", err)
+	}
+	if plan.Conflicts != nil {
+		t.Fatalf("Unexpected conflicts: %v", plan.Conflicts)
+	}
+	if !managers.Equals(original) {
+		t.Errorf("expected managers to be unchanged by prepare, got %v", managers)
+	}
+	if created != nil {
+		t.Errorf("expected hooks not to be called before commit, got %v", created)
+	}
+
+	object, newManagers := plan.Commit()
+	if !value.Equals(object.AsValue(), expectedObject.AsValue()) {
+		t.Errorf("expected object %v, got %v", value.ToString(expectedObject.AsValue()), value.ToString(object.AsValue()))
+	}
+	if !newManagers.Equals(expectedManagers) {
+		t.Errorf("expected managers %v, got %v", expectedManagers, newManagers)
+	}
+	if expected := _NS(_P("list", _KBF("name", "b"))); !created.Equals(expected) {
+		t.Errorf("expected created items %v on commit, got %v", expected, created)
+	}
+}
+
+func TestPrepareApplyConflicts(t *testing.T) {
+	updater := buildUpdater(merge.UpdaterBuilder{})
+	parse := objectParser(t, leafFieldsParser, "v1")
+	live := parse(`{"numeric": 1}`)
+	managers := fieldpath.ManagedFields{
+		"controller": fieldpath.NewVersionedSet(_NS(_P("numeric")), "v1", false),
+	}
+
+	plan, err := updater.PrepareApply(live, parse(`{"numeric": 2}`), "v1", managers, "applier", false)
+	if err != nil {
+		t.Fatalf("Failed to prepare apply: %v", err)
+	}
+	expected := merge.Conflicts{{Manager: "controller", Path: _P("numeric")}}
+	if !plan.Conflicts.Equals(expected) {
+		t.Errorf("expected conflicts %v, got %v", expected, plan.Conflicts)
+	}
+	if object, newManagers := plan.Commit(); object != nil || newManagers != nil {
+		t.Errorf("expected plan with conflicts not to commit, got %v, %v", object, newManagers)
+	}
+}
+
+func TestPreviewApply(t *testing.T) {
+	updater := buildUpdater(merge.UpdaterBuilder{})
+	parse := objectParser(t, leafFieldsParser, "v1")
+	live := parse(`{"numeric": 1, "string": "a"}`)
+	managers := fieldpath.ManagedFields{
+		"controller": fieldpath.NewVersionedSet(_NS(_P("numeric")), "v1", false),
+		"applier":    fieldpath.NewVersionedSet(_NS(_P("string")), "v1", true),
+	}
+	original := managers.Copy()
+
+	config := parse(`{"numeric": 2, "string": "a", "bool": true}`)
+	takeover, conflicts, err := updater.PreviewApply(live, config, "v1", managers, "applier")
+	if err != nil {
+		t.Fatalf("Failed to preview apply: %v", err)
+	}
+	if expected := _NS(_P("numeric"), _P("bool")); !takeover.Equals(expected) {
+		t.Errorf("expected takeover %v, got %v", expected, takeover)
+	}
+	expected := merge.Conflicts{{Manager: "controller", Path: _P("numeric")}}
+	if !conflicts.Equals(expected) {
+		t.Errorf("expected conflicts %v, got %v", expected, conflicts)
+	}
+	if !managers.Equals(original) {
+		t.Errorf("expected managers to be unchanged by preview, got %v", managers)
+	}
+
+	config = parse(`{"numeric": 1, "bool": true}`)
+	takeover, conflicts, err = updater.PreviewApply(live, config, "v1", managers, "applier")
+	if err != nil {
+		t.Fatalf("Failed to preview apply: %v", err)
+	}
+	if expected := _NS(_P("numeric"), _P("bool")); !takeover.Equals(expected) {
+		t.Errorf("expected takeover %v, got %v", expected, takeover)
+	}
+	if conflicts != nil {
+		t.Errorf("expected no conflicts, got %v", conflicts)
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/merge"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+func TestPrivilegedManagers(t *testing.T) {
+	parse := objectParser(t, leafFieldsParser, "v1")
+	updater := buildUpdater(merge.UpdaterBuilder{
+		ManagerAliases: map[string]string{
+			"controller-a": "controller",
+		},
+		PrivilegedManagers: map[string]bool{
+			"controller": true,
+		},
+	})
+
+	live, managers, err := updater.Apply(parse(`{}`), parse(`{"numeric": 1, "string": "a"}`), "v1", fieldpath.ManagedFields{}, "user", false)
+	if err != nil {
+		t.Fatalf("Failed to apply: %v", err)
+	}
+
+	// A non-privileged manager conflicts.
+	_, _, err = updater.Apply(live, parse(`{"numeric": 2}`), "v1", managers, "other", false)
+	if conflicts, ok := err.(merge.Conflicts); !ok || len(conflicts) != 1 || conflicts[0].Manager != "user" {
+		t.Fatalf("Expected a single conflict with user, got %v", err)
+	}
+
+	// A privileged manager, here through an alias, takes the field over.
+	live, managers, err = updater.Apply(live, parse(`{"numeric": 2}`), "v1", managers, "controller-a", false)
+	if err != nil {
+		t.Fatalf("Failed to apply: %v", err)
+	}
+	if expected := parse(`{"numeric": 2, "string": "a"}`); !value.Equals(live.AsValue(), expected.AsValue()) {
+		t.Fatalf("Expected object:\n%v\ngot:\n%v", value.ToString(expected.AsValue()), value.ToString(live.AsValue()))
+	}
+	if expected := (fieldpath.ManagedFields{
+		"controller": fieldpath.NewVersionedSet(_NS(_P("numeric")), "v1", true),
+		"user":       fieldpath.NewVersionedSet(_NS(_P("string")), "v1", true),
+	}); !managers.Equals(expected) {
+		t.Fatalf("Expected managers:\n%v\ngot:\n%v", expected, managers)
+	}
+}
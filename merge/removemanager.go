@@ -0,0 +1,61 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+// RemoveManager removes the contribution of manager from live, which is
+// at version: the fields, list and map items it owns that no other manager
+// owns are removed from the object, like an empty apply would prune them,
+// and manager is dropped from the managed fields. Fields co-owned with
+// other managers are kept. The object is returned at version. If manager
+// doesn't own any fields, live and managers are returned unchanged. An
+// error is returned if live can't be converted to the version of manager's
+// managed fields, rather than dropping manager without removing its fields.
+func (s *Updater) RemoveManager(live *typed.TypedValue, version fieldpath.APIVersion, managers fieldpath.ManagedFields, manager string) (*typed.TypedValue, fieldpath.ManagedFields, error) {
+	managerSet, ok := managers[manager]
+	if !ok {
+		return live, managers, nil
+	}
+	// prune leaves the object as is if it can't be converted to the
+	// version of the managed fields, as is right for obsolete versions of
+	// the applier, but not here.
+	if _, err := s.Converter.Convert(live, managerSet.APIVersion()); err != nil {
+		return nil, nil, fmt.Errorf("failed to convert live object (%v) to version %v of the fields of %q: %v", live.TypeRef(), managerSet.APIVersion(), manager, err)
+	}
+	newManagers := fieldpath.ManagedFields{}
+	for name, set := range managers {
+		if name != manager {
+			newManagers[name] = set
+		}
+	}
+	// prune expects the managed fields to include the pruning manager, give
+	// it nothing so that it doesn't add back any of its fields, at version
+	// so that the pruned object is converted back to version.
+	pruneManagers := newManagers.Copy()
+	pruneManagers[manager] = fieldpath.NewVersionedSet(fieldpath.NewSet(), version, managerSet.Applied())
+	object, err := s.prune(live, pruneManagers, manager, managerSet)
+	if err != nil {
+		return nil, nil, err
+	}
+	return object, newManagers, nil
+}
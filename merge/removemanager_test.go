@@ -0,0 +1,127 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/merge"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+func TestRemoveManager(t *testing.T) {
+	updater := buildUpdater(merge.UpdaterBuilder{})
+	parse := objectParser(t, associativeListParser, "v1")
+	live := parse(`{"list": []}`)
+	managers := fieldpath.ManagedFields{}
+	for _, op := range []struct {
+		manager string
+		config  typed.YAMLObject
+	}{
+		{"a", `{"list": [{"name": "a", "value": 1}, {"name": "b", "value": 2}]}`},
+		{"b", `{"list": [{"name": "b", "value": 2}, {"name": "c", "value": 3}]}`},
+	} {
+		var err error
+		live, managers, err = updater.Apply(live, parse(op.config), "v1", managers, op.manager, false)
+		if err != nil {
+			t.Fatalf("Failed to apply for %v: %v", op.manager, err)
+		}
+	}
+	updated := parse(`{"list": [{"name": "a", "value": 1}, {"name": "b", "value": 2}, {"name": "c", "value": 3}, {"name": "d", "value": 4}]}`)
+	live, managers, err := updater.Update(live, updated, "v1", managers, "controller")
+	if err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+	ownedByB := managers["b"].Set()
+	ownedByController := managers["controller"].Set()
+
+	object, newManagers, err := updater.RemoveManager(live, "v1", managers, "a")
+	if err != nil {
+		t.Fatalf("Failed to remove manager: %v", err)
+	}
+	// Item a was only owned by a, item b is co-owned with b.
+	expected := parse(`{"list": [{"name": "b", "value": 2}, {"name": "c", "value": 3}, {"name": "d", "value": 4}]}`)
+	if comparison, err := object.Compare(expected); err != nil || !comparison.IsSame() {
+		t.Errorf("expected object %v, got %v", value.ToString(expected.AsValue()), value.ToString(object.AsValue()))
+	}
+	if _, ok := newManagers["a"]; ok {
+		t.Errorf("expected a to be removed from the managed fields, got %v", newManagers["a"].Set())
+	}
+	if got := newManagers["b"].Set(); !got.Equals(ownedByB) {
+		t.Errorf("expected b's fields to be unchanged, got %v", got)
+	}
+	if got := newManagers["controller"].Set(); !got.Equals(ownedByController) {
+		t.Errorf("expected controller's fields to be unchanged, got %v", got)
+	}
+	if _, ok := managers["a"]; !ok {
+		t.Errorf("expected the original managed fields to be unchanged")
+	}
+}
+
+func TestRemoveManagerUnknownManager(t *testing.T) {
+	updater := buildUpdater(merge.UpdaterBuilder{})
+	live := objectParser(t, associativeListParser, "v1")(`{"list": [{"name": "a", "value": 1}]}`)
+	managers := fieldpath.ManagedFields{
+		"b": fieldpath.NewVersionedSet(_NS(_P("list", _KBF("name", "a"))), "v1", true),
+	}
+	object, newManagers, err := updater.RemoveManager(live, "v1", managers, "a")
+	if err != nil {
+		t.Fatalf("Failed to remove manager: %v", err)
+	}
+	if object != live || !newManagers.Equals(managers) {
+		t.Errorf("expected object and managers to be unchanged, got %v and %v", value.ToString(object.AsValue()), newManagers)
+	}
+}
+
+func TestRemoveManagerConvertsBack(t *testing.T) {
+	updater := buildUpdater(merge.UpdaterBuilder{Converter: renamingConverter{structMultiversionParser}})
+	parse := objectParser(t, structMultiversionParser, "v1")
+	live := parse(`{
+		"struct": {
+			"name": "a",
+			"scalarField_v1": "b"
+		}
+	}`)
+	managers := fieldpath.ManagedFields{
+		"a": fieldpath.NewVersionedSet(_NS(_P("struct", "scalarField_v2")), "v2", true),
+		"b": fieldpath.NewVersionedSet(_NS(_P("struct", "name")), "v1", true),
+	}
+	object, newManagers, err := updater.RemoveManager(live, "v1", managers, "a")
+	if err != nil {
+		t.Fatalf("Failed to remove manager: %v", err)
+	}
+	expected := parse(`{"struct": {"name": "a"}}`)
+	if comparison, err := object.Compare(expected); err != nil || !comparison.IsSame() {
+		t.Errorf("expected object %v at v1, got %v (%v)", value.ToString(expected.AsValue()), value.ToString(object.AsValue()), err)
+	}
+	if _, ok := newManagers["a"]; ok {
+		t.Errorf("expected a to be removed from the managed fields, got %v", newManagers["a"].Set())
+	}
+}
+
+func TestRemoveManagerMissingVersion(t *testing.T) {
+	updater := buildUpdater(merge.UpdaterBuilder{})
+	live := objectParser(t, associativeListParser, "v1")(`{"list": [{"name": "a", "value": 1}]}`)
+	managers := fieldpath.ManagedFields{
+		"a": fieldpath.NewVersionedSet(_NS(_P("list", _KBF("name", "a"))), "v2", true),
+	}
+	if _, _, err := updater.RemoveManager(live, "v1", managers, "a"); err == nil {
+		t.Error("expected an error removing a manager at a missing version")
+	}
+}
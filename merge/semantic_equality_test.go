@@ -0,0 +1,168 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	. "sigs.k8s.io/structured-merge-diff/v4/internal/fixture"
+	"sigs.k8s.io/structured-merge-diff/v4/merge"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+var quantityParser = func() Parser {
+	parser, err := typed.NewParser(`types:
+- name: resources
+  map:
+    fields:
+    - name: port
+      type:
+        scalar: numeric
+    - name: memory
+      type:
+        namedType: quantity
+- name: quantity
+  scalar: string
+`)
+	if err != nil {
+		panic(err)
+	}
+	return SameVersionParser{T: parser.Type("resources")}
+}()
+
+// quantityBytes parses the simple quantities used in these tests.
+func quantityBytes(q string) (int64, bool) {
+	multiplier := int64(1)
+	for suffix, m := range map[string]int64{"Ki": 1 << 10, "Mi": 1 << 20, "Gi": 1 << 30} {
+		if strings.HasSuffix(q, suffix) {
+			q = strings.TrimSuffix(q, suffix)
+			multiplier = m
+			break
+		}
+	}
+	n, err := strconv.ParseInt(q, 10, 64)
+	return n * multiplier, err == nil
+}
+
+var quantityEqualities = typed.Equalities{
+	"quantity": func(lhs, rhs value.Value) bool {
+		if !lhs.IsString() || !rhs.IsString() {
+			return false
+		}
+		l, lok := quantityBytes(lhs.AsString())
+		r, rok := quantityBytes(rhs.AsString())
+		return lok && rok && l == r
+	},
+}
+
+// quantityScalarEqual compares the memory field as a quantity.
+func quantityScalarEqual(lhs, rhs value.Value, path fieldpath.Path) bool {
+	if path.String() != ".memory" {
+		return value.Equals(lhs, rhs)
+	}
+	return quantityEqualities["quantity"](lhs, rhs)
+}
+
+func TestSemanticallyEqualValuesDontConflict(t *testing.T) {
+	tests := map[string]struct {
+		equalities  typed.Equalities
+		scalarEqual typed.ScalarEqual
+		updated     typed.YAMLObject
+		applied     typed.YAMLObject
+		conflict    bool
+	}{
+		"int_float": {
+			updated: `{"port": 80}`,
+			applied: `{"port": 80.0}`,
+		},
+		"different_numbers": {
+			updated:  `{"port": 80}`,
+			applied:  `{"port": 80.5}`,
+			conflict: true,
+		},
+		"quantity_without_equality": {
+			updated:  `{"memory": "1Gi"}`,
+			applied:  `{"memory": "1024Mi"}`,
+			conflict: true,
+		},
+		"quantity_with_equality": {
+			equalities: quantityEqualities,
+			updated:    `{"memory": "1Gi"}`,
+			applied:    `{"memory": "1024Mi"}`,
+		},
+		"different_quantity_with_equality": {
+			equalities: quantityEqualities,
+			updated:    `{"memory": "1Gi"}`,
+			applied:    `{"memory": "1023Mi"}`,
+			conflict:   true,
+		},
+		"quantity_with_scalar_equal": {
+			scalarEqual: quantityScalarEqual,
+			updated:     `{"memory": "1Gi"}`,
+			applied:     `{"memory": "1024Mi"}`,
+		},
+		"different_quantity_with_scalar_equal": {
+			scalarEqual: quantityScalarEqual,
+			updated:     `{"memory": "1Gi"}`,
+			applied:     `{"memory": "1023Mi"}`,
+			conflict:    true,
+		},
+		"different_numbers_with_scalar_equal": {
+			scalarEqual: quantityScalarEqual,
+			updated:     `{"port": 80}`,
+			applied:     `{"port": 81}`,
+			conflict:    true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := merge.UpdaterBuilder{
+				Converter: &specificVersionConverter{
+					AcceptedVersions: []fieldpath.APIVersion{"v1"},
+				},
+				Equalities:  test.equalities,
+				ScalarEqual: test.scalarEqual,
+			}
+			state := State{
+				Updater: builder.BuildUpdater(),
+				Parser:  quantityParser,
+			}
+			if err := state.Update(test.updated, "v1", "controller"); err != nil {
+				t.Fatalf("Failed to update: %v", err)
+			}
+			err := state.Apply(test.applied, "v1", "applier", false)
+			if _, ok := err.(merge.Conflicts); ok != test.conflict {
+				t.Fatalf("Expected conflict: %v, got %v", test.conflict, err)
+			}
+			if test.conflict {
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to apply: %v", err)
+			}
+			// Both managers own the field.
+			controller, applier := state.Managers["controller"].Set(), state.Managers["applier"].Set()
+			if controller.Empty() || !controller.Equals(applier) {
+				t.Errorf("Expected both managers to own the same fields, got %v and %v", controller, applier)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+// TakeoverConfiguration returns the configuration that manager "to" would
+// need to apply in order to take over the fields currently owned by manager
+// "from", while keeping the fields it already owns. The configuration is
+// extracted from live and is at the version of "from"'s managed fields,
+// which is the version it must be applied at. Since the values are those of
+// live, applying the configuration doesn't conflict: "to" then shares the
+// ownership of the fields with "from", which can give them up with an empty
+// apply.
+func (s *Updater) TakeoverConfiguration(live *typed.TypedValue, managers fieldpath.ManagedFields, from, to string) (*typed.TypedValue, error) {
+	fromSet, ok := managers[from]
+	if !ok {
+		return nil, fmt.Errorf("manager %q doesn't own any fields", from)
+	}
+	version := fromSet.APIVersion()
+	versioned, err := s.Converter.Convert(live, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert live object (%v) to version %v: %v", live.TypeRef(), version, err)
+	}
+	config, err := versioned.ExtractItemsWithError(fromSet.Set().Leaves(), typed.WithAppendKeyFields())
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract owned fields of %q: %v", from, err)
+	}
+	toSet, ok := managers[to]
+	if !ok {
+		return config, nil
+	}
+	if toSet.APIVersion() == version {
+		return versioned.ExtractItemsWithError(fromSet.Set().Union(toSet.Set()).Leaves(), typed.WithAppendKeyFields())
+	}
+	owned, err := s.Converter.Convert(live, toSet.APIVersion())
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert live object (%v) to version %v: %v", live.TypeRef(), toSet.APIVersion(), err)
+	}
+	owned, err = owned.ExtractItemsWithError(toSet.Set().Leaves(), typed.WithAppendKeyFields())
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract owned fields of %q: %v", to, err)
+	}
+	owned, err = s.Converter.Convert(owned, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert owned fields of %q to version %v: %v", to, version, err)
+	}
+	return owned.Merge(config)
+}
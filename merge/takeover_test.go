@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/merge"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+func TestTakeoverConfiguration(t *testing.T) {
+	updater := buildUpdater(merge.UpdaterBuilder{})
+	parse := objectParser(t, associativeListParser, "v1")
+	live := parse(`{"list": []}`)
+	managers := fieldpath.ManagedFields{}
+	for _, op := range []struct {
+		manager string
+		config  typed.YAMLObject
+	}{
+		{"a", `{"list": [{"name": "a", "value": 1}]}`},
+		{"b", `{"list": [{"name": "b", "value": 2}, {"name": "c", "value": 3}]}`},
+		{"c", `{"list": [{"name": "d", "value": 4}]}`},
+	} {
+		var err error
+		live, managers, err = updater.Apply(live, parse(op.config), "v1", managers, op.manager, false)
+		if err != nil {
+			t.Fatalf("Failed to apply for %v: %v", op.manager, err)
+		}
+	}
+	formerlyOwnedByB := managers["b"].Set()
+	ownedByC := managers["c"].Set()
+
+	config, err := updater.TakeoverConfiguration(live, managers, "b", "a")
+	if err != nil {
+		t.Fatalf("Failed to get takeover configuration: %v", err)
+	}
+	expected := parse(`{"list": [{"name": "a", "value": 1}, {"name": "b", "value": 2}, {"name": "c", "value": 3}]}`)
+	if comparison, err := config.Compare(expected); err != nil || !comparison.IsSame() {
+		t.Fatalf("expected configuration %v, got %v", value.ToString(expected.AsValue()), value.ToString(config.AsValue()))
+	}
+
+	object, newManagers, err := updater.Apply(live, config, "v1", managers, "a", false)
+	if err != nil {
+		t.Fatalf("Failed to apply takeover configuration: %v", err)
+	}
+	if object == nil {
+		object = live
+	}
+	if comparison, err := object.Compare(live); err != nil || !comparison.IsSame() {
+		t.Errorf("expected object to be unchanged, got %v", value.ToString(object.AsValue()))
+	}
+	if got := newManagers["b"].Set(); !got.Equals(formerlyOwnedByB) {
+		t.Errorf("expected b to share its fields, got %v", got)
+	}
+	expectedA := _NS(
+		_P("list", _KBF("name", "a")),
+		_P("list", _KBF("name", "a"), "name"),
+		_P("list", _KBF("name", "a"), "value"),
+	).Union(formerlyOwnedByB)
+	if got := newManagers["a"].Set(); !got.Equals(expectedA) {
+		t.Errorf("expected a to own\n%v\ngot\n%v", expectedA, got)
+	}
+	if got := newManagers["c"].Set(); !got.Equals(ownedByC) {
+		t.Errorf("expected c's fields to be unchanged, got %v", got)
+	}
+
+	// Once b gives up its fields, a is left as their only owner.
+	object, newManagers, err = updater.Apply(object, parse(`{}`), "v1", newManagers, "b", false)
+	if err != nil {
+		t.Fatalf("Failed to apply empty configuration: %v", err)
+	}
+	if object == nil {
+		object = live
+	}
+	if comparison, err := object.Compare(live); err != nil || !comparison.IsSame() {
+		t.Errorf("expected object to be unchanged, got %v", value.ToString(object.AsValue()))
+	}
+	if _, ok := newManagers["b"]; ok {
+		t.Errorf("expected b to own no fields, got %v", newManagers["b"].Set())
+	}
+	if got := newManagers["a"].Set(); !got.Equals(expectedA) {
+		t.Errorf("expected a to own\n%v\ngot\n%v", expectedA, got)
+	}
+}
+
+func TestTakeoverConfigurationUnknownManager(t *testing.T) {
+	updater := buildUpdater(merge.UpdaterBuilder{})
+	live := objectParser(t, associativeListParser, "v1")(`{"list": []}`)
+	if _, err := updater.TakeoverConfiguration(live, fieldpath.ManagedFields{}, "b", "a"); err == nil {
+		t.Error("expected error for manager without fields")
+	}
+}
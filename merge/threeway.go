@@ -0,0 +1,49 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+// ThreeWay computes the classic three-way merge of the configuration
+// modified into current, given the configuration original that was
+// previously applied, without tracking managed fields: the fields that
+// modified removes from original are removed from current, the fields that
+// modified sets are merged into current, and the fields that are only in
+// current are kept. Atomic maps and lists are replaced as a whole, while
+// granular ones are merged field by field or item by item. original may be
+// nil if nothing was applied before, in which case nothing is removed.
+func ThreeWay(original, modified, current *typed.TypedValue) (*typed.TypedValue, error) {
+	result := current
+	if original != nil {
+		compare, err := original.Compare(modified)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare original and modified objects: %v", err)
+		}
+		if result, err = current.RemoveItemsWithError(compare.Removed); err != nil {
+			return nil, fmt.Errorf("failed to remove fields from current object: %v", err)
+		}
+	}
+	merged, err := result.Merge(modified)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge modified object: %v", err)
+	}
+	return merged, nil
+}
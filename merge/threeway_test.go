@@ -0,0 +1,138 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/merge"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+var threeWayParser = func() *typed.Parser {
+	parser, err := typed.NewParser(`types:
+- name: root
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    - name: labels
+      type:
+        map:
+          elementType:
+            scalar: string
+    - name: args
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+    - name: containers
+      type:
+        list:
+          elementType:
+            namedType: container
+          elementRelationship: associative
+          keys:
+          - name
+- name: container
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    - name: image
+      type:
+        scalar: string
+    - name: pullPolicy
+      type:
+        scalar: string
+`)
+	if err != nil {
+		panic(err)
+	}
+	return parser
+}()
+
+func TestThreeWay(t *testing.T) {
+	tests := []struct {
+		name     string
+		original typed.YAMLObject
+		modified typed.YAMLObject
+		current  typed.YAMLObject
+		expected typed.YAMLObject
+	}{
+		{
+			name:     "granular-map",
+			original: `{"labels": {"app": "web", "tier": "front"}}`,
+			modified: `{"labels": {"app": "api"}}`,
+			current:  `{"labels": {"app": "web", "tier": "front", "team": "x"}}`,
+			expected: `{"labels": {"app": "api", "team": "x"}}`,
+		},
+		{
+			name:     "atomic-list",
+			original: `{"args": ["a", "b"]}`,
+			modified: `{"args": ["a"]}`,
+			current:  `{"args": ["a", "b", "c"]}`,
+			expected: `{"args": ["a"]}`,
+		},
+		{
+			name:     "associative-list",
+			original: `{"containers": [{"name": "a", "image": "a:1"}, {"name": "b", "image": "b:1"}]}`,
+			modified: `{"containers": [{"name": "a", "image": "a:2"}]}`,
+			current:  `{"containers": [{"name": "a", "image": "a:1", "pullPolicy": "Always"}, {"name": "b", "image": "b:1"}, {"name": "c", "image": "c:1"}]}`,
+			expected: `{"containers": [{"name": "a", "image": "a:2", "pullPolicy": "Always"}, {"name": "c", "image": "c:1"}]}`,
+		},
+		{
+			name:     "removed-field",
+			original: `{"name": "a", "labels": {"app": "web"}}`,
+			modified: `{"labels": {"app": "web"}}`,
+			current:  `{"name": "a", "labels": {"app": "web", "team": "x"}}`,
+			expected: `{"labels": {"app": "web", "team": "x"}}`,
+		},
+		{
+			name:     "no-original",
+			modified: `{"labels": {"app": "api"}}`,
+			current:  `{"name": "a", "labels": {"app": "web"}}`,
+			expected: `{"name": "a", "labels": {"app": "api"}}`,
+		},
+	}
+	parse := objectParser(t, threeWayParser, "root")
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var original *typed.TypedValue
+			if tt.original != "" {
+				original = parse(tt.original)
+			}
+			current := parse(tt.current)
+			got, err := merge.ThreeWay(original, parse(tt.modified), current)
+			if err != nil {
+				t.Fatalf("Failed to merge: %v", err)
+			}
+			expected := parse(tt.expected)
+			if !value.Equals(got.AsValue(), expected.AsValue()) {
+				t.Errorf("expected\n%v\nbut got\n%v", value.ToString(expected.AsValue()), value.ToString(got.AsValue()))
+			}
+			if !value.Equals(current.AsValue(), parse(tt.current).AsValue()) {
+				t.Errorf("expected current to be unchanged, got %v", value.ToString(current.AsValue()))
+			}
+		})
+	}
+}
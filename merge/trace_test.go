@@ -0,0 +1,74 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merge_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/merge"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// rulesTracer records the rules applied to each path.
+type rulesTracer map[string]typed.WalkRule
+
+func (rulesTracer) Enter(fieldpath.Path, value.Value, value.Value) {}
+func (r rulesTracer) Rule(path fieldpath.Path, rule typed.WalkRule) {
+	r[path.String()] = rule
+}
+func (rulesTracer) Leave(fieldpath.Path, value.Value) {}
+
+func TestApplyTracer(t *testing.T) {
+	parse := objectParser(t, nestedTypeParser, "v1")
+	tracer := rulesTracer{}
+	updater := buildUpdater(merge.UpdaterBuilder{
+		Tracer: tracer,
+	})
+	_, _, err := updater.Apply(parse(`{"struct": {"name": "a"}}`), parse(`{"struct": {"name": "b"}, "mapOfMaps": {}}`), "v1", fieldpath.ManagedFields{}, "applier", false)
+	if err != nil {
+		t.Fatalf("Failed to apply: %v", err)
+	}
+	expected := rulesTracer{
+		".struct.name": typed.WalkRuleScalar,
+		".mapOfMaps":   typed.WalkRuleEmpty,
+	}
+	if len(tracer) != len(expected) {
+		t.Fatalf("Expected rules %v, got %v", expected, tracer)
+	}
+	for path, rule := range expected {
+		if tracer[path] != rule {
+			t.Errorf("Expected rule %v for %v, got %v", rule, path, tracer[path])
+		}
+	}
+}
+
+func TestPreviewApplyDoesNotTrace(t *testing.T) {
+	parse := objectParser(t, nestedTypeParser, "v1")
+	tracer := rulesTracer{}
+	updater := buildUpdater(merge.UpdaterBuilder{
+		Tracer: tracer,
+	})
+	_, _, err := updater.PreviewApply(parse(`{"struct": {"name": "a"}}`), parse(`{"struct": {"name": "b"}}`), "v1", fieldpath.ManagedFields{}, "applier")
+	if err != nil {
+		t.Fatalf("Failed to preview apply: %v", err)
+	}
+	if len(tracer) != 0 {
+		t.Errorf("Expected no rules, got %v", tracer)
+	}
+}
@@ -15,6 +15,8 @@ package merge
 
 import (
 	"fmt"
+	"time"
+
 	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
 	"sigs.k8s.io/structured-merge-diff/v4/typed"
 	"sigs.k8s.io/structured-merge-diff/v4/value"
@@ -43,14 +45,90 @@ type UpdaterBuilder struct {
 	// Comparing has become more expensive too now that we're not using
 	// `Compare` but `value.Equals` so this gives an option to avoid it.
 	ReturnInputOnNoop bool
+
+	// Now, if set, provides the time recorded in the sets written by
+	// Update and Apply (see fieldpath.VersionedSet.Time). Sets are not
+	// timestamped otherwise.
+	Now func() time.Time
+
+	// WarnAtomicCoownership, if set, is called after each successful
+	// Update or Apply with the atomic maps and lists of the resulting
+	// object that are owned by more than one manager, if any.
+	WarnAtomicCoownership func([]AtomicCoownership)
+
+	// ReportCreatedListItems, if set, is called after each successful
+	// Apply with the paths of the associative list items that the apply
+	// created (see typed.CreatedListItems).
+	ReportCreatedListItems func(created *fieldpath.Set)
+
+	// Compressor is used by CompressManagedFields and ExpandManagedFields.
+	// The default, nil, doesn't compress managed fields.
+	Compressor ManagedFieldsCompressor
+
+	// Equalities make values of the given types that are represented
+	// differently but semantically equal not conflict.
+	Equalities typed.Equalities
+
+	// ScalarEqual, if set, is used instead of value.Equals to decide
+	// whether scalar fields changed, e.g. to compare quantities
+	// semantically (see typed.CompareOptions).
+	ScalarEqual typed.ScalarEqual
+
+	// MaxResultSize, if positive, makes Apply fail as soon as merging the
+	// configuration into the live object visits more than this many nodes
+	// (see typed.MergeOptions.MaxSize), e.g. to protect against
+	// configurations that add huge lists.
+	MaxResultSize int
+
+	// DropEmptyContainers makes Apply drop the empty maps and lists of
+	// the resulting object that no manager owns, rather than preserving
+	// them (see typed.TypedValue.DropEmptyContainers). Paths owned at any
+	// version count as owned.
+	DropEmptyContainers bool
+
+	// ManagerAliases maps manager names to the name of the manager they
+	// are an alias of, e.g. for controllers that share an identity but run
+	// as distinct managers. Update and Apply attribute the fields owned by
+	// the aliases, and the fields updated or applied by them, to the
+	// canonical name, so that aliases of the same manager never conflict
+	// with each other. The sets of the aliases must be of the same version.
+	ManagerAliases map[string]string
+
+	// PrivilegedManagers are the managers whose applies always win
+	// conflicts, as if they were forced, e.g. for trusted controllers.
+	// Managers are looked up by their canonical name (see ManagerAliases).
+	PrivilegedManagers map[string]bool
+
+	// NullMeansDelete makes the fields that are null in the configuration
+	// given to Apply delete the field from the resulting object, and
+	// relinquish its ownership, rather than set it to an explicit null
+	// value owned by the applier (see typed.MergeOptions).
+	NullMeansDelete bool
+
+	// Tracer, if set, is notified of the nodes walked while Apply merges
+	// the configuration into the live object, e.g. to debug unexpected
+	// results (see typed.MergeOptions).
+	Tracer typed.WalkTracer
 }
 
 func (u *UpdaterBuilder) BuildUpdater() *Updater {
 	return &Updater{
-		Converter:         u.Converter,
-		IgnoreFilter:      u.IgnoreFilter,
-		IgnoredFields:     u.IgnoredFields,
-		returnInputOnNoop: u.ReturnInputOnNoop,
+		Converter:              u.Converter,
+		IgnoreFilter:           u.IgnoreFilter,
+		IgnoredFields:          u.IgnoredFields,
+		returnInputOnNoop:      u.ReturnInputOnNoop,
+		now:                    u.Now,
+		warnAtomicCoownership:  u.WarnAtomicCoownership,
+		reportCreatedListItems: u.ReportCreatedListItems,
+		compressor:             u.Compressor,
+		equalities:             u.Equalities,
+		scalarEqual:            u.ScalarEqual,
+		maxResultSize:          u.MaxResultSize,
+		dropEmptyContainers:    u.DropEmptyContainers,
+		managerAliases:         u.ManagerAliases,
+		privilegedManagers:     u.PrivilegedManagers,
+		nullMeansDelete:        u.NullMeansDelete,
+		tracer:                 u.Tracer,
 	}
 }
 
@@ -67,17 +145,86 @@ type Updater struct {
 	IgnoreFilter map[fieldpath.APIVersion]fieldpath.Filter
 
 	returnInputOnNoop bool
+
+	now func() time.Time
+
+	warnAtomicCoownership func([]AtomicCoownership)
+
+	reportCreatedListItems func(created *fieldpath.Set)
+
+	compressor ManagedFieldsCompressor
+
+	equalities typed.Equalities
+
+	scalarEqual typed.ScalarEqual
+
+	maxResultSize int
+
+	dropEmptyContainers bool
+
+	managerAliases map[string]string
+
+	privilegedManagers map[string]bool
+
+	nullMeansDelete bool
+
+	tracer typed.WalkTracer
+}
+
+// warn reports the atomic fields of object owned by multiple managers, if
+// requested.
+func (s *Updater) warn(object *typed.TypedValue, managers fieldpath.ManagedFields) {
+	if s.warnAtomicCoownership == nil {
+		return
+	}
+	if warnings := s.atomicCoownership(object, managers); len(warnings) > 0 {
+		s.warnAtomicCoownership(warnings)
+	}
+}
+
+// timestamp returns the time to record in the sets written now.
+func (s *Updater) timestamp() time.Time {
+	if s.now == nil {
+		return time.Time{}
+	}
+	return s.now()
+}
+
+// filterIgnored removes the fields ignored at version from set.
+func (s *Updater) filterIgnored(set *fieldpath.Set, version fieldpath.APIVersion) (*fieldpath.Set, error) {
+	if s.IgnoredFields != nil && s.IgnoreFilter != nil {
+		return nil, fmt.Errorf("IgnoreFilter and IgnoreFilter may not both be set")
+	}
+	var ignoreFilter fieldpath.Filter
+	if s.IgnoredFields != nil {
+		ignoreFilter = fieldpath.NewExcludeSetFilter(s.IgnoredFields[version])
+	} else {
+		ignoreFilter = s.IgnoreFilter[version]
+	}
+	if ignoreFilter != nil {
+		set = ignoreFilter.Filter(set)
+	}
+	return set, nil
+}
+
+// compareOptions returns the options used to compare objects.
+func (s *Updater) compareOptions() typed.CompareOptions {
+	return typed.CompareOptions{
+		Equalities:  s.equalities,
+		ScalarEqual: s.scalarEqual,
+	}
 }
 
 func (s *Updater) update(oldObject, newObject *typed.TypedValue, version fieldpath.APIVersion, managers fieldpath.ManagedFields, workflow string, force bool) (fieldpath.ManagedFields, *typed.Comparison, error) {
 	conflicts := fieldpath.ManagedFields{}
 	removed := fieldpath.ManagedFields{}
-	compare, err := oldObject.Compare(newObject)
+	compare, err := oldObject.CompareWithOptions(newObject, s.compareOptions())
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to compare objects: %v", err)
 	}
 
 	var versions map[fieldpath.APIVersion]*typed.Comparison
+	objects := map[fieldpath.APIVersion]*typed.TypedValue{version: newObject}
 
 	if s.IgnoredFields != nil && s.IgnoreFilter != nil {
 		return nil, nil, fmt.Errorf("IgnoreFilter and IgnoreFilter may not both be set")
@@ -115,7 +262,8 @@ func (s *Updater) update(oldObject, newObject *typed.TypedValue, version fieldpa
 				}
 				return nil, nil, fmt.Errorf("failed to convert new object: %v", err)
 			}
-			compare, err = versionedOldObject.Compare(versionedNewObject)
+			objects[managerSet.APIVersion()] = versionedNewObject
+			compare, err = versionedOldObject.CompareWithOptions(versionedNewObject, s.compareOptions())
 			if err != nil {
 				return nil, nil, fmt.Errorf("failed to compare objects: %v", err)
 			}
@@ -138,15 +286,15 @@ func (s *Updater) update(oldObject, newObject *typed.TypedValue, version fieldpa
 	}
 
 	if !force && len(conflicts) != 0 {
-		return nil, nil, ConflictsFromManagers(conflicts)
+		return nil, nil, conflictsWithReasons(conflicts, versions, objects)
 	}
 
 	for manager, conflictSet := range conflicts {
-		managers[manager] = fieldpath.NewVersionedSet(managers[manager].Set().Difference(conflictSet.Set()), managers[manager].APIVersion(), managers[manager].Applied())
+		managers[manager] = fieldpath.NewVersionedSetAt(managers[manager].Set().Difference(conflictSet.Set()), managers[manager].APIVersion(), managers[manager].Applied(), managers[manager].Time())
 	}
 
 	for manager, removedSet := range removed {
-		managers[manager] = fieldpath.NewVersionedSet(managers[manager].Set().Difference(removedSet.Set()), managers[manager].APIVersion(), managers[manager].Applied())
+		managers[manager] = fieldpath.NewVersionedSetAt(managers[manager].Set().Difference(removedSet.Set()), managers[manager].APIVersion(), managers[manager].Applied(), managers[manager].Time())
 	}
 
 	for manager := range managers {
@@ -165,6 +313,11 @@ func (s *Updater) update(oldObject, newObject *typed.TypedValue, version fieldpa
 // this is a CREATE call).
 func (s *Updater) Update(liveObject, newObject *typed.TypedValue, version fieldpath.APIVersion, managers fieldpath.ManagedFields, manager string) (*typed.TypedValue, fieldpath.ManagedFields, error) {
 	var err error
+	manager = s.canonicalManager(manager)
+	managers, err = s.canonicalizeManagers(managers)
+	if err != nil {
+		return nil, fieldpath.ManagedFields{}, err
+	}
 	managers, err = s.reconcileManagedFieldsWithSchemaChanges(liveObject, managers)
 	if err != nil {
 		return nil, fieldpath.ManagedFields{}, err
@@ -177,28 +330,21 @@ func (s *Updater) Update(liveObject, newObject *typed.TypedValue, version fieldp
 		managers[manager] = fieldpath.NewVersionedSet(fieldpath.NewSet(), version, false)
 	}
 	set := managers[manager].Set().Difference(compare.Removed).Union(compare.Modified).Union(compare.Added)
-
-	if s.IgnoredFields != nil && s.IgnoreFilter != nil {
-		return nil, nil, fmt.Errorf("IgnoreFilter and IgnoreFilter may not both be set")
-	}
-	var ignoreFilter fieldpath.Filter
-	if s.IgnoredFields != nil {
-		ignoreFilter = fieldpath.NewExcludeSetFilter(s.IgnoredFields[version])
-	} else {
-		ignoreFilter = s.IgnoreFilter[version]
-	}
-	if ignoreFilter != nil {
-		set = ignoreFilter.Filter(set)
+	set, err = s.filterIgnored(set, version)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	managers[manager] = fieldpath.NewVersionedSet(
+	managers[manager] = fieldpath.NewVersionedSetAt(
 		set,
 		version,
 		false,
+		s.timestamp(),
 	)
 	if managers[manager].Set().Empty() {
 		delete(managers, manager)
 	}
+	s.warn(newObject, managers)
 	return newObject, managers, nil
 }
 
@@ -206,46 +352,116 @@ func (s *Updater) Update(liveObject, newObject *typed.TypedValue, version fieldp
 // well as the configuration that is applied. This will merge the object
 // and return it.
 func (s *Updater) Apply(liveObject, configObject *typed.TypedValue, version fieldpath.APIVersion, managers fieldpath.ManagedFields, manager string, force bool) (*typed.TypedValue, fieldpath.ManagedFields, error) {
-	var err error
-	managers, err = s.reconcileManagedFieldsWithSchemaChanges(liveObject, managers)
+	plan, err := s.apply(liveObject, configObject, version, managers, manager, force)
 	if err != nil {
 		return nil, fieldpath.ManagedFields{}, err
 	}
-	newObject, err := liveObject.Merge(configObject)
+	object, managers := plan.Commit()
+	return object, managers, nil
+}
+
+// apply computes the result of Apply as a plan, without calling the hooks
+// of the updater. managers is modified.
+func (s *Updater) apply(liveObject, configObject *typed.TypedValue, version fieldpath.APIVersion, managers fieldpath.ManagedFields, manager string, force bool) (*ApplyPlan, error) {
+	var err error
+	manager = s.canonicalManager(manager)
+	force = force || s.privilegedManagers[manager]
+	managers, err = s.canonicalizeManagers(managers)
 	if err != nil {
-		return nil, fieldpath.ManagedFields{}, fmt.Errorf("failed to merge config: %v", err)
+		return nil, err
 	}
-	lastSet := managers[manager]
-	set, err := configObject.ToFieldSet()
+	managers, err = s.reconcileManagedFieldsWithSchemaChanges(liveObject, managers)
 	if err != nil {
-		return nil, fieldpath.ManagedFields{}, fmt.Errorf("failed to get field set: %v", err)
+		return nil, err
 	}
-
-	if s.IgnoredFields != nil && s.IgnoreFilter != nil {
-		return nil, nil, fmt.Errorf("IgnoreFilter and IgnoreFilter may not both be set")
+	mergeOptions := typed.MergeOptions{
+		MaxSize:         s.maxResultSize,
+		NullMeansDelete: s.nullMeansDelete,
+		Tracer:          s.tracer,
 	}
-	var ignoreFilter fieldpath.Filter
-	if s.IgnoredFields != nil {
-		ignoreFilter = fieldpath.NewExcludeSetFilter(s.IgnoredFields[version])
-	} else {
-		ignoreFilter = s.IgnoreFilter[version]
+	newObject, err := liveObject.MergeWithOptions(configObject, mergeOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge config: %v", err)
 	}
-	if ignoreFilter != nil {
-		set = ignoreFilter.Filter(set)
+	lastSet := managers[manager]
+	set, err := s.appliedSet(configObject, version)
+	if err != nil {
+		return nil, err
 	}
-	managers[manager] = fieldpath.NewVersionedSet(set, version, true)
+	managers[manager] = fieldpath.NewVersionedSetAt(set, version, true, s.timestamp())
 	newObject, err = s.prune(newObject, managers, manager, lastSet)
 	if err != nil {
-		return nil, fieldpath.ManagedFields{}, fmt.Errorf("failed to prune fields: %v", err)
+		return nil, fmt.Errorf("failed to prune fields: %v", err)
 	}
 	managers, _, err = s.update(liveObject, newObject, version, managers, manager, force)
 	if err != nil {
-		return nil, fieldpath.ManagedFields{}, err
+		return nil, err
+	}
+	if s.dropEmptyContainers {
+		owned := fieldpath.NewSet()
+		for _, versionedSet := range managers {
+			owned = owned.Union(versionedSet.Set())
+		}
+		newObject, err = newObject.DropEmptyContainers(owned)
+		if err != nil {
+			return nil, fmt.Errorf("failed to drop empty containers: %v", err)
+		}
+	}
+	plan := &ApplyPlan{
+		Object:   newObject,
+		Managers: managers,
+		updater:  s,
+		merged:   newObject,
+	}
+	if s.reportCreatedListItems != nil {
+		plan.created, err = typed.CreatedListItems(liveObject, newObject)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find created list items: %v", err)
+		}
 	}
 	if !s.returnInputOnNoop && value.EqualsUsing(value.NewFreelistAllocator(), liveObject.AsValue(), newObject.AsValue()) {
-		newObject = nil
+		plan.Object = nil
 	}
-	return newObject, managers, nil
+	return plan, nil
+}
+
+// appliedSet returns the fields that applying configObject at version
+// gives ownership of.
+func (s *Updater) appliedSet(configObject *typed.TypedValue, version fieldpath.APIVersion) (*fieldpath.Set, error) {
+	appliedObject := configObject
+	if s.nullMeansDelete {
+		// The fields deleted by the configuration aren't owned.
+		var err error
+		appliedObject, err = configObject.Empty().MergeWithOptions(configObject, typed.MergeOptions{NullMeansDelete: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge config: %v", err)
+		}
+	}
+	set, err := appliedObject.ToFieldSet()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get field set: %v", err)
+	}
+	return s.filterIgnored(set, version)
+}
+
+// ApplyIfMatches is like Apply, but only applies configObject if
+// liveObject still matches expectedObject, usually the object that the
+// configuration was computed from. If it doesn't, nothing is applied and
+// matched is false. Conflicts that prevent the apply are returned
+// separately from other errors.
+func (s *Updater) ApplyIfMatches(liveObject, expectedObject, configObject *typed.TypedValue, version fieldpath.APIVersion, managers fieldpath.ManagedFields, manager string, force bool) (object *typed.TypedValue, newManagers fieldpath.ManagedFields, conflicts Conflicts, matched bool, err error) {
+	compare, err := liveObject.Compare(expectedObject)
+	if err != nil {
+		return nil, nil, nil, false, fmt.Errorf("failed to compare live and expected objects: %v", err)
+	}
+	if !compare.IsSame() {
+		return nil, managers, nil, false, nil
+	}
+	object, newManagers, err = s.Apply(liveObject, configObject, version, managers, manager, force)
+	if c, ok := err.(Conflicts); ok {
+		return nil, nil, c, true, nil
+	}
+	return object, newManagers, nil, true, err
 }
 
 // prune will remove a field, list or map item, iff:
@@ -266,7 +482,10 @@ func (s *Updater) prune(merged *typed.TypedValue, managers fieldpath.ManagedFiel
 	}
 
 	sc, tr := convertedMerged.Schema(), convertedMerged.TypeRef()
-	pruned := convertedMerged.RemoveItems(lastSet.Set().EnsureNamedFieldsAreMembers(sc, tr))
+	pruned, err := convertedMerged.RemoveItemsWithError(lastSet.Set().EnsureNamedFieldsAreMembers(sc, tr))
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove fields: %v", err)
+	}
 	pruned, err = s.addBackOwnedItems(convertedMerged, pruned, version, managers, applyingManager)
 	if err != nil {
 		return nil, fmt.Errorf("failed add back owned items: %v", err)
@@ -334,7 +553,10 @@ func (s *Updater) addBackOwnedItemsForVersion(merged, pruned *typed.TypedValue,
 		return nil, nil, fmt.Errorf("failed to create field set from pruned object at version %v: %v", version, err)
 	}
 	sc, tr := merged.Schema(), merged.TypeRef()
-	pruned = merged.RemoveItems(mergedSet.EnsureNamedFieldsAreMembers(sc, tr).Difference(prunedSet.EnsureNamedFieldsAreMembers(sc, tr).Union(managed.EnsureNamedFieldsAreMembers(sc, tr))))
+	pruned, err = merged.RemoveItemsWithError(mergedSet.EnsureNamedFieldsAreMembers(sc, tr).Difference(prunedSet.EnsureNamedFieldsAreMembers(sc, tr).Union(managed.EnsureNamedFieldsAreMembers(sc, tr))))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to remove fields at version %v: %v", version, err)
+	}
 	return merged, pruned, nil
 }
 
@@ -361,7 +583,7 @@ func (s *Updater) addBackDanglingItems(merged, pruned *typed.TypedValue, lastSet
 	prunedSet = prunedSet.EnsureNamedFieldsAreMembers(sc, tr)
 	mergedSet = mergedSet.EnsureNamedFieldsAreMembers(sc, tr)
 	last := lastSet.Set().EnsureNamedFieldsAreMembers(sc, tr)
-	return merged.RemoveItems(mergedSet.Difference(prunedSet).Intersection(last)), nil
+	return merged.RemoveItemsWithError(mergedSet.Difference(prunedSet).Intersection(last))
 }
 
 // reconcileManagedFieldsWithSchemaChanges reconciles the managed fields with any changes to the
@@ -385,7 +607,7 @@ func (s *Updater) reconcileManagedFieldsWithSchemaChanges(liveObject *typed.Type
 			return nil, err
 		}
 		if reconciled != nil {
-			result[manager] = fieldpath.NewVersionedSet(reconciled, versionedSet.APIVersion(), versionedSet.Applied())
+			result[manager] = fieldpath.NewVersionedSetAt(reconciled, versionedSet.APIVersion(), versionedSet.Applied(), versionedSet.Time())
 		} else {
 			result[manager] = versionedSet
 		}
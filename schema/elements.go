@@ -17,6 +17,7 @@ limitations under the License.
 package schema
 
 import (
+	"strings"
 	"sync"
 )
 
@@ -34,6 +35,9 @@ type Schema struct {
 	// Cached results of resolving type references to atoms. Only stores
 	// type references which require fields of Atom to be overriden.
 	resolvedTypes map[TypeRef]Atom
+
+	normalizeOnce sync.Once
+	normalizes    bool
 }
 
 // A TypeSpecifier references a particular type in a schema.
@@ -47,6 +51,10 @@ type TypeDef struct {
 	// Top level types should be named. Every type must have a unique name.
 	Name string `yaml:"name,omitempty"`
 
+	// Description is a human readable description of the type, used to
+	// give context in validation errors. It has no effect on merging.
+	Description string `yaml:"description,omitempty"`
+
 	Atom `yaml:"atom,omitempty,inline"`
 }
 
@@ -70,12 +78,46 @@ type Atom struct {
 	*Scalar `yaml:"scalar,omitempty"`
 	*List   `yaml:"list,omitempty"`
 	*Map    `yaml:"map,omitempty"`
+
+	// MinLength and MaxLength bound the length, in runes, of string
+	// scalars. A nil bound is not enforced.
+	MinLength *int `yaml:"minLength,omitempty"`
+	MaxLength *int `yaml:"maxLength,omitempty"`
+
+	// Normalize is the normalization of the string values of this type,
+	// applied when they are parsed into a typed value, e.g. "trim,upper".
+	Normalize Normalization `yaml:"normalize,omitempty"`
+}
+
+// Normalization is a transformation of string values, or a comma-separated
+// list of transformations applied in order.
+type Normalization string
+
+const (
+	// NormalizeTrim removes leading and trailing white space.
+	NormalizeTrim = Normalization("trim")
+	// NormalizeLower maps letters to their lower case.
+	NormalizeLower = Normalization("lower")
+	// NormalizeUpper maps letters to their upper case.
+	NormalizeUpper = Normalization("upper")
+)
+
+// Steps returns the transformations that make up n, in order.
+func (n Normalization) Steps() []Normalization {
+	if n == "" {
+		return nil
+	}
+	parts := strings.Split(string(n), ",")
+	steps := make([]Normalization, len(parts))
+	for i, part := range parts {
+		steps[i] = Normalization(strings.TrimSpace(part))
+	}
+	return steps
 }
 
 // Scalar (AKA "primitive") represents a type which has a single value which is
-// either numeric, string, or boolean, or untyped for any of them.
-//
-// TODO: split numeric into float/int? Something even more fine-grained?
+// either numeric, string, or boolean, or untyped for any of them. Numeric
+// values can be restricted to integers.
 type Scalar string
 
 const (
@@ -83,6 +125,14 @@ const (
 	String  = Scalar("string")
 	Boolean = Scalar("boolean")
 	Untyped = Scalar("untyped")
+	// Integer is a numeric scalar without a fractional part. Floats with
+	// an integral value, such as 3.0, are accepted like in JSON, where
+	// they are the same number as 3.
+	Integer = Scalar("integer")
+	// Number is a numeric scalar, either an integer or not. It is the same
+	// as Numeric, which remains for existing schemas, and is the
+	// counterpart of Integer.
+	Number = Scalar("number")
 )
 
 // ElementRelationship is an enum of the different possible relationships
@@ -237,6 +287,14 @@ type StructField struct {
 	Type TypeRef `yaml:"type,omitempty"`
 	// Default value for the field, nil if not present.
 	Default interface{} `yaml:"default,omitempty"`
+	// Computed marks fields whose value is derived by the server (e.g.
+	// `status.observedGeneration`). Computed fields are never owned by
+	// any manager, never cause conflicts, and a merge keeps their
+	// existing value.
+	Computed bool `yaml:"computed,omitempty"`
+	// Description is a human readable description of the field, used to
+	// give context in validation errors. It has no effect on merging.
+	Description string `yaml:"description,omitempty"`
 }
 
 // List represents a type which contains a zero or more elements, all of the
@@ -266,6 +324,11 @@ type List struct {
 	//
 	// TODO: change this to "non-atomic struct" above and make the code reflect this.
 	//
+	// The element type must not be an atomic map: keys address fields of
+	// the elements, which can't be owned separately from the rest of an
+	// atomic element. A list of atomic elements should itself be atomic.
+	// The strict parser rejects schemas that combine the two.
+	//
 	// Each key must refer to a single field name (no nesting, not JSONPath).
 	Keys []string `yaml:"keys,omitempty"`
 }
@@ -283,6 +346,37 @@ func (s *Schema) FindNamedType(name string) (TypeDef, bool) {
 	return t, ok
 }
 
+// HasNormalizations returns true if any type of the schema normalizes its
+// string values.
+func (s *Schema) HasNormalizations() bool {
+	s.normalizeOnce.Do(func() {
+		for _, td := range s.Types {
+			if atomHasNormalizations(td.Atom) {
+				s.normalizes = true
+				return
+			}
+		}
+	})
+	return s.normalizes
+}
+
+func atomHasNormalizations(a Atom) bool {
+	if a.Normalize != "" {
+		return true
+	}
+	if a.Map != nil {
+		for _, f := range a.Map.Fields {
+			if f.Type.NamedType == nil && atomHasNormalizations(f.Type.Inlined) {
+				return true
+			}
+		}
+		if a.Map.ElementType.NamedType == nil && atomHasNormalizations(a.Map.ElementType.Inlined) {
+			return true
+		}
+	}
+	return a.List != nil && a.List.ElementType.NamedType == nil && atomHasNormalizations(a.List.ElementType.Inlined)
+}
+
 func (s *Schema) resolveNoOverrides(tr TypeRef) (Atom, bool) {
 	result := Atom{}
 
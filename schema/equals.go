@@ -66,6 +66,9 @@ func (a *TypeDef) Equals(b *TypeDef) bool {
 	if a.Name != b.Name {
 		return false
 	}
+	if a.Description != b.Description {
+		return false
+	}
 	return a.Atom.Equals(&b.Atom)
 }
 
@@ -83,6 +86,12 @@ func (a *Atom) Equals(b *Atom) bool {
 	if (a.Map == nil) != (b.Map == nil) {
 		return false
 	}
+	if !intPtrEquals(a.MinLength, b.MinLength) || !intPtrEquals(a.MaxLength, b.MaxLength) {
+		return false
+	}
+	if a.Normalize != b.Normalize {
+		return false
+	}
 	switch {
 	case a.Scalar != nil:
 		return *a.Scalar == *b.Scalar
@@ -176,6 +185,12 @@ func (a *StructField) Equals(b *StructField) bool {
 	if !reflect.DeepEqual(a.Default, b.Default) {
 		return false
 	}
+	if a.Computed != b.Computed {
+		return false
+	}
+	if a.Description != b.Description {
+		return false
+	}
 	return a.Type.Equals(&b.Type)
 }
 
@@ -200,3 +215,10 @@ func (a *List) Equals(b *List) bool {
 	}
 	return true
 }
+
+func intPtrEquals(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -87,6 +87,7 @@ func TestEquals(t *testing.T) {
 			}
 			var y TypeDef
 			y.Name = x.Name
+			y.Description = x.Description
 			y.Atom = x.Atom
 			return x.Equals(&y) == reflect.DeepEqual(x, y)
 		},
@@ -107,6 +108,9 @@ func TestEquals(t *testing.T) {
 			y.Scalar = x.Scalar
 			y.List = x.List
 			y.Map = x.Map
+			y.MinLength = x.MinLength
+			y.MaxLength = x.MaxLength
+			y.Normalize = x.Normalize
 			return x.Equals(&y) == reflect.DeepEqual(x, y)
 		},
 		func(x *Map) bool {
@@ -147,6 +151,7 @@ func TestEquals(t *testing.T) {
 			y.Name = x.Name
 			y.Type = x.Type
 			y.Default = x.Default
+			y.Description = x.Description
 			return x.Equals(&y) == reflect.DeepEqual(x, y)
 		},
 		func(x List) bool {
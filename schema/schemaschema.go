@@ -36,9 +36,21 @@ var SchemaSchemaYAML = `types:
     - name: name
       type:
         scalar: string
+    - name: description
+      type:
+        scalar: string
     - name: scalar
       type:
         scalar: string
+    - name: minLength
+      type:
+        scalar: numeric
+    - name: maxLength
+      type:
+        scalar: numeric
+    - name: normalize
+      type:
+        scalar: string
     - name: map
       type:
         namedType: map
@@ -57,6 +69,15 @@ var SchemaSchemaYAML = `types:
     - name: scalar
       type:
         scalar: string
+    - name: minLength
+      type:
+        scalar: numeric
+    - name: maxLength
+      type:
+        scalar: numeric
+    - name: normalize
+      type:
+        scalar: string
     - name: map
       type:
         namedType: map
@@ -131,6 +152,12 @@ var SchemaSchemaYAML = `types:
     - name: default
       type:
         namedType: __untyped_atomic_
+    - name: computed
+      type:
+        scalar: boolean
+    - name: description
+      type:
+        scalar: string
 - name: list
   map:
     fields:
@@ -0,0 +1,145 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Validate checks the structure of the schema, and returns an error listing
+// every problem found, each prefixed by the path of the faulty type: the
+// name of its named type followed by the fields leading to it, "[]" for the
+// element type of a list and ".*" for the element type of a map.
+//
+// It reports lists whose keys are declared more than once, atomic lists
+// that declare keys, associative lists whose keys don't name scalar
+// fields of their element type, and unknown normalizations.
+func (s *Schema) Validate() error {
+	var errs []string
+	s.VisitAtoms(func(a Atom, path string) error {
+		for _, n := range a.Normalize.Steps() {
+			switch n {
+			case NormalizeTrim, NormalizeLower, NormalizeUpper:
+			default:
+				errs = append(errs, fmt.Sprintf("%v: unknown normalization %q", path, n))
+			}
+		}
+		if a.List != nil {
+			errs = s.validateListKeys(a.List, path, errs)
+		}
+		return nil
+	})
+	if len(errs) != 0 {
+		return fmt.Errorf("invalid schema:\n%v", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// VisitAtoms calls visit with the atom of each named type of s, and with
+// the atoms inlined within them, along with their path as described by
+// Validate. It stops at the first error returned by visit, and returns it.
+func (s *Schema) VisitAtoms(visit func(a Atom, path string) error) error {
+	for _, td := range s.Types {
+		if err := visitAtom(td.Atom, td.Name, visit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func visitAtom(a Atom, path string, visit func(a Atom, path string) error) error {
+	if err := visit(a, path); err != nil {
+		return err
+	}
+	if a.Map != nil {
+		for _, f := range a.Map.Fields {
+			if err := visitTypeRef(f.Type, path+"."+f.Name, visit); err != nil {
+				return err
+			}
+		}
+		if err := visitTypeRef(a.Map.ElementType, path+".*", visit); err != nil {
+			return err
+		}
+	}
+	if a.List != nil {
+		if err := visitTypeRef(a.List.ElementType, path+"[]", visit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// visitTypeRef only visits inlined types, since named types are visited on
+// their own.
+func visitTypeRef(tr TypeRef, path string, visit func(a Atom, path string) error) error {
+	if tr.NamedType != nil {
+		return nil
+	}
+	return visitAtom(tr.Inlined, path, visit)
+}
+
+func (s *Schema) validateListKeys(l *List, path string, errs []string) []string {
+	if len(l.Keys) == 0 {
+		return errs
+	}
+	seen := map[string]bool{}
+	for _, key := range l.Keys {
+		if seen[key] {
+			errs = append(errs, fmt.Sprintf("%v: key %q is declared more than once", path, key))
+		}
+		seen[key] = true
+	}
+	switch l.ElementRelationship {
+	case Atomic:
+		return append(errs, fmt.Sprintf("%v: atomic list can't declare keys %v", path, l.Keys))
+	case Associative:
+	default:
+		return errs
+	}
+	elem, ok := s.Resolve(l.ElementType)
+	if !ok {
+		return append(errs, fmt.Sprintf("%v: no type found for the elements of the list", path))
+	}
+	if elem.Map == nil {
+		return append(errs, fmt.Sprintf("%v: list with keys %v must have map elements", path, l.Keys))
+	}
+	for i, key := range l.Keys {
+		if indexOf(l.Keys, key) != i {
+			// Already checked.
+			continue
+		}
+		f, ok := elem.Map.FindField(key)
+		if !ok {
+			errs = append(errs, fmt.Sprintf("%v: key %q is not a field of the list elements", path, key))
+			continue
+		}
+		if a, ok := s.Resolve(f.Type); !ok || a.Scalar == nil {
+			errs = append(errs, fmt.Sprintf("%v: key %q must be a scalar field of the list elements", path, key))
+		}
+	}
+	return errs
+}
+
+func indexOf(keys []string, key string) int {
+	for i := range keys {
+		if keys[i] == key {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	yaml "sigs.k8s.io/yaml/goyaml.v2"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		testName string
+		schema   string
+		errors   []string
+	}{
+		{
+			testName: "valid",
+			schema: `types:
+- name: root
+  map:
+    fields:
+    - name: list
+      type:
+        list:
+          elementType:
+            namedType: element
+          elementRelationship: associative
+          keys: [name, port]
+    - name: set
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: associative
+    - name: atomicList
+      type:
+        list:
+          elementType:
+            namedType: element
+          elementRelationship: atomic
+- name: element
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    - name: port
+      type:
+        namedType: port
+- name: port
+  scalar: numeric
+`,
+		},
+		{
+			testName: "duplicate-keys",
+			schema: `types:
+- name: root
+  list:
+    elementType:
+      namedType: element
+    elementRelationship: associative
+    keys: [name, name]
+- name: element
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+`,
+			errors: []string{`root: key "name" is declared more than once`},
+		},
+		{
+			testName: "missing-key-field",
+			schema: `types:
+- name: root
+  map:
+    fields:
+    - name: list
+      type:
+        list:
+          elementType:
+            map:
+              fields:
+              - name: name
+                type:
+                  scalar: string
+          elementRelationship: associative
+          keys: [name, id]
+`,
+			errors: []string{`root.list: key "id" is not a field of the list elements`},
+		},
+		{
+			testName: "non-scalar-key-field",
+			schema: `types:
+- name: root
+  map:
+    elementType:
+      list:
+        elementType:
+          map:
+            fields:
+            - name: name
+              type:
+                map:
+                  elementType:
+                    scalar: string
+        elementRelationship: associative
+        keys: [name]
+`,
+			errors: []string{`root.*: key "name" must be a scalar field of the list elements`},
+		},
+		{
+			testName: "scalar-elements",
+			schema: `types:
+- name: root
+  list:
+    elementType:
+      scalar: string
+    elementRelationship: associative
+    keys: [name]
+`,
+			errors: []string{`root: list with keys [name] must have map elements`},
+		},
+		{
+			testName: "missing-element-type",
+			schema: `types:
+- name: root
+  list:
+    elementType:
+      namedType: element
+    elementRelationship: associative
+    keys: [name]
+`,
+			errors: []string{`root: no type found for the elements of the list`},
+		},
+		{
+			testName: "atomic-list-with-keys",
+			schema: `types:
+- name: root
+  map:
+    fields:
+    - name: list
+      type:
+        list:
+          elementType:
+            namedType: element
+          elementRelationship: atomic
+          keys: [name]
+    - name: other
+      type:
+        list:
+          elementType:
+            namedType: element
+          elementRelationship: associative
+          keys: [name, name]
+- name: element
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+`,
+			errors: []string{
+				`root.list: atomic list can't declare keys [name]`,
+				`root.other: key "name" is declared more than once`,
+			},
+		},
+		{
+			testName: "unknown-normalization",
+			schema: `types:
+- name: root
+  map:
+    fields:
+    - name: protocol
+      type:
+        scalar: string
+        normalize: trim,title
+`,
+			errors: []string{`root.protocol: unknown normalization "title"`},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			var s Schema
+			if err := yaml.Unmarshal([]byte(tt.schema), &s); err != nil {
+				t.Fatalf("failed to parse schema: %v", err)
+			}
+			err := s.Validate()
+			if len(tt.errors) == 0 {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected errors %v", tt.errors)
+			}
+			for _, e := range tt.errors {
+				if !strings.Contains(err.Error(), e) {
+					t.Errorf("expected error %q in %v", e, err)
+				}
+			}
+		})
+	}
+}
+
+func TestVisitAtoms(t *testing.T) {
+	var s Schema
+	if err := yaml.Unmarshal([]byte(`types:
+- name: root
+  map:
+    fields:
+    - name: list
+      type:
+        list:
+          elementType:
+            map:
+              elementType:
+                scalar: string
+    - name: named
+      type:
+        namedType: leaf
+- name: leaf
+  scalar: string
+`), &s); err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	var paths []string
+	err := s.VisitAtoms(func(a Atom, path string) error {
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Maps without element type have an empty inlined one.
+	expected := []string{"root", "root.list", "root.list[]", "root.list[].*", "root.*", "leaf"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected paths %v, got %v", expected, paths)
+	}
+
+	stop := errors.New("stop")
+	paths = nil
+	err = s.VisitAtoms(func(a Atom, path string) error {
+		paths = append(paths, path)
+		if a.List != nil {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Errorf("expected the visit error, got %v", err)
+	}
+	if expected := []string{"root", "root.list"}; !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected paths %v, got %v", expected, paths)
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed
+
+import (
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/schema"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// AtomicFields returns the paths of s that refer to atomic maps or lists in
+// tv. Paths that can't be found in tv are ignored.
+func (tv TypedValue) AtomicFields(s *fieldpath.Set) *fieldpath.Set {
+	atomic := fieldpath.NewSet()
+	s.Iterate(func(p fieldpath.Path) {
+		a, v, ok := atomAtPath(tv.schema, tv.typeRef, tv.value, p)
+		if !ok {
+			return
+		}
+		switch {
+		case a.Map != nil && v.IsMap() && a.Map.ElementRelationship == schema.Atomic,
+			a.List != nil && v.IsList() && a.List.ElementRelationship == schema.Atomic:
+			atomic.Insert(p.Copy())
+		}
+	})
+	return atomic
+}
+
+// atomAtPath follows path in v, and returns the value found along with its
+// atom, deduced from the value.
+func atomAtPath(s *schema.Schema, tr schema.TypeRef, v value.Value, path fieldpath.Path) (schema.Atom, value.Value, bool) {
+	for _, pe := range path {
+		a, ok := s.Resolve(tr)
+		if !ok {
+			return schema.Atom{}, nil, false
+		}
+		a = deduceAtom(a, v)
+		switch {
+		case pe.FieldName != nil:
+			if a.Map == nil || !v.IsMap() {
+				return schema.Atom{}, nil, false
+			}
+			child, ok := v.AsMap().Get(*pe.FieldName)
+			if !ok {
+				return schema.Atom{}, nil, false
+			}
+			v = child
+			tr = a.Map.ElementType
+			if sf, ok := a.Map.FindField(*pe.FieldName); ok {
+				tr = sf.Type
+			}
+		case pe.Index != nil:
+			if a.List == nil || !v.IsList() || *pe.Index < 0 || *pe.Index >= v.AsList().Length() {
+				return schema.Atom{}, nil, false
+			}
+			v = v.AsList().At(*pe.Index)
+			tr = a.List.ElementType
+		default:
+			if a.List == nil || !v.IsList() {
+				return schema.Atom{}, nil, false
+			}
+			l := v.AsList()
+			found := false
+			for i := 0; i < l.Length(); i++ {
+				child := l.At(i)
+				if childPE, err := listItemToPathElement(value.HeapAllocator, s, a.List, child); err == nil && childPE.Equals(pe) {
+					v = child
+					found = true
+					break
+				}
+			}
+			if !found {
+				return schema.Atom{}, nil, false
+			}
+			tr = a.List.ElementType
+		}
+	}
+	a, ok := s.Resolve(tr)
+	if !ok {
+		return schema.Atom{}, nil, false
+	}
+	return deduceAtom(a, v), v, true
+}
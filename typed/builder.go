@@ -0,0 +1,164 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/schema"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// ValueBuilder builds an object of a given type field by field, validating
+// each field as it is set. Errors are accumulated and returned by Build.
+type ValueBuilder struct {
+	pt   ParseableType
+	root interface{}
+	errs ValidationErrors
+}
+
+// NewValueBuilder returns a builder of objects of type pt, starting from
+// an empty object.
+func NewValueBuilder(pt ParseableType) *ValueBuilder {
+	return &ValueBuilder{pt: pt}
+}
+
+// SetField sets the value at path, creating the maps and associative list
+// items that lead to it, and validates v against the type found at path.
+// Associative list items are created with their key fields set. Items of
+// lists can also be addressed by index if they already exist, but items of
+// sets can't be addressed, the whole set must be set instead. An empty path
+// sets the whole object.
+func (b *ValueBuilder) SetField(path fieldpath.Path, v value.Value) *ValueBuilder {
+	// Copy v so that it isn't modified by setting fields within it.
+	u := value.AsUnordered(v).Unstructured()
+	root, err := b.set(b.pt.TypeRef, b.root, path, u)
+	if err != nil {
+		b.errs = append(b.errs, errorf("%v", err).WithPrefix(path.String())...)
+		return b
+	}
+	b.root = root
+	return b
+}
+
+// Build validates the object built so far, and returns it along with the
+// errors of all the fields that failed to be set.
+func (b *ValueBuilder) Build() (*TypedValue, error) {
+	if len(b.errs) != 0 {
+		return nil, b.errs
+	}
+	root := b.root
+	if root == nil {
+		root = map[string]interface{}{}
+	}
+	return asTyped(value.NewValueInterface(root), b.pt.Schema, b.pt.TypeRef, b.pt.MaxDepth)
+}
+
+// set returns current, of type tr, with v set at path.
+func (b *ValueBuilder) set(tr schema.TypeRef, current interface{}, path fieldpath.Path, v interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		if _, err := AsTyped(value.NewValueInterface(v), b.pt.Schema, tr); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+	a, ok := b.pt.Schema.Resolve(tr)
+	if !ok {
+		return nil, fmt.Errorf("schema error: no type found matching: %v", tr)
+	}
+	pe := path[0]
+	switch {
+	case pe.FieldName != nil:
+		if a.Map == nil {
+			return nil, fmt.Errorf("can't set field %q on a type that isn't a map", *pe.FieldName)
+		}
+		m, ok := current.(map[string]interface{})
+		if current != nil && !ok {
+			return nil, fmt.Errorf("can't set field %q on a value that isn't a map", *pe.FieldName)
+		}
+		if m == nil {
+			m = map[string]interface{}{}
+		}
+		fieldType := a.Map.ElementType
+		if sf, ok := a.Map.FindField(*pe.FieldName); ok {
+			fieldType = sf.Type
+		} else if (fieldType == schema.TypeRef{}) {
+			return nil, fmt.Errorf("field %q not declared in schema", *pe.FieldName)
+		}
+		child, err := b.set(fieldType, m[*pe.FieldName], path[1:], v)
+		if err != nil {
+			return nil, err
+		}
+		m[*pe.FieldName] = child
+		return m, nil
+	case pe.Key != nil, pe.Index != nil:
+		if a.List == nil {
+			return nil, fmt.Errorf("can't set item %v on a type that isn't a list", pe)
+		}
+		l, ok := current.([]interface{})
+		if current != nil && !ok {
+			return nil, fmt.Errorf("can't set item %v on a value that isn't a list", pe)
+		}
+		i, err := b.findItem(a.List, l, pe)
+		if err != nil {
+			return nil, err
+		}
+		var item interface{}
+		if i < len(l) {
+			item = l[i]
+		} else {
+			fields := map[string]interface{}{}
+			for _, f := range *pe.Key {
+				fields[f.Name] = f.Value.Unstructured()
+			}
+			item = fields
+		}
+		child, err := b.set(a.List.ElementType, item, path[1:], v)
+		if err != nil {
+			return nil, err
+		}
+		if i < len(l) {
+			l[i] = child
+		} else {
+			l = append(l, child)
+		}
+		return l, nil
+	}
+	return nil, fmt.Errorf("can't set %v, only fields and list items with keys or indices can be set", pe)
+}
+
+// findItem returns the index of the item of l addressed by pe, or the length
+// of l if pe is a key that isn't found, for the item to be appended.
+func (b *ValueBuilder) findItem(t *schema.List, l []interface{}, pe fieldpath.PathElement) (int, error) {
+	if pe.Index != nil {
+		if *pe.Index < 0 || *pe.Index >= len(l) {
+			return 0, fmt.Errorf("index %v out of range", *pe.Index)
+		}
+		return *pe.Index, nil
+	}
+	if t.ElementRelationship != schema.Associative || len(t.Keys) == 0 {
+		return 0, fmt.Errorf("can't set item %v on a list without keys", pe)
+	}
+	for i, item := range l {
+		itemPE, err := listItemToPathElement(value.HeapAllocator, b.pt.Schema, t, value.NewValueInterface(item))
+		if err == nil && itemPE.Equals(pe) {
+			return i, nil
+		}
+	}
+	return len(l), nil
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+var builderParser = func() *typed.Parser {
+	parser, err := typed.NewParser(`types:
+- name: deployment
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    - name: spec
+      type:
+        namedType: spec
+- name: spec
+  map:
+    fields:
+    - name: replicas
+      type:
+        scalar: numeric
+    - name: labels
+      type:
+        map:
+          elementType:
+            scalar: string
+    - name: containers
+      type:
+        list:
+          elementType:
+            namedType: container
+          elementRelationship: associative
+          keys:
+          - name
+    - name: args
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+- name: container
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    - name: image
+      type:
+        scalar: string
+    - name: port
+      type:
+        scalar: numeric
+`)
+	if err != nil {
+		panic(err)
+	}
+	return parser
+}()
+
+func TestValueBuilder(t *testing.T) {
+	pt := builderParser.Type("deployment")
+	labels := map[string]interface{}{"app": "web"}
+	tv, err := typed.NewValueBuilder(pt).
+		SetField(_P("name"), _V("web")).
+		SetField(_P("spec", "replicas"), _V(3)).
+		SetField(_P("spec", "labels"), _V(labels)).
+		SetField(_P("spec", "labels", "tier"), _V("front")).
+		SetField(_P("spec", "containers", _KBF("name", "web"), "image"), _V("nginx")).
+		SetField(_P("spec", "containers", _KBF("name", "sidecar"), "image"), _V("envoy")).
+		SetField(_P("spec", "containers", _KBF("name", "web"), "port"), _V(80)).
+		SetField(_P("spec", "args"), _V([]interface{}{"a", "b"})).
+		SetField(_P("spec", "args", 1), _V("c")).
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected, err := pt.FromYAML(`
+name: web
+spec:
+  replicas: 3
+  labels:
+    app: web
+    tier: front
+  containers:
+  - name: web
+    image: nginx
+    port: 80
+  - name: sidecar
+    image: envoy
+  args: [a, c]
+`)
+	if err != nil {
+		t.Fatalf("failed to parse expected object: %v", err)
+	}
+	if !value.Equals(tv.AsValue(), expected.AsValue()) {
+		t.Errorf("expected\n%v\nbut got\n%v", value.ToString(expected.AsValue()), value.ToString(tv.AsValue()))
+	}
+	if len(labels) != 1 {
+		t.Errorf("expected the given value to be left untouched, got %v", labels)
+	}
+
+	// An empty builder builds an empty object.
+	empty, err := typed.NewValueBuilder(pt).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !value.Equals(empty.AsValue(), _V(map[string]interface{}{})) {
+		t.Errorf("expected an empty object, got %v", value.ToString(empty.AsValue()))
+	}
+}
+
+func TestValueBuilderErrors(t *testing.T) {
+	pt := builderParser.Type("deployment")
+	table := []struct {
+		name     string
+		path     fieldpath.Path
+		value    interface{}
+		expected string
+	}{
+		{"type-mismatch", _P("spec", "replicas"), "three", ".spec.replicas: expected numeric"},
+		{"nested-type-mismatch", _P("spec"), map[string]interface{}{"replicas": true}, ".spec: .replicas: expected numeric"},
+		{"unknown-field", _P("spec", "unknown"), "a", `.spec.unknown: field "unknown" not declared in schema`},
+		{"field-of-scalar", _P("name", "first"), "a", `can't set field "first" on a type that isn't a map`},
+		{"key-of-atomic-list", _P("spec", "args", _KBF("name", "a")), "a", "can't set item"},
+		{"index-out-of-range", _P("spec", "containers", 0, "image"), "a", "index 0 out of range"},
+	}
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			b := typed.NewValueBuilder(pt).SetField(_P("name"), _V("web"))
+			_, err := b.SetField(tt.path, _V(tt.value)).Build()
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if _, ok := err.(typed.ValidationErrors); !ok {
+				t.Errorf("expected validation errors, got %T", err)
+			}
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed
+
+import (
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/schema"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// canonicalizeFields returns a copy of v where the fields that match a
+// field declared by the schema only when ignoring case are renamed to the
+// declared spelling. Fields that collide once renamed are reported.
+func canonicalizeFields(s *schema.Schema, tr schema.TypeRef, v value.Value) (interface{}, ValidationErrors) {
+	a, ok := s.Resolve(tr)
+	if !ok {
+		// Left for the validation to report.
+		return v.Unstructured(), nil
+	}
+	a = deduceAtom(a, v)
+	switch {
+	case a.Map != nil && v.IsMap():
+		return canonicalizeMapFields(s, a.Map, v.AsMap())
+	case a.List != nil && v.IsList():
+		var errs ValidationErrors
+		l := v.AsList()
+		out := make([]interface{}, l.Length())
+		for i := range out {
+			child, childErrs := canonicalizeFields(s, a.List.ElementType, l.At(i))
+			errs = append(errs, childErrs.WithPrefix(fieldpath.PathElement{Index: &i}.String())...)
+			out[i] = child
+		}
+		return out, errs
+	}
+	return v.Unstructured(), nil
+}
+
+func canonicalizeMapFields(s *schema.Schema, t *schema.Map, m value.Map) (interface{}, ValidationErrors) {
+	var errs ValidationErrors
+	keys := make([]string, 0, m.Length())
+	m.Iterate(func(key string, _ value.Value) bool {
+		keys = append(keys, key)
+		return true
+	})
+	sort.Strings(keys)
+	out := make(map[string]interface{}, len(keys))
+	original := make(map[string]string, len(keys))
+	for _, key := range keys {
+		name := key
+		fieldType := t.ElementType
+		if sf, ok := t.FindField(key); ok {
+			fieldType = sf.Type
+		} else if sf, ok := findFieldIgnoringCase(t, key); ok {
+			name = sf.Name
+			fieldType = sf.Type
+		}
+		pe := fieldpath.PathElement{FieldName: &name}
+		if other, ok := original[name]; ok {
+			errs = append(errs, errorf("fields %q and %q only differ by case", other, key).WithPrefix(pe.String())...)
+			continue
+		}
+		original[name] = key
+		val, _ := m.Get(key)
+		child, childErrs := canonicalizeFields(s, fieldType, val)
+		errs = append(errs, childErrs.WithPrefix(pe.String())...)
+		out[name] = child
+	}
+	return out, errs
+}
+
+func findFieldIgnoringCase(t *schema.Map, name string) (schema.StructField, bool) {
+	for _, sf := range t.Fields {
+		if strings.EqualFold(sf.Name, name) {
+			return sf, true
+		}
+	}
+	return schema.StructField{}, false
+}
@@ -39,6 +39,27 @@ type Comparison struct {
 	Added *fieldpath.Set
 }
 
+// Equalities maps type names to functions that decide whether two values of
+// that type are semantically equal even though they are represented
+// differently, e.g. the quantities "1Gi" and "1024Mi". Values that are equal
+// according to value.Equals are always equal.
+type Equalities map[string]func(lhs, rhs value.Value) bool
+
+// ScalarEqual decides whether the scalars lhs and rhs found at path are
+// equal, in place of value.Equals, e.g. to compare the quantities "1000m"
+// and "1" semantically. path must not be retained after the call returns.
+type ScalarEqual func(lhs, rhs value.Value, path fieldpath.Path) bool
+
+// CompareOptions customizes how leaves are compared by CompareWithOptions.
+type CompareOptions struct {
+	// Equalities, if any, also consider leaves of the given named types
+	// equal if their function says so.
+	Equalities Equalities
+	// ScalarEqual, if set, is used instead of value.Equals to compare
+	// scalar leaves.
+	ScalarEqual ScalarEqual
+}
+
 // IsSame returns true if the comparison returned no changes (the two
 // compared objects are similar).
 func (c *Comparison) IsSame() bool {
@@ -72,6 +93,18 @@ func (c *Comparison) ExcludeFields(fields *fieldpath.Set) *Comparison {
 	return c
 }
 
+// SplitDefaulted partitions the fields added by the comparison into those
+// added by the user and those that were only added because they were
+// defaulted, given the set of defaulted fields. Fields within defaulted
+// fields are considered defaulted too. The comparison isn't modified.
+func (c *Comparison) SplitDefaulted(defaulted *fieldpath.Set) (userAdded, defaultAdded *fieldpath.Set) {
+	if defaulted == nil || defaulted.Empty() {
+		return c.Added, fieldpath.NewSet()
+	}
+	userAdded = c.Added.RecursiveDifference(defaulted)
+	return userAdded, c.Added.Difference(userAdded)
+}
+
 func (c *Comparison) FilterFields(filter fieldpath.Filter) *Comparison {
 	if filter == nil {
 		return c
@@ -94,6 +127,12 @@ type compareWalker struct {
 	// Resulting comparison.
 	comparison *Comparison
 
+	// Semantic equality of named types, if any.
+	equalities Equalities
+
+	// Equality of scalars, if not value.Equals.
+	scalarEqual ScalarEqual
+
 	// internal housekeeping--don't set when constructing.
 	inLeaf bool // Set to true if we're in a "big leaf"--atomic map/list
 
@@ -101,6 +140,9 @@ type compareWalker struct {
 	spareWalkers *[]*compareWalker
 
 	allocator value.Allocator
+
+	// The maximum length of path.
+	maxDepth int
 }
 
 // compare compares stuff.
@@ -109,6 +151,9 @@ func (w *compareWalker) compare(prefixFn func() string) (errs ValidationErrors)
 		// check this condidition here instead of everywhere below.
 		return errorf("at least one of lhs and rhs must be provided")
 	}
+	if len(w.path) > w.maxDepth {
+		return errorf("maximum nesting depth exceeded").WithLazyPrefix(prefixFn)
+	}
 	a, ok := w.schema.Resolve(w.typeRef)
 	if !ok {
 		return errorf("schema error: no type found matching: %v", *w.typeRef.NamedType)
@@ -140,8 +185,9 @@ func (w *compareWalker) compare(prefixFn func() string) (errs ValidationErrors)
 }
 
 // doLeaf should be called on leaves before descending into children, if there
-// will be a descent. It modifies w.inLeaf.
-func (w *compareWalker) doLeaf() {
+// will be a descent. It modifies w.inLeaf. scalar is true if the leaf is a
+// scalar.
+func (w *compareWalker) doLeaf(scalar bool) {
 	if w.inLeaf {
 		// We're in a "big leaf", an atomic map or list. Ignore
 		// subsequent leaves.
@@ -154,13 +200,32 @@ func (w *compareWalker) doLeaf() {
 		w.comparison.Added.Insert(w.path)
 	} else if w.rhs == nil {
 		w.comparison.Removed.Insert(w.path)
-	} else if !value.EqualsUsing(w.allocator, w.rhs, w.lhs) {
+	} else if !w.equal(scalar) && !w.semanticallyEqual() {
 		// TODO: Equality is not sufficient for this.
 		// Need to implement equality check on the value type.
 		w.comparison.Modified.Insert(w.path)
 	}
 }
 
+// equal returns true if lhs and rhs are equal, using scalarEqual for
+// scalars if set.
+func (w *compareWalker) equal(scalar bool) bool {
+	if scalar && w.scalarEqual != nil {
+		return w.scalarEqual(w.lhs, w.rhs, w.path)
+	}
+	return value.EqualsUsing(w.allocator, w.rhs, w.lhs)
+}
+
+// semanticallyEqual returns true if the equality function registered for
+// the current type considers lhs and rhs equal.
+func (w *compareWalker) semanticallyEqual() bool {
+	if w.typeRef.NamedType == nil {
+		return false
+	}
+	equal, ok := w.equalities[*w.typeRef.NamedType]
+	return ok && equal(w.lhs, w.rhs)
+}
+
 func (w *compareWalker) doScalar(t *schema.Scalar) ValidationErrors {
 	// Make sure at least one side is a valid scalar.
 	lerrs := validateScalar(t, w.lhs, "lhs: ")
@@ -170,7 +235,7 @@ func (w *compareWalker) doScalar(t *schema.Scalar) ValidationErrors {
 	}
 
 	// All scalars are leaf fields.
-	w.doLeaf()
+	w.doLeaf(true)
 
 	return nil
 }
@@ -403,7 +468,7 @@ func (w *compareWalker) doList(t *schema.List) (errs ValidationErrors) {
 	emptyPromoteToLeaf := (lhs == nil || lhs.Length() == 0) && (rhs == nil || rhs.Length() == 0)
 
 	if t.ElementRelationship == schema.Atomic || emptyPromoteToLeaf {
-		w.doLeaf()
+		w.doLeaf(false)
 		return nil
 	}
 
@@ -419,6 +484,10 @@ func (w *compareWalker) doList(t *schema.List) (errs ValidationErrors) {
 func (w *compareWalker) visitMapItem(t *schema.Map, out map[string]interface{}, key string, lhs, rhs value.Value) (errs ValidationErrors) {
 	fieldType := t.ElementType
 	if sf, ok := t.FindField(key); ok {
+		if sf.Computed {
+			// Computed fields are never reported as changed.
+			return nil
+		}
 		fieldType = sf.Type
 	}
 	pe := fieldpath.PathElement{FieldName: &key}
@@ -456,7 +525,7 @@ func (w *compareWalker) doMap(t *schema.Map) (errs ValidationErrors) {
 	emptyPromoteToLeaf := (lhs == nil || lhs.Empty()) && (rhs == nil || rhs.Empty())
 
 	if t.ElementRelationship == schema.Atomic || emptyPromoteToLeaf {
-		w.doLeaf()
+		w.doLeaf(false)
 		return nil
 	}
 
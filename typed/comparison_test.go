@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath/fieldpathtesting"
 	"sigs.k8s.io/structured-merge-diff/v4/typed"
 )
 
@@ -74,3 +75,84 @@ func TestComparisonExcludeFields(t *testing.T) {
 		})
 	}
 }
+
+func TestComparisonSplitDefaulted(t *testing.T) {
+	parser, err := typed.NewParser(`types:
+- name: service
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    - name: ports
+      type:
+        list:
+          elementType:
+            namedType: port
+          elementRelationship: associative
+          keys:
+          - port
+          - protocol
+    - name: sessionAffinity
+      type:
+        map:
+          fields:
+          - name: timeoutSeconds
+            type:
+              scalar: numeric
+- name: port
+  map:
+    fields:
+    - name: port
+      type:
+        scalar: numeric
+    - name: protocol
+      type:
+        scalar: string
+      default: TCP
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt := parser.Type("service")
+	before, err := pt.FromYAML(`name: a`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := pt.FromYAML(`
+name: a
+ports:
+- port: 80
+  protocol: TCP
+sessionAffinity:
+  timeoutSeconds: 10
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := before.Compare(after)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port := fieldpath.KeyByFields("port", 80, "protocol", "TCP")
+	defaulted := fieldpath.NewSet(
+		fieldpath.MakePathOrDie("ports", port, "protocol"),
+		fieldpath.MakePathOrDie("sessionAffinity"),
+	)
+	userAdded, defaultAdded := c.SplitDefaulted(defaulted)
+	fieldpathtesting.AssertSetEquals(t, userAdded, fieldpath.NewSet(
+		fieldpath.MakePathOrDie("ports"),
+		fieldpath.MakePathOrDie("ports", port),
+		fieldpath.MakePathOrDie("ports", port, "port"),
+	))
+	fieldpathtesting.AssertSetEquals(t, defaultAdded, fieldpath.NewSet(
+		fieldpath.MakePathOrDie("ports", port, "protocol"),
+		fieldpath.MakePathOrDie("sessionAffinity"),
+		fieldpath.MakePathOrDie("sessionAffinity", "timeoutSeconds"),
+	))
+
+	userAdded, defaultAdded = c.SplitDefaulted(nil)
+	fieldpathtesting.AssertSetEquals(t, userAdded, c.Added)
+	fieldpathtesting.AssertSetEquals(t, defaultAdded, nil)
+}
@@ -0,0 +1,34 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed
+
+import (
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+// Coverage returns how many of the leaf fields of tv are in owned, along with
+// the total number of leaf fields of tv. Paths in owned that are not leaves
+// of tv, e.g. because they have since been removed from the object, are not
+// counted.
+func Coverage(tv *TypedValue, owned *fieldpath.Set) (int, int, error) {
+	set, err := tv.ToFieldSet()
+	if err != nil {
+		return 0, 0, err
+	}
+	leaves := set.Leaves()
+	return leaves.Intersection(owned).Size(), leaves.Size(), nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/schema"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// DropEmptyContainers returns a copy of tv without the empty maps and lists
+// whose path isn't in keep, e.g. the paths owned by managers. Containers
+// that become empty once their empty children are dropped are dropped too.
+// The root of the object is always kept.
+func (tv TypedValue) DropEmptyContainers(keep *fieldpath.Set) (*TypedValue, error) {
+	if tv.value == nil {
+		return &tv, nil
+	}
+	out, _, err := dropEmptyContainers(tv.schema, tv.typeRef, tv.value, fieldpath.Path{}, keep)
+	if err != nil {
+		return nil, err
+	}
+	tv.value = value.NewValueInterface(out)
+	return &tv, nil
+}
+
+// dropEmptyContainers returns v without its empty containers, and false if
+// v itself is an empty container that should be dropped from its parent.
+func dropEmptyContainers(s *schema.Schema, tr schema.TypeRef, v value.Value, path fieldpath.Path, keep *fieldpath.Set) (interface{}, bool, error) {
+	a, ok := s.Resolve(tr)
+	if !ok {
+		return nil, false, fmt.Errorf("schema error: no type found matching: %v", tr)
+	}
+	a = deduceAtom(a, v)
+	var out interface{}
+	var length int
+	switch {
+	case v.IsMap() && a.Map != nil && a.Map.ElementRelationship != schema.Atomic:
+		m := map[string]interface{}{}
+		var err error
+		v.AsMap().Iterate(func(key string, val value.Value) bool {
+			fieldType := a.Map.ElementType
+			if sf, ok := a.Map.FindField(key); ok {
+				fieldType = sf.Type
+			}
+			var child interface{}
+			var kept bool
+			child, kept, err = dropEmptyContainers(s, fieldType, val, append(path, fieldpath.PathElement{FieldName: &key}), keep)
+			if kept {
+				m[key] = child
+			}
+			return err == nil
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		out, length = m, len(m)
+	case v.IsList() && a.List != nil && a.List.ElementRelationship == schema.Associative:
+		l := v.AsList()
+		items := make([]interface{}, 0, l.Length())
+		for i := 0; i < l.Length(); i++ {
+			item := l.At(i)
+			pe, err := listItemToPathElement(value.HeapAllocator, s, a.List, item)
+			if err != nil {
+				// Items that can't be addressed are kept as is.
+				items = append(items, item.Unstructured())
+				continue
+			}
+			child, kept, err := dropEmptyContainers(s, a.List.ElementType, item, append(path, pe), keep)
+			if err != nil {
+				return nil, false, err
+			}
+			if kept {
+				items = append(items, child)
+			}
+		}
+		out, length = items, len(items)
+	case v.IsMap():
+		out, length = v.Unstructured(), v.AsMap().Length()
+	case v.IsList():
+		out, length = v.Unstructured(), v.AsList().Length()
+	default:
+		return v.Unstructured(), true, nil
+	}
+	if length == 0 && len(path) != 0 && !keep.Has(path) {
+		return nil, false, nil
+	}
+	return out, true, nil
+}
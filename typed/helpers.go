@@ -30,14 +30,27 @@ import (
 type ValidationError struct {
 	Path         string
 	ErrorMessage string
+	// TypeChain is the list of named types resolved from the root to
+	// the failing value. It is only set when validating with
+	// ExplainTypes.
+	TypeChain []string
+	// Line and Column are the position of the failing value in the YAML
+	// document it was read from, starting at 1. They are only set by
+	// ParseableType.FromYAML, are 0 if unknown, and aren't part of the
+	// error message.
+	Line, Column int
 }
 
 // Error returns a human readable error message.
 func (ve ValidationError) Error() string {
+	message := ve.ErrorMessage
+	if len(ve.TypeChain) != 0 {
+		message = fmt.Sprintf("%v (types: %v)", message, strings.Join(ve.TypeChain, " -> "))
+	}
 	if len(ve.Path) == 0 {
-		return ve.ErrorMessage
+		return message
 	}
-	return fmt.Sprintf("%s: %v", ve.Path, ve.ErrorMessage)
+	return fmt.Sprintf("%s: %v", ve.Path, message)
 }
 
 // ValidationErrors accumulates multiple validation error messages.
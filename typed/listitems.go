@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed
+
+import (
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/schema"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// PathElementForListItem returns the path element identifying item, found
+// at the given index of a list of type list, in the paths of the fields of
+// the objects of schema s (see TypedValue.ToFieldSet). There are three
+// cases, depending on the list:
+//
+//   - The items of associative lists with keys are identified by the
+//     values of their key fields, e.g. [name="a",protocol="TCP"]. Key
+//     fields that the item omits take their default value from the
+//     schema, and the item is invalid if they don't have one.
+//   - The items of associative lists without keys, which are sets of
+//     scalars, are identified by their value, e.g. [="a"].
+//   - The items of other lists, which are atomic, are identified by their
+//     index, e.g. [0]. Such paths are only found in validation errors, as
+//     atomic lists are owned as a whole.
+//
+// An error is returned if the item can't be identified, e.g. if it lacks
+// a key field or if it isn't a scalar in a set.
+func PathElementForListItem(s *schema.Schema, list *schema.List, index int, item value.Value) (fieldpath.PathElement, error) {
+	return listItemPathElement(value.HeapAllocator, s, list, index, item)
+}
+
+func listItemPathElement(a value.Allocator, s *schema.Schema, list *schema.List, index int, item value.Value) (fieldpath.PathElement, error) {
+	if list.ElementRelationship != schema.Associative {
+		return fieldpath.PathElement{Index: &index}, nil
+	}
+	return listItemToPathElement(a, s, list, item)
+}
+
+// CreatedListItems returns the paths of the associative list items of
+// merged that don't exist in live, including items nested in new items.
+func CreatedListItems(live, merged *TypedValue) (*fieldpath.Set, error) {
+	liveSet, err := live.ToFieldSet()
+	if err != nil {
+		return nil, err
+	}
+	mergedSet, err := merged.ToFieldSet()
+	if err != nil {
+		return nil, err
+	}
+	created := fieldpath.NewSet()
+	mergedSet.Difference(liveSet).Iterate(func(p fieldpath.Path) {
+		if last, ok := p.Last(); ok && (last.Key != nil || last.Value != nil) {
+			created.Insert(p.Copy())
+		}
+	})
+	return created, nil
+}
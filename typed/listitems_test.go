@@ -0,0 +1,133 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+func TestPathElementForListItem(t *testing.T) {
+	parser, err := typed.NewParser(`types:
+- name: keyed
+  list:
+    elementType:
+      namedType: item
+    elementRelationship: associative
+    keys:
+    - name
+    - protocol
+- name: item
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    - name: protocol
+      type:
+        scalar: string
+      default: TCP
+    - name: port
+      type:
+        scalar: numeric
+- name: set
+  list:
+    elementType:
+      scalar: string
+    elementRelationship: associative
+- name: atomic
+  list:
+    elementType:
+      scalar: string
+    elementRelationship: atomic
+`)
+	if err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	index := func(i int) fieldpath.PathElement {
+		return fieldpath.PathElement{Index: &i}
+	}
+	setItem := func(v interface{}) fieldpath.PathElement {
+		val := value.NewValueInterface(v)
+		return fieldpath.PathElement{Value: &val}
+	}
+
+	for _, tt := range []struct {
+		name     string
+		list     string
+		index    int
+		item     interface{}
+		expected fieldpath.PathElement
+		err      bool
+	}{{
+		name:     "keyed",
+		list:     "keyed",
+		index:    1,
+		item:     map[string]interface{}{"name": "a", "protocol": "UDP", "port": 80},
+		expected: fieldpath.PathElement{Key: _KBF("name", "a", "protocol", "UDP")},
+	}, {
+		name:     "keyed with default",
+		list:     "keyed",
+		item:     map[string]interface{}{"name": "a"},
+		expected: fieldpath.PathElement{Key: _KBF("name", "a", "protocol", "TCP")},
+	}, {
+		name: "keyed without key",
+		list: "keyed",
+		item: map[string]interface{}{"port": 80},
+		err:  true,
+	}, {
+		name:     "set",
+		list:     "set",
+		index:    1,
+		item:     "a",
+		expected: setItem("a"),
+	}, {
+		name: "set of maps",
+		list: "set",
+		item: map[string]interface{}{"name": "a"},
+		err:  true,
+	}, {
+		name:     "atomic",
+		list:     "atomic",
+		index:    1,
+		item:     "a",
+		expected: index(1),
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			td, ok := parser.Schema.FindNamedType(tt.list)
+			if !ok {
+				t.Fatalf("type %v not found", tt.list)
+			}
+			pe, err := typed.PathElementForListItem(&parser.Schema, td.List, tt.index, value.NewValueInterface(tt.item))
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", pe)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !pe.Equals(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, pe)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed
+
+import (
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/schema"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// ValidateListKeys checks the items of the associative lists with keys
+// within tv: each key field must be set by the item, or have a default
+// value, and be a scalar, and items must not have the same key as another
+// item only because of default values. Problems are reported for each
+// item, at the index of the item in its list, and the items with problems
+// aren't checked any further. The error is set if the keys can't be
+// checked because of the schema, e.g. if the items of a list with keys
+// aren't maps.
+func (tv TypedValue) ValidateListKeys() (ValidationErrors, error) {
+	w := listKeysWalker{schema: tv.schema}
+	if err := w.walk(tv.value, tv.typeRef, ""); err != nil {
+		return nil, err
+	}
+	return w.errs, nil
+}
+
+// listKeysWalker collects the problems with the keys of the associative
+// list items of a value.
+type listKeysWalker struct {
+	schema *schema.Schema
+	errs   ValidationErrors
+}
+
+func (w *listKeysWalker) walk(v value.Value, tr schema.TypeRef, path string) error {
+	a, ok := w.schema.Resolve(tr)
+	if !ok || v == nil {
+		return nil
+	}
+	a = deduceAtom(a, v)
+	switch {
+	case a.Map != nil && v.IsMap():
+		var err error
+		v.AsMap().Iterate(func(name string, child value.Value) bool {
+			fieldType := a.Map.ElementType
+			if sf, ok := a.Map.FindField(name); ok {
+				fieldType = sf.Type
+			}
+			err = w.walk(child, fieldType, path+fieldpath.PathElement{FieldName: &name}.String())
+			return err == nil
+		})
+		return err
+	case a.List != nil && v.IsList():
+		return w.walkList(a.List, v.AsList(), path)
+	}
+	return nil
+}
+
+func (w *listKeysWalker) walkList(t *schema.List, l value.List, path string) error {
+	keyed := t.ElementRelationship == schema.Associative && len(t.Keys) > 0
+	observed := fieldpath.MakePathElementValueMap(l.Length())
+	for i := 0; i < l.Length(); i++ {
+		child := l.At(i)
+		pe := fieldpath.PathElement{Index: &i}
+		itemPath := path + pe.String()
+		if keyed {
+			errs, err := w.checkKeys(t, child)
+			if err != nil {
+				return err
+			}
+			if len(errs) > 0 {
+				w.errs = append(w.errs, errs.WithPrefix(itemPath)...)
+				continue
+			}
+			if pe, err = listItemToPathElement(value.HeapAllocator, w.schema, t, child); err != nil {
+				return err
+			}
+			if first, ok := observed.Get(pe); !ok {
+				observed.Insert(pe, child)
+			} else if omitted := omittedKeyFields(t, child, first); len(omitted) > 0 {
+				w.errs = append(w.errs, errorf("key %v is ambiguous because key fields %q are defaulted", pe.String(), omitted).WithPrefix(itemPath)...)
+				continue
+			}
+			itemPath = path + pe.String()
+		}
+		if err := w.walk(child, t.ElementType, itemPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkKeys returns the problems with the key fields of child, an item of
+// the associative list t.
+func (w *listKeysWalker) checkKeys(t *schema.List, child value.Value) (ValidationErrors, error) {
+	if child.IsNull() {
+		return errorf("associative list with keys may not have a null element"), nil
+	}
+	if !child.IsMap() {
+		return errorf("associative list with keys may not have non-map elements"), nil
+	}
+	var errs ValidationErrors
+	m := child.AsMap()
+	for _, key := range t.Keys {
+		val, ok := m.Get(key)
+		if !ok {
+			def, err := getAssociativeKeyDefault(w.schema, t, key)
+			if err != nil {
+				return nil, err
+			}
+			if def == nil {
+				errs = append(errs, errorf("key field %q is missing and has no default value", key)...)
+			}
+			continue
+		}
+		if !val.IsScalar() {
+			errs = append(errs, errorf("key field %q is not a scalar: %v", key, value.ToString(val))...)
+		}
+	}
+	return errs, nil
+}
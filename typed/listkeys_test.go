@@ -0,0 +1,122 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed_test
+
+import (
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+var listKeysParser = func() *typed.Parser {
+	parser, err := typed.NewParser(`types:
+- name: pod
+  map:
+    fields:
+    - name: ports
+      type:
+        list:
+          elementType:
+            namedType: port
+          elementRelationship: associative
+          keys:
+          - port
+          - protocol
+- name: port
+  map:
+    fields:
+    - name: port
+      type:
+        scalar: numeric
+    - name: protocol
+      default: "TCP"
+      type:
+        scalar: string
+    - name: name
+      type:
+        scalar: string
+    - name: aliases
+      type:
+        list:
+          elementType:
+            map:
+              fields:
+              - name: alias
+                type:
+                  scalar: string
+          elementRelationship: associative
+          keys:
+          - alias
+`)
+	if err != nil {
+		panic(err)
+	}
+	return parser
+}()
+
+func TestValidateListKeys(t *testing.T) {
+	tests := map[string]struct {
+		object   string
+		expected []string
+	}{
+		"valid": {
+			object: `{"ports": [{"port": 80}, {"port": 80, "protocol": "UDP"}, {"port": 443, "aliases": [{"alias": "https"}]}]}`,
+		},
+		"missing_undefaulted_key": {
+			object:   `{"ports": [{"port": 80}, {"protocol": "UDP"}, {"name": "other"}]}`,
+			expected: []string{`.ports[1]: key field "port" is missing and has no default value`, `.ports[2]: key field "port" is missing and has no default value`},
+		},
+		"ambiguous": {
+			object:   `{"ports": [{"port": 80, "protocol": "TCP"}, {"port": 443}, {"port": 80}]}`,
+			expected: []string{`.ports[2]: key [port=80,protocol="TCP"] is ambiguous because key fields ["protocol"] are defaulted`},
+		},
+		"non_scalar_keys": {
+			object:   `{"ports": [{"port": [80], "protocol": {"name": "TCP"}}, {"port": null}]}`,
+			expected: []string{`.ports[0]: key field "port" is not a scalar: [80]`, `.ports[0]: key field "protocol" is not a scalar: name="TCP"`, `.ports[1]: key field "port" is not a scalar: null`},
+		},
+		"non_map_item": {
+			object:   `{"ports": [80, null]}`,
+			expected: []string{`.ports[0]: associative list with keys may not have non-map elements`, `.ports[1]: associative list with keys may not have a null element`},
+		},
+		"nested": {
+			object:   `{"ports": [{"port": 80, "aliases": [{"alias": "http"}, {}]}]}`,
+			expected: []string{`.ports[port=80,protocol="TCP"].aliases[1]: key field "alias" is missing and has no default value`},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			v, err := value.FromJSON([]byte(test.object))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tv := typed.AsTypedUnvalidated(v, &listKeysParser.Schema, listKeysParser.Type("pod").TypeRef)
+			errs, err := tv.ValidateListKeys()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Error())
+			}
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("expected errors:\n%q\ngot:\n%q", test.expected, got)
+			}
+		})
+	}
+}
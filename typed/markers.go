@@ -0,0 +1,453 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/schema"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+const (
+	// MarkerKey is the name of the field that turns a map into a marker.
+	// A marker is placed in an object where a field or an associative list
+	// item would normally be, e.g.:
+	//
+	//   spec:
+	//     replicas: {k8s_io__value: unset}
+	//     containers:
+	//     - name: sidecar
+	//       k8s_io__value: unset
+	MarkerKey = "k8s_io__value"
+
+	// UnsetMarker is the marker value that requests the field or item to
+	// be removed.
+	UnsetMarker = "unset"
+
+	// ReplaceMarker is the marker value that requests the contents of a
+	// granular map or associative list to be replaced as a whole, as if
+	// it was atomic. Unlike an unset marker, it doesn't stand for the value
+	// but is carried by it: a map carries it as one of its fields, and a
+	// list as an item made of the marker only, e.g.:
+	//
+	//   spec:
+	//     selector:
+	//       k8s_io__value: replace
+	//       app: web
+	//     containers:
+	//     - k8s_io__value: replace
+	//     - name: web
+	ReplaceMarker = "replace"
+)
+
+// ExtractMarkers removes all the markers from tv, and returns the value
+// without the markers along with the set of paths that were marked as unset.
+// Markers are only recognized where fields or items are independent, i.e.
+// not within atomic maps or lists. Replace markers are removed too, use
+// ExtractMarkersWithReplace to get their paths.
+func ExtractMarkers(tv *TypedValue) (*TypedValue, *fieldpath.Set, error) {
+	out, unset, _, err := ExtractMarkersWithReplace(tv)
+	return out, unset, err
+}
+
+// ExtractMarkersWithReplace is like ExtractMarkers, but also returns the set
+// of paths of the maps and associative lists that were marked for
+// replacement, to be given as MergeOptions.Replace. Replace markers are only
+// allowed where unset markers are, on granular maps and associative lists.
+func ExtractMarkersWithReplace(tv *TypedValue) (out *TypedValue, unset, replace *fieldpath.Set, err error) {
+	w := markerExtractor{
+		schema:    tv.schema,
+		allocator: value.NewFreelistAllocator(),
+		unset:     fieldpath.NewSet(),
+		replace:   fieldpath.NewSet(),
+	}
+	v, _, errs := w.extract(tv.value, tv.typeRef)
+	if len(errs) != 0 {
+		return nil, nil, nil, errs
+	}
+	result := *tv
+	result.value = value.NewValueInterface(v)
+	return &result, w.unset, w.replace, nil
+}
+
+// ValidateMarkers checks the markers of tv the way ExtractMarkersWithReplace
+// does, and returns the same validation errors, without building the value
+// stripped of its markers. An error is returned if the type of tv can't be
+// resolved.
+func (tv *TypedValue) ValidateMarkers() (ValidationErrors, error) {
+	if _, ok := tv.schema.Resolve(tv.typeRef); !ok {
+		return nil, fmt.Errorf("schema error: no type found matching: %v", tv.typeRef)
+	}
+	w := markerExtractor{
+		schema:       tv.schema,
+		allocator:    value.NewFreelistAllocator(),
+		unset:        fieldpath.NewSet(),
+		replace:      fieldpath.NewSet(),
+		validateOnly: true,
+	}
+	_, _, errs := w.extract(tv.value, tv.typeRef)
+	return errs, nil
+}
+
+// OwnedPathsForApply returns the set of paths that an applier would own by
+// applying config, without performing the merge. This is made of the fields
+// set in config once its markers are extracted, along with the paths marked
+// as unset.
+func OwnedPathsForApply(config *TypedValue) (*fieldpath.Set, error) {
+	extracted, unset, err := ExtractMarkers(config)
+	if err != nil {
+		return nil, err
+	}
+	set, err := extracted.ToFieldSet()
+	if err != nil {
+		return nil, err
+	}
+	return set.Union(unset), nil
+}
+
+type markerExtractor struct {
+	schema    *schema.Schema
+	allocator value.Allocator
+	path      fieldpath.Path
+	unset     *fieldpath.Set
+	replace   *fieldpath.Set
+	// If set to true, markers are only checked and no value is built.
+	validateOnly bool
+}
+
+// isMarker returns the marker carried by v, if any.
+func isMarker(a value.Allocator, v value.Value) (string, bool, error) {
+	if v == nil || !v.IsMap() {
+		return "", false, nil
+	}
+	m := v.AsMapUsing(a)
+	defer a.Free(m)
+	marker, ok := m.Get(MarkerKey)
+	if !ok {
+		return "", false, nil
+	}
+	if !marker.IsString() || (marker.AsString() != UnsetMarker && marker.AsString() != ReplaceMarker) {
+		return "", true, fmt.Errorf("unknown marker: %v", value.ToString(marker))
+	}
+	return marker.AsString(), true, nil
+}
+
+// isReplaceItem returns true if v is the list item that carries a replace
+// marker for its list.
+func isReplaceItem(a value.Allocator, v value.Value) bool {
+	if v == nil || !v.IsMap() {
+		return false
+	}
+	m := v.AsMapUsing(a)
+	defer a.Free(m)
+	marker, ok := m.Get(MarkerKey)
+	return ok && m.Length() == 1 && marker.IsString() && marker.AsString() == ReplaceMarker
+}
+
+// checkReplaceMarker returns an error unless a, the type of a value that
+// carries a replace marker, is a granular map.
+func checkReplaceMarker(a schema.Atom) error {
+	if a.Map == nil || a.Map.ElementRelationship == schema.Atomic {
+		return fmt.Errorf("replace marker is only allowed on granular maps and associative lists")
+	}
+	return nil
+}
+
+// extract returns v without its markers, and false if v itself is a marker
+// and should be dropped from its parent.
+func (w *markerExtractor) extract(v value.Value, tr schema.TypeRef) (interface{}, bool, ValidationErrors) {
+	marker, isMarked, err := isMarker(w.allocator, v)
+	if err != nil {
+		return nil, false, errorf("%v", err).WithPrefix(w.path.String())
+	} else if isMarked && marker == UnsetMarker {
+		w.unset.Insert(w.path.Copy())
+		return nil, false, nil
+	}
+	a, ok := w.schema.Resolve(tr)
+	if !ok {
+		return nil, false, errorf("schema error: no type found matching: %v", tr)
+	}
+	a = deduceAtom(a, v)
+	if isMarked {
+		if err := checkReplaceMarker(a); err != nil {
+			return nil, false, errorf("%v", err).WithPrefix(w.path.String())
+		}
+		w.replace.Insert(w.path.Copy())
+	}
+	switch {
+	case a.Map != nil && v.IsMap() && a.Map.ElementRelationship != schema.Atomic:
+		return w.extractMap(a.Map, v)
+	case a.List != nil && v.IsList() && a.List.ElementRelationship == schema.Associative:
+		return w.extractList(a.List, v)
+	}
+	if w.validateOnly {
+		return nil, true, nil
+	}
+	return v.Unstructured(), true, nil
+}
+
+func (w *markerExtractor) extractMap(t *schema.Map, v value.Value) (interface{}, bool, ValidationErrors) {
+	var errs ValidationErrors
+	m := v.AsMapUsing(w.allocator)
+	defer w.allocator.Free(m)
+	var out map[string]interface{}
+	if !w.validateOnly {
+		out = make(map[string]interface{}, m.Length())
+	}
+	m.Iterate(func(key string, val value.Value) bool {
+		if key == MarkerKey {
+			// The replace marker of the map, recorded by extract.
+			return true
+		}
+		fieldType := t.ElementType
+		if sf, ok := t.FindField(key); ok {
+			fieldType = sf.Type
+		}
+		w.path = append(w.path, fieldpath.PathElement{FieldName: &key})
+		child, keep, childErrs := w.extract(val, fieldType)
+		w.path = w.path[:len(w.path)-1]
+		errs = append(errs, childErrs...)
+		if keep && !w.validateOnly {
+			out[key] = child
+		}
+		return true
+	})
+	return out, true, errs
+}
+
+func (w *markerExtractor) extractList(t *schema.List, v value.Value) (interface{}, bool, ValidationErrors) {
+	var errs ValidationErrors
+	l := v.AsListUsing(w.allocator)
+	defer w.allocator.Free(l)
+	var out []interface{}
+	if !w.validateOnly {
+		out = make([]interface{}, 0, l.Length())
+	}
+	for i := 0; i < l.Length(); i++ {
+		item := l.At(i)
+		if isReplaceItem(w.allocator, item) {
+			w.replace.Insert(w.path.Copy())
+			continue
+		}
+		pe, err := listItemToPathElement(w.allocator, w.schema, t, item)
+		if err != nil {
+			errs = append(errs, errorf("element %v: %v", i, err).WithPrefix(w.path.String())...)
+			continue
+		}
+		w.path = append(w.path, pe)
+		child, keep, childErrs := w.extract(item, t.ElementType)
+		w.path = w.path[:len(w.path)-1]
+		errs = append(errs, childErrs...)
+		if keep && !w.validateOnly {
+			out = append(out, child)
+		}
+	}
+	return out, true, errs
+}
+
+// InjectUnsetMarkers returns a copy of tv where an unset marker has been
+// placed at each path of the unset set. Fields get replaced by a marker, and
+// associative list items get replaced by a map made of their keys and the
+// marker. Items of sets and atomic lists can't be marked, nor can anything
+// within an atomic map or list.
+//
+// The returned value is not validated, since markers don't conform to the
+// schema until they are extracted.
+func InjectUnsetMarkers(tv *TypedValue, unset *fieldpath.Set) (*TypedValue, error) {
+	out := tv.value
+	var errs ValidationErrors
+	unset.Iterate(func(p fieldpath.Path) {
+		v, err := injectMarker(tv.schema, tv.typeRef, out, p)
+		if err != nil {
+			errs = append(errs, errorf("%v", err).WithPrefix(p.String())...)
+			return
+		}
+		out = value.NewValueInterface(v)
+	})
+	if len(errs) != 0 {
+		return nil, errs
+	}
+	result := *tv
+	result.value = out
+	return &result, nil
+}
+
+// injectMarker returns a copy of v with a marker at path. Only the maps and
+// lists along the path are copied.
+func injectMarker(s *schema.Schema, tr schema.TypeRef, v value.Value, path fieldpath.Path) (interface{}, error) {
+	a, ok := s.Resolve(tr)
+	if !ok {
+		return nil, fmt.Errorf("schema error: no type found matching: %v", tr)
+	}
+	pe := path[0]
+	switch {
+	case pe.FieldName != nil:
+		if a.Map == nil {
+			return nil, fmt.Errorf("expected a map for field %q", *pe.FieldName)
+		}
+		if a.Map.ElementRelationship == schema.Atomic {
+			return nil, fmt.Errorf("can't place a marker within an atomic map")
+		}
+		out := map[string]interface{}{}
+		var child value.Value
+		if v != nil && v.IsMap() {
+			v.AsMap().Iterate(func(key string, val value.Value) bool {
+				out[key] = val.Unstructured()
+				if key == *pe.FieldName {
+					child = val
+				}
+				return true
+			})
+		}
+		if len(path) == 1 {
+			out[*pe.FieldName] = map[string]interface{}{MarkerKey: UnsetMarker}
+			return out, nil
+		}
+		fieldType := a.Map.ElementType
+		if sf, ok := a.Map.FindField(*pe.FieldName); ok {
+			fieldType = sf.Type
+		}
+		c, err := injectMarker(s, fieldType, child, path[1:])
+		if err != nil {
+			return nil, err
+		}
+		out[*pe.FieldName] = c
+		return out, nil
+	case pe.Key != nil:
+		if a.List == nil || a.List.ElementRelationship != schema.Associative || len(a.List.Keys) == 0 {
+			return nil, fmt.Errorf("expected an associative list with keys for %v", pe)
+		}
+		item := map[string]interface{}{}
+		marker := map[string]interface{}{MarkerKey: UnsetMarker}
+		for _, f := range *pe.Key {
+			item[f.Name] = f.Value.Unstructured()
+			marker[f.Name] = f.Value.Unstructured()
+		}
+		var out []interface{}
+		found := false
+		if v != nil && v.IsList() {
+			l := v.AsList()
+			for i := 0; i < l.Length(); i++ {
+				child := l.At(i)
+				if found {
+					out = append(out, child.Unstructured())
+					continue
+				}
+				if childPE, err := listItemToPathElement(value.HeapAllocator, s, a.List, child); err != nil || !childPE.Equals(pe) {
+					out = append(out, child.Unstructured())
+					continue
+				}
+				found = true
+				if len(path) == 1 {
+					out = append(out, marker)
+					continue
+				}
+				c, err := injectMarker(s, a.List.ElementType, child, path[1:])
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, c)
+			}
+		}
+		if !found {
+			if len(path) == 1 {
+				out = append(out, marker)
+			} else {
+				c, err := injectMarker(s, a.List.ElementType, value.NewValueInterface(item), path[1:])
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, c)
+			}
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("can't place a marker on %v, only fields and associative list items with keys can be unset", pe)
+}
+
+// RemovalApplyConfig returns a configuration that is made of unset markers
+// only, and that removes the paths of toRemove from live once applied with
+// its markers extracted. Paths within an atomic map or list are unset at the
+// atomic value, since it can only be removed as a whole, and paths within
+// another path that is unset are dropped. The associative list items that
+// lead to a marker are made of their keys only. Like with
+// InjectUnsetMarkers, items of sets and non-associative lists can't be
+// removed.
+func RemovalApplyConfig(live *TypedValue, toRemove *fieldpath.Set) (*TypedValue, error) {
+	unset := fieldpath.NewSet()
+	var errs ValidationErrors
+	toRemove.Iterate(func(p fieldpath.Path) {
+		marked, err := markerPath(live.schema, live.typeRef, p)
+		if err != nil {
+			errs = append(errs, errorf("%v", err).WithPrefix(p.String())...)
+			return
+		}
+		unset.Insert(marked)
+	})
+	if len(errs) != 0 {
+		return nil, errs
+	}
+	minimal := fieldpath.NewSet()
+	unset.Iterate(func(p fieldpath.Path) {
+		for i := 1; i < len(p); i++ {
+			if unset.Has(p[:i]) {
+				return
+			}
+		}
+		minimal.Insert(p.Copy())
+	})
+	return InjectUnsetMarkers(live.Empty(), minimal)
+}
+
+// markerPath returns the path where the marker that removes path goes,
+// which is path itself unless it is within an atomic map or list.
+func markerPath(s *schema.Schema, tr schema.TypeRef, path fieldpath.Path) (fieldpath.Path, error) {
+	for i, pe := range path {
+		a, ok := s.Resolve(tr)
+		if !ok {
+			return nil, fmt.Errorf("schema error: no type found matching: %v", tr)
+		}
+		switch {
+		case pe.FieldName != nil && a.Map != nil:
+			if a.Map.ElementRelationship == schema.Atomic {
+				return atomicMarkerPath(path[:i])
+			}
+			tr = a.Map.ElementType
+			if sf, ok := a.Map.FindField(*pe.FieldName); ok {
+				tr = sf.Type
+			}
+		case pe.FieldName == nil && a.List != nil:
+			if a.List.ElementRelationship == schema.Atomic {
+				return atomicMarkerPath(path[:i])
+			}
+			tr = a.List.ElementType
+		default:
+			// The marker can't be placed, as InjectUnsetMarkers reports.
+			return path.Copy(), nil
+		}
+	}
+	return path.Copy(), nil
+}
+
+func atomicMarkerPath(path fieldpath.Path) (fieldpath.Path, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("can't remove a path within an atomic root")
+	}
+	return path.Copy(), nil
+}
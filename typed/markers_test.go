@@ -0,0 +1,604 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+	yaml "sigs.k8s.io/yaml/goyaml.v2"
+)
+
+var markersSchema = typed.YAMLObject(`types:
+- name: myRoot
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    - name: atomicList
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+    - name: list
+      type:
+        list:
+          elementType:
+            namedType: myElement
+          elementRelationship: associative
+          keys:
+          - key
+    - name: set
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: associative
+- name: myElement
+  map:
+    fields:
+    - name: key
+      type:
+        scalar: string
+    - name: value
+      type:
+        scalar: numeric
+`)
+
+func TestInjectUnsetMarkers(t *testing.T) {
+	parser, err := typed.NewParser(markersSchema)
+	if err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	pt := parser.Type("myRoot")
+
+	table := []struct {
+		name     string
+		object   typed.YAMLObject
+		unset    *fieldpath.Set
+		expected string
+	}{
+		{
+			name:     "scalar",
+			object:   `{"name": "a", "atomicList": ["a"]}`,
+			unset:    _NS(_P("name")),
+			expected: `{"name": {"k8s_io__value": "unset"}, "atomicList": ["a"]}`,
+		},
+		{
+			name:     "atomic-list",
+			object:   `{"name": "a", "atomicList": ["a", "b"]}`,
+			unset:    _NS(_P("atomicList")),
+			expected: `{"name": "a", "atomicList": {"k8s_io__value": "unset"}}`,
+		},
+		{
+			name:     "associative-list-item",
+			object:   `{"list": [{"key": "a", "value": 1}, {"key": "b", "value": 2}]}`,
+			unset:    _NS(_P("list", _KBF("key", "a"))),
+			expected: `{"list": [{"key": "a", "k8s_io__value": "unset"}, {"key": "b", "value": 2}]}`,
+		},
+		{
+			name:     "absent-associative-list-item",
+			object:   `{"list": [{"key": "b", "value": 2}]}`,
+			unset:    _NS(_P("list", _KBF("key", "a"))),
+			expected: `{"list": [{"key": "b", "value": 2}, {"key": "a", "k8s_io__value": "unset"}]}`,
+		},
+		{
+			name:   "multiple",
+			object: `{"name": "a", "list": [{"key": "b", "value": 2}]}`,
+			unset: _NS(
+				_P("name"),
+				_P("atomicList"),
+				_P("list", _KBF("key", "b"), "value"),
+			),
+			expected: `{"name": {"k8s_io__value": "unset"}, "atomicList": {"k8s_io__value": "unset"}, "list": [{"key": "b", "value": {"k8s_io__value": "unset"}}]}`,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tv, err := pt.FromYAML(tt.object)
+			if err != nil {
+				t.Fatalf("failed to parse object: %v", err)
+			}
+			injected, err := typed.InjectUnsetMarkers(tv, tt.unset)
+			if err != nil {
+				t.Fatalf("failed to inject markers: %v", err)
+			}
+			var expected interface{}
+			if err := yaml.Unmarshal([]byte(tt.expected), &expected); err != nil {
+				t.Fatalf("failed to parse expected object: %v", err)
+			}
+			if !value.Equals(injected.AsValue(), value.NewValueInterface(expected)) {
+				t.Errorf("expected\n%v\nbut got\n%v", tt.expected, value.ToString(injected.AsValue()))
+			}
+			// The original object must be left untouched.
+			original, _ := pt.FromYAML(tt.object)
+			if !value.Equals(tv.AsValue(), original.AsValue()) {
+				t.Errorf("expected original object to be unchanged, got %v", value.ToString(tv.AsValue()))
+			}
+
+			extracted, unset, err := typed.ExtractMarkers(injected)
+			if err != nil {
+				t.Fatalf("failed to extract markers: %v", err)
+			}
+			if !unset.Equals(tt.unset) {
+				t.Errorf("expected extracted markers\n%v\nbut got\n%v", tt.unset, unset)
+			}
+			if err := extracted.Validate(); err != nil {
+				t.Errorf("expected object without markers to be valid: %v", err)
+			}
+		})
+	}
+}
+
+func TestInjectUnsetMarkersErrors(t *testing.T) {
+	parser, err := typed.NewParser(markersSchema)
+	if err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	tv, err := parser.Type("myRoot").FromYAML(`{"atomicList": ["a"], "set": ["a"]}`)
+	if err != nil {
+		t.Fatalf("failed to parse object: %v", err)
+	}
+	for _, unset := range []*fieldpath.Set{
+		_NS(_P("atomicList", 0)),
+		_NS(_P("set", _V("a"))),
+	} {
+		if _, err := typed.InjectUnsetMarkers(tv, unset); err == nil {
+			t.Errorf("expected error when injecting markers at %v", unset)
+		}
+	}
+}
+
+func TestExtractMarkersUnknownMarker(t *testing.T) {
+	parser, err := typed.NewParser(markersSchema)
+	if err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(`{"name": {"k8s_io__value": "bogus"}}`), &v); err != nil {
+		t.Fatalf("failed to parse object: %v", err)
+	}
+	tv := typed.AsTypedUnvalidated(value.NewValueInterface(v), &parser.Schema, parser.Type("myRoot").TypeRef)
+	if _, _, err := typed.ExtractMarkers(tv); err == nil {
+		t.Errorf("expected error for unknown marker")
+	}
+}
+
+func TestOwnedPathsForApply(t *testing.T) {
+	parser, err := typed.NewParser(markersSchema)
+	if err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	table := []struct {
+		name     string
+		config   string
+		expected *fieldpath.Set
+	}{
+		{
+			name:     "no-markers",
+			config:   `{"name": "a", "atomicList": ["a"]}`,
+			expected: _NS(_P("name"), _P("atomicList")),
+		},
+		{
+			name:     "unset-field",
+			config:   `{"name": {"k8s_io__value": "unset"}, "atomicList": ["a"]}`,
+			expected: _NS(_P("name"), _P("atomicList")),
+		},
+		{
+			name:   "unset-list-item",
+			config: `{"list": [{"key": "a", "k8s_io__value": "unset"}, {"key": "b", "value": 2}]}`,
+			expected: _NS(
+				_P("list", _KBF("key", "a")),
+				_P("list", _KBF("key", "b")),
+				_P("list", _KBF("key", "b"), "key"),
+				_P("list", _KBF("key", "b"), "value"),
+			),
+		},
+		{
+			name:   "unset-nested-field",
+			config: `{"list": [{"key": "b", "value": {"k8s_io__value": "unset"}}]}`,
+			expected: _NS(
+				_P("list", _KBF("key", "b")),
+				_P("list", _KBF("key", "b"), "key"),
+				_P("list", _KBF("key", "b"), "value"),
+			),
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var v interface{}
+			if err := yaml.Unmarshal([]byte(tt.config), &v); err != nil {
+				t.Fatalf("failed to parse config: %v", err)
+			}
+			tv := typed.AsTypedUnvalidated(value.NewValueInterface(v), &parser.Schema, parser.Type("myRoot").TypeRef)
+			owned, err := typed.OwnedPathsForApply(tv)
+			if err != nil {
+				t.Fatalf("failed to get owned paths: %v", err)
+			}
+			if !owned.Equals(tt.expected) {
+				t.Errorf("expected owned paths\n%v\nbut got\n%v", tt.expected, owned)
+			}
+		})
+	}
+}
+
+func TestOwnedPathsForApplyUnknownMarker(t *testing.T) {
+	parser, err := typed.NewParser(markersSchema)
+	if err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(`{"name": {"k8s_io__value": "bogus"}}`), &v); err != nil {
+		t.Fatalf("failed to parse object: %v", err)
+	}
+	tv := typed.AsTypedUnvalidated(value.NewValueInterface(v), &parser.Schema, parser.Type("myRoot").TypeRef)
+	if _, err := typed.OwnedPathsForApply(tv); err == nil {
+		t.Errorf("expected error for unknown marker")
+	}
+}
+
+func TestAllowMarkersRoundTrip(t *testing.T) {
+	parser, err := typed.NewParser(markersSchema)
+	if err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	pt := parser.Type("myRoot")
+	const object = `{"name": {"k8s_io__value": "unset"}, "list": [{"key": "a", "k8s_io__value": "unset"}, {"key": "b", "value": {"k8s_io__value": "unset"}}]}`
+	expectedUnset := _NS(
+		_P("name"),
+		_P("list", _KBF("key", "a")),
+		_P("list", _KBF("key", "b"), "value"),
+	)
+
+	if _, err := pt.FromYAML(object); err == nil {
+		t.Fatal("expected markers to be rejected without AllowMarkers")
+	}
+	tv, err := pt.FromYAML(object, typed.AllowMarkers)
+	if err != nil {
+		t.Fatalf("failed to parse object with markers: %v", err)
+	}
+	serialized, err := value.ToYAML(tv.AsValue())
+	if err != nil {
+		t.Fatalf("failed to serialize object: %v", err)
+	}
+	reparsed, err := pt.FromYAML(typed.YAMLObject(serialized), typed.AllowMarkers)
+	if err != nil {
+		t.Fatalf("failed to reparse serialized object: %v\n%s", err, serialized)
+	}
+	var expected interface{}
+	if err := yaml.Unmarshal([]byte(object), &expected); err != nil {
+		t.Fatalf("failed to parse expected object: %v", err)
+	}
+	if !value.Equals(reparsed.AsValue(), value.NewValueInterface(expected)) {
+		t.Errorf("expected markers to survive serialization, got\n%s", serialized)
+	}
+	_, unset, err := typed.ExtractMarkers(reparsed)
+	if err != nil {
+		t.Fatalf("failed to extract markers: %v", err)
+	}
+	if !unset.Equals(expectedUnset) {
+		t.Errorf("expected extracted markers\n%v\nbut got\n%v", expectedUnset, unset)
+	}
+}
+
+func TestAllowMarkersErrors(t *testing.T) {
+	parser, err := typed.NewParser(markersSchema)
+	if err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	pt := parser.Type("myRoot")
+	for _, object := range []typed.YAMLObject{
+		`{"name": {"k8s_io__value": "bogus"}}`,
+		`{"atomicList": [{"k8s_io__value": "unset"}]}`,
+		`{"set": [{"k8s_io__value": "unset"}]}`,
+	} {
+		if _, err := pt.FromYAML(object, typed.AllowMarkers); err == nil {
+			t.Errorf("expected error for %v", object)
+		}
+	}
+}
+
+func TestReplaceMarkers(t *testing.T) {
+	parser, err := typed.NewParser(markersSchema)
+	if err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	pt := parser.Type("myRoot")
+	live, err := pt.FromYAML(`{"name": "a", "list": [{"key": "a", "value": 1}, {"key": "b", "value": 2}], "set": ["a", "b"]}`)
+	if err != nil {
+		t.Fatalf("failed to parse live object: %v", err)
+	}
+
+	table := []struct {
+		name            string
+		config          typed.YAMLObject
+		expectedReplace *fieldpath.Set
+		expected        typed.YAMLObject
+	}{
+		{
+			name:            "no-markers",
+			config:          `{"list": [{"key": "c", "value": 3}]}`,
+			expectedReplace: _NS(),
+			expected:        `{"name": "a", "list": [{"key": "a", "value": 1}, {"key": "b", "value": 2}, {"key": "c", "value": 3}], "set": ["a", "b"]}`,
+		},
+		{
+			name:            "list",
+			config:          `{"list": [{"k8s_io__value": "replace"}, {"key": "c", "value": 3}]}`,
+			expectedReplace: _NS(_P("list")),
+			expected:        `{"name": "a", "list": [{"key": "c", "value": 3}], "set": ["a", "b"]}`,
+		},
+		{
+			name:            "set",
+			config:          `{"set": ["c", {"k8s_io__value": "replace"}]}`,
+			expectedReplace: _NS(_P("set")),
+			expected:        `{"name": "a", "list": [{"key": "a", "value": 1}, {"key": "b", "value": 2}], "set": ["c"]}`,
+		},
+		{
+			name:            "list-item",
+			config:          `{"list": [{"key": "a", "k8s_io__value": "replace"}]}`,
+			expectedReplace: _NS(_P("list", _KBF("key", "a"))),
+			expected:        `{"name": "a", "list": [{"key": "a"}, {"key": "b", "value": 2}], "set": ["a", "b"]}`,
+		},
+		{
+			name:            "with-unset",
+			config:          `{"name": {"k8s_io__value": "unset"}, "list": [{"k8s_io__value": "replace"}]}`,
+			expectedReplace: _NS(_P("list")),
+			expected:        `{"name": "a", "list": [], "set": ["a", "b"]}`,
+		},
+	}
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := pt.FromYAML(tt.config, typed.AllowMarkers)
+			if err != nil {
+				t.Fatalf("failed to parse config: %v", err)
+			}
+			extracted, _, replace, err := typed.ExtractMarkersWithReplace(config)
+			if err != nil {
+				t.Fatalf("failed to extract markers: %v", err)
+			}
+			if !replace.Equals(tt.expectedReplace) {
+				t.Errorf("expected replace markers\n%v\nbut got\n%v", tt.expectedReplace, replace)
+			}
+			if err := extracted.Validate(); err != nil {
+				t.Errorf("expected the extracted value to be valid: %v", err)
+			}
+			merged, err := live.MergeWithOptions(extracted, typed.MergeOptions{Replace: replace})
+			if err != nil {
+				t.Fatalf("failed to merge: %v", err)
+			}
+			expected, err := pt.FromYAML(tt.expected)
+			if err != nil {
+				t.Fatalf("failed to parse expected object: %v", err)
+			}
+			if !value.Equals(merged.AsValue(), expected.AsValue()) {
+				t.Errorf("expected\n%v\nbut got\n%v", value.ToString(expected.AsValue()), value.ToString(merged.AsValue()))
+			}
+		})
+	}
+}
+
+func TestReplaceMarkersErrors(t *testing.T) {
+	parser, err := typed.NewParser(markersSchema)
+	if err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	pt := parser.Type("myRoot")
+	for _, object := range []string{
+		`{"name": {"k8s_io__value": "replace"}}`,
+		`{"list": {"k8s_io__value": "replace"}}`,
+		`{"atomicList": {"k8s_io__value": "replace"}}`,
+	} {
+		if _, err := pt.FromYAML(typed.YAMLObject(object), typed.AllowMarkers); err == nil {
+			t.Errorf("expected validation error for %v", object)
+		}
+		var v interface{}
+		if err := yaml.Unmarshal([]byte(object), &v); err != nil {
+			t.Fatalf("failed to parse object: %v", err)
+		}
+		tv := typed.AsTypedUnvalidated(value.NewValueInterface(v), &parser.Schema, pt.TypeRef)
+		if _, _, _, err := typed.ExtractMarkersWithReplace(tv); err == nil {
+			t.Errorf("expected extraction error for %v", object)
+		}
+	}
+}
+
+func TestValidateMarkers(t *testing.T) {
+	parser, err := typed.NewParser(markersSchema)
+	if err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	pt := parser.Type("myRoot")
+	table := []struct {
+		name   string
+		object string
+		valid  bool
+	}{
+		{"no_markers", `{"name": "a", "list": [{"key": "a", "value": 1}]}`, true},
+		{"unset_field", `{"name": {"k8s_io__value": "unset"}}`, true},
+		{"unset_item", `{"list": [{"key": "a", "k8s_io__value": "unset"}]}`, true},
+		{"replace_list", `{"list": [{"k8s_io__value": "replace"}, {"key": "a"}]}`, true},
+		{"replace_item", `{"list": [{"key": "a", "k8s_io__value": "replace"}]}`, true},
+		{"unknown_marker", `{"name": {"k8s_io__value": "bogus"}}`, false},
+		{"invalid_marker_on_scalar", `{"name": {"k8s_io__value": "replace"}}`, false},
+		{"invalid_marker_on_atomic_list", `{"atomicList": {"k8s_io__value": "replace"}}`, false},
+		{"invalid_marker_on_set_item", `{"set": [{"k8s_io__value": "unset"}]}`, false},
+		{"invalid_marker_without_key", `{"list": [{"k8s_io__value": "unset"}]}`, false},
+	}
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			var v interface{}
+			if err := yaml.Unmarshal([]byte(tt.object), &v); err != nil {
+				t.Fatalf("failed to parse object: %v", err)
+			}
+			tv := typed.AsTypedUnvalidated(value.NewValueInterface(v), &parser.Schema, pt.TypeRef)
+			errs, err := tv.ValidateMarkers()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.valid && len(errs) != 0 {
+				t.Errorf("expected no errors, got %v", errs)
+			}
+			if !tt.valid && len(errs) == 0 {
+				t.Errorf("expected errors")
+			}
+			// The same errors are reported when extracting.
+			_, _, _, extractErr := typed.ExtractMarkersWithReplace(tv)
+			if (extractErr == nil) != (len(errs) == 0) {
+				t.Errorf("expected extraction to agree, got %v", extractErr)
+			}
+		})
+	}
+
+	tv := typed.AsTypedUnvalidated(value.NewValueInterface(nil), &parser.Schema, parser.Type("unknown").TypeRef)
+	if _, err := tv.ValidateMarkers(); err == nil {
+		t.Errorf("expected an error for an unknown type")
+	}
+}
+
+func TestRemovalApplyConfig(t *testing.T) {
+	parser, err := typed.NewParser(`types:
+- name: myRoot
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    - name: labels
+      type:
+        map:
+          elementType:
+            scalar: string
+    - name: selector
+      type:
+        map:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+    - name: list
+      type:
+        list:
+          elementType:
+            namedType: myElement
+          elementRelationship: associative
+          keys:
+          - key
+- name: myElement
+  map:
+    fields:
+    - name: key
+      type:
+        scalar: string
+    - name: value
+      type:
+        scalar: numeric
+`)
+	if err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	pt := parser.Type("myRoot")
+	live, err := pt.FromYAML(`{"name": "a", "labels": {"x": "1", "y": "2"}, "selector": {"app": "web"}, "list": [{"key": "a", "value": 1}, {"key": "b", "value": 2}]}`)
+	if err != nil {
+		t.Fatalf("failed to parse object: %v", err)
+	}
+
+	table := []struct {
+		name     string
+		toRemove *fieldpath.Set
+		config   string
+		removed  string
+	}{
+		{
+			name:     "scalar",
+			toRemove: _NS(_P("name")),
+			config:   `{"name": {"k8s_io__value": "unset"}}`,
+			removed:  `{"labels": {"x": "1", "y": "2"}, "selector": {"app": "web"}, "list": [{"key": "a", "value": 1}, {"key": "b", "value": 2}]}`,
+		},
+		{
+			name: "associative-list-item",
+			toRemove: _NS(
+				_P("list", _KBF("key", "a")),
+				_P("list", _KBF("key", "a"), "key"),
+				_P("list", _KBF("key", "a"), "value"),
+			),
+			config:  `{"list": [{"key": "a", "k8s_io__value": "unset"}]}`,
+			removed: `{"name": "a", "labels": {"x": "1", "y": "2"}, "selector": {"app": "web"}, "list": [{"key": "b", "value": 2}]}`,
+		},
+		{
+			name:     "associative-list-item-field",
+			toRemove: _NS(_P("list", _KBF("key", "b"), "value")),
+			config:   `{"list": [{"key": "b", "value": {"k8s_io__value": "unset"}}]}`,
+			removed:  `{"name": "a", "labels": {"x": "1", "y": "2"}, "selector": {"app": "web"}, "list": [{"key": "a", "value": 1}, {"key": "b"}]}`,
+		},
+		{
+			name:     "granular-map-entry",
+			toRemove: _NS(_P("labels", "x")),
+			config:   `{"labels": {"x": {"k8s_io__value": "unset"}}}`,
+			removed:  `{"name": "a", "labels": {"y": "2"}, "selector": {"app": "web"}, "list": [{"key": "a", "value": 1}, {"key": "b", "value": 2}]}`,
+		},
+		{
+			name:     "atomic-map-entry",
+			toRemove: _NS(_P("selector", "app")),
+			config:   `{"selector": {"k8s_io__value": "unset"}}`,
+			removed:  `{"name": "a", "labels": {"x": "1", "y": "2"}, "list": [{"key": "a", "value": 1}, {"key": "b", "value": 2}]}`,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			config, err := typed.RemovalApplyConfig(live, tt.toRemove)
+			if err != nil {
+				t.Fatalf("failed to get removal config: %v", err)
+			}
+			var expected interface{}
+			if err := yaml.Unmarshal([]byte(tt.config), &expected); err != nil {
+				t.Fatalf("failed to parse expected config: %v", err)
+			}
+			if !value.Equals(config.AsValue(), value.NewValueInterface(expected)) {
+				t.Errorf("expected config\n%v\nbut got\n%v", tt.config, value.ToString(config.AsValue()))
+			}
+
+			extracted, unset, err := typed.ExtractMarkers(config)
+			if err != nil {
+				t.Fatalf("failed to extract markers: %v", err)
+			}
+			merged, err := live.Merge(extracted)
+			if err != nil {
+				t.Fatalf("failed to merge config: %v", err)
+			}
+			removed, err := pt.FromYAML(typed.YAMLObject(tt.removed))
+			if err != nil {
+				t.Fatalf("failed to parse expected object: %v", err)
+			}
+			if result := merged.RemoveItems(unset); !value.Equals(result.AsValue(), removed.AsValue()) {
+				t.Errorf("expected applying the config to give\n%v\nbut got\n%v", tt.removed, value.ToString(result.AsValue()))
+			}
+		})
+	}
+}
@@ -48,6 +48,67 @@ type mergingWalker struct {
 	spareWalkers *[]*mergingWalker
 
 	allocator value.Allocator
+
+	// If set, limits the number of nodes that the merge visits.
+	budget *mergeBudget
+
+	// If set, the maps and lists to merge as if they were atomic.
+	replace *fieldpath.Set
+
+	// The maximum length of path.
+	maxDepth int
+
+	// If set, the fields that are null in rhs are deleted rather than
+	// set to null.
+	nullMeansDelete bool
+
+	// If set, notified of the nodes walked.
+	tracer WalkTracer
+}
+
+// MergeOptions customizes how objects are merged by MergeWithOptions.
+type MergeOptions struct {
+	// MaxSize, if positive, makes the merge fail as soon as it visits
+	// more than this many nodes, each field, list item and atomic value
+	// counting as one, rather than building an arbitrarily large object.
+	MaxSize int
+	// Replace, if set, are the paths of the maps and lists of the rhs
+	// that replace those of the lhs as a whole, as if they were atomic,
+	// rather than being merged into them, usually the replace markers
+	// extracted from the rhs by ExtractMarkersWithReplace.
+	Replace *fieldpath.Set
+	// NullMeansDelete makes the map fields that are null in the rhs
+	// delete the field from the result, rather than set it to null.
+	// Maps that only lose fields this way are kept, even if empty.
+	NullMeansDelete bool
+	// Tracer, if set, is notified of the nodes walked by the merge.
+	Tracer WalkTracer
+}
+
+// mergeBudget counts the nodes visited by a merge, which is shared by all the
+// walkers of the merge.
+type mergeBudget struct {
+	max      int
+	visited  int
+	exceeded bool
+}
+
+// spend counts one more node, and returns false if that exceeds the budget.
+func (b *mergeBudget) spend() bool {
+	if b == nil {
+		return true
+	}
+	b.visited++
+	if b.visited > b.max {
+		b.exceeded = true
+	}
+	return !b.exceeded
+}
+
+// isExceeded returns true once the budget has been exceeded, so that the
+// walkers stop visiting the remaining map and list items.
+func (b *mergeBudget) isExceeded() bool {
+	return b != nil && b.exceeded
 }
 
 // merge rules examine w.lhs and w.rhs (up to one of which may be nil) and
@@ -73,11 +134,23 @@ func (w *mergingWalker) merge(prefixFn func() string) (errs ValidationErrors) {
 		// check this condidition here instead of everywhere below.
 		return errorf("at least one of lhs and rhs must be provided")
 	}
+	if len(w.path) > w.maxDepth {
+		return errorf("maximum nesting depth exceeded").WithLazyPrefix(prefixFn)
+	}
+	if !w.budget.spend() {
+		// Abort as soon as the budget is exceeded, the error is reported
+		// once by the caller of the merge.
+		return nil
+	}
 	a, ok := w.schema.Resolve(w.typeRef)
 	if !ok {
 		return errorf("schema error: no type found matching: %v", *w.typeRef.NamedType)
 	}
 
+	if w.tracer != nil {
+		w.tracer.Enter(w.path, w.lhs, w.rhs)
+	}
+
 	alhs := deduceAtom(a, w.lhs)
 	arhs := deduceAtom(a, w.rhs)
 
@@ -96,18 +169,35 @@ func (w *mergingWalker) merge(prefixFn func() string) (errs ValidationErrors) {
 	if !w.inLeaf && w.postItemHook != nil {
 		w.postItemHook(w)
 	}
+	if w.tracer != nil {
+		var out value.Value
+		if w.out != nil {
+			out = value.NewValueInterface(*w.out)
+		}
+		w.tracer.Leave(w.path, out)
+	}
 	return errs.WithLazyPrefix(prefixFn)
 }
 
+// replaces returns true if the current map or list is replaced rather than
+// merged, i.e. if it was marked for replacement in the rhs.
+func (w *mergingWalker) replaces() bool {
+	return w.replace != nil && w.rhs != nil && w.replace.Has(w.path)
+}
+
 // doLeaf should be called on leaves before descending into children, if there
-// will be a descent. It modifies w.inLeaf.
-func (w *mergingWalker) doLeaf() {
+// will be a descent. It modifies w.inLeaf. rule is the reason why the node is
+// a leaf.
+func (w *mergingWalker) doLeaf(rule WalkRule) {
 	if w.inLeaf {
 		// We're in a "big leaf", an atomic map or list. Ignore
 		// subsequent leaves.
 		return
 	}
 	w.inLeaf = true
+	if w.tracer != nil {
+		w.tracer.Rule(w.path, rule)
+	}
 
 	// We don't recurse into leaf fields for merging.
 	w.rule(w)
@@ -122,7 +212,7 @@ func (w *mergingWalker) doScalar(t *schema.Scalar) ValidationErrors {
 	}
 
 	// All scalars are leaf fields.
-	w.doLeaf()
+	w.doLeaf(WalkRuleScalar)
 
 	return nil
 }
@@ -205,7 +295,7 @@ func (w *mergingWalker) visitListItems(t *schema.List, lhs, rhs value.List) (err
 
 	mergedRHS := fieldpath.MakePathElementMap(len(rhsPEs))
 	lLen, rLen = len(lhsPEs), len(rhsPEs)
-	for lI, rI := 0, 0; lI < lLen || rI < rLen; {
+	for lI, rI := 0, 0; (lI < lLen || rI < rLen) && !w.budget.isExceeded(); {
 		if lI < lLen && rI < rLen {
 			pe := lhsPEs[lI]
 			if pe.Equals(rhsPEs[rI]) {
@@ -352,8 +442,14 @@ func (w *mergingWalker) doList(t *schema.List) (errs ValidationErrors) {
 	// distinction.
 	emptyPromoteToLeaf := (lhs == nil || lhs.Length() == 0) && (rhs == nil || rhs.Length() == 0)
 
-	if t.ElementRelationship == schema.Atomic || emptyPromoteToLeaf {
-		w.doLeaf()
+	if t.ElementRelationship == schema.Atomic {
+		w.doLeaf(WalkRuleAtomic)
+		return nil
+	} else if emptyPromoteToLeaf {
+		w.doLeaf(WalkRuleEmpty)
+		return nil
+	} else if w.replaces() {
+		w.doLeaf(WalkRuleReplace)
 		return nil
 	}
 
@@ -369,6 +465,14 @@ func (w *mergingWalker) doList(t *schema.List) (errs ValidationErrors) {
 func (w *mergingWalker) visitMapItem(t *schema.Map, out map[string]interface{}, key string, lhs, rhs value.Value) (errs ValidationErrors) {
 	fieldType := t.ElementType
 	if sf, ok := t.FindField(key); ok {
+		if sf.Computed && lhs != nil {
+			// Computed fields keep their existing value.
+			out[key] = lhs.Unstructured()
+			if w.tracer != nil {
+				traceNode(w.tracer, childPath(w.path, fieldpath.PathElement{FieldName: &key}), lhs, rhs, WalkRuleComputed, lhs)
+			}
+			return nil
+		}
 		fieldType = sf.Type
 	}
 	pe := fieldpath.PathElement{FieldName: &key}
@@ -383,14 +487,35 @@ func (w *mergingWalker) visitMapItem(t *schema.Map, out map[string]interface{},
 	return errs
 }
 
+// deletes returns true if the field key of the map is deleted rather than
+// merged, i.e. if it is null in the rhs and nulls mean delete.
+func (w *mergingWalker) deletes(t *schema.Map, key string, lhs, rhs value.Value) bool {
+	if !w.nullMeansDelete || rhs == nil || !rhs.IsNull() {
+		return false
+	}
+	if sf, ok := t.FindField(key); ok && sf.Computed && lhs != nil {
+		// Computed fields keep their existing value.
+		return false
+	}
+	return true
+}
+
 func (w *mergingWalker) visitMapItems(t *schema.Map, lhs, rhs value.Map) (errs ValidationErrors) {
 	out := map[string]interface{}{}
+	deleted := false
 
 	value.MapZipUsing(w.allocator, lhs, rhs, value.Unordered, func(key string, lhsValue, rhsValue value.Value) bool {
+		if w.deletes(t, key, lhsValue, rhsValue) {
+			deleted = true
+			if w.tracer != nil {
+				traceNode(w.tracer, childPath(w.path, fieldpath.PathElement{FieldName: &key}), lhsValue, rhsValue, WalkRuleDelete, nil)
+			}
+			return true
+		}
 		errs = append(errs, w.visitMapItem(t, out, key, lhsValue, rhsValue)...)
-		return true
+		return !w.budget.isExceeded()
 	})
-	if len(out) > 0 {
+	if len(out) > 0 || deleted {
 		i := interface{}(out)
 		w.out = &i
 	}
@@ -412,8 +537,14 @@ func (w *mergingWalker) doMap(t *schema.Map) (errs ValidationErrors) {
 	// distinction.
 	emptyPromoteToLeaf := (lhs == nil || lhs.Empty()) && (rhs == nil || rhs.Empty())
 
-	if t.ElementRelationship == schema.Atomic || emptyPromoteToLeaf {
-		w.doLeaf()
+	if t.ElementRelationship == schema.Atomic {
+		w.doLeaf(WalkRuleAtomic)
+		return nil
+	} else if emptyPromoteToLeaf {
+		w.doLeaf(WalkRuleEmpty)
+		return nil
+	} else if w.replaces() {
+		w.doLeaf(WalkRuleReplace)
 		return nil
 	}
 
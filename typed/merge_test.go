@@ -530,3 +530,140 @@ func TestMerge(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeMaxSize(t *testing.T) {
+	parser, err := typed.NewParser(`types:
+- name: type
+  map:
+    fields:
+    - name: list
+      type:
+        list:
+          elementType:
+            namedType: item
+          elementRelationship: associative
+          keys:
+          - key
+- name: item
+  map:
+    fields:
+    - name: key
+      type:
+        scalar: string
+`)
+	if err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	pt := parser.Type("type")
+	lhs, err := pt.FromYAML(`{"list":[{"key":"a"}]}`)
+	if err != nil {
+		t.Fatalf("unable to parse lhs: %v", err)
+	}
+	rhs, err := pt.FromYAML(`{"list":[{"key":"b"},{"key":"c"}]}`)
+	if err != nil {
+		t.Fatalf("unable to parse rhs: %v", err)
+	}
+
+	// The root, the list, and the three items with their key.
+	got, err := lhs.MergeWithOptions(rhs, typed.MergeOptions{MaxSize: 8})
+	if err != nil {
+		t.Fatalf("expected merge within the budget to succeed: %v", err)
+	}
+	expected, _ := lhs.Merge(rhs)
+	if !value.Equals(got.AsValue(), expected.AsValue()) {
+		t.Errorf("Expected\n%v\nbut got\n%v\n", value.ToString(expected.AsValue()), value.ToString(got.AsValue()))
+	}
+
+	if _, err := lhs.MergeWithOptions(rhs, typed.MergeOptions{MaxSize: 7}); err == nil {
+		t.Error("expected merge over the budget to fail")
+	}
+
+	items := make([]interface{}, 10000)
+	for i := range items {
+		items[i] = map[string]interface{}{"key": fmt.Sprint(i)}
+	}
+	huge, err := typed.AsTyped(value.NewValueInterface(map[string]interface{}{"list": items}), &parser.Schema, pt.TypeRef)
+	if err != nil {
+		t.Fatalf("unable to build huge object: %v", err)
+	}
+	if _, err := lhs.MergeWithOptions(huge, typed.MergeOptions{MaxSize: 100}); err == nil {
+		t.Error("expected huge merge to fail")
+	}
+}
+
+func TestMergeNullMeansDelete(t *testing.T) {
+	parser, err := typed.NewParser(`types:
+- name: type
+  map:
+    fields:
+    - name: scalar
+      type:
+        scalar: string
+    - name: map
+      type:
+        map:
+          elementType:
+            scalar: string
+`)
+	if err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	pt := parser.Type("type")
+	for _, tt := range []struct {
+		name            string
+		lhs             typed.YAMLObject
+		rhs             typed.YAMLObject
+		nullMeansDelete bool
+		expected        typed.YAMLObject
+	}{
+		{
+			name:     "set",
+			lhs:      `{"scalar":"a","map":{"k":"v","j":"w"}}`,
+			rhs:      `{"scalar":null,"map":{"k":null}}`,
+			expected: `{"scalar":null,"map":{"k":null,"j":"w"}}`,
+		},
+		{
+			name:            "delete",
+			lhs:             `{"scalar":"a","map":{"k":"v","j":"w"}}`,
+			rhs:             `{"scalar":null,"map":{"k":null}}`,
+			nullMeansDelete: true,
+			expected:        `{"map":{"j":"w"}}`,
+		},
+		{
+			name:            "delete map",
+			lhs:             `{"scalar":"a","map":{"k":"v"}}`,
+			rhs:             `{"map":null}`,
+			nullMeansDelete: true,
+			expected:        `{"scalar":"a"}`,
+		},
+		{
+			name:            "delete last field",
+			lhs:             `{"map":{"k":"v"}}`,
+			rhs:             `{"map":{"k":null}}`,
+			nullMeansDelete: true,
+			expected:        `{"map":{}}`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			lhs, err := pt.FromYAML(tt.lhs)
+			if err != nil {
+				t.Fatalf("unable to parse lhs: %v", err)
+			}
+			rhs, err := pt.FromYAML(tt.rhs)
+			if err != nil {
+				t.Fatalf("unable to parse rhs: %v", err)
+			}
+			expected, err := pt.FromYAML(tt.expected)
+			if err != nil {
+				t.Fatalf("unable to parse expected: %v", err)
+			}
+			got, err := lhs.MergeWithOptions(rhs, typed.MergeOptions{NullMeansDelete: tt.nullMeansDelete})
+			if err != nil {
+				t.Fatalf("merge failed: %v", err)
+			}
+			if !value.Equals(got.AsValue(), expected.AsValue()) {
+				t.Errorf("Expected\n%v\nbut got\n%v\n", value.ToString(expected.AsValue()), value.ToString(got.AsValue()))
+			}
+		})
+	}
+}
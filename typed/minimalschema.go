@@ -0,0 +1,80 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/structured-merge-diff/v4/schema"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// MinimalSchemaFor returns the subset of the schema of tv made of the named
+// types that are actually used when validating tv, including the types
+// referenced transitively by its fields and items. Types are kept in the
+// order of the original schema. Fields of these types may refer to types
+// that were pruned, as long as tv doesn't set them.
+func MinimalSchemaFor(tv *TypedValue) (schema.Schema, error) {
+	p := schemaPruner{schema: tv.schema, used: map[string]struct{}{}}
+	if err := p.visit(tv.typeRef, tv.value); err != nil {
+		return schema.Schema{}, err
+	}
+	var types []schema.TypeDef
+	for _, td := range tv.schema.Types {
+		if _, ok := p.used[td.Name]; ok {
+			types = append(types, td)
+		}
+	}
+	return schema.Schema{Types: types}, nil
+}
+
+type schemaPruner struct {
+	schema *schema.Schema
+	used   map[string]struct{}
+}
+
+func (p *schemaPruner) visit(tr schema.TypeRef, v value.Value) error {
+	if tr.NamedType != nil {
+		p.used[*tr.NamedType] = struct{}{}
+	}
+	a, ok := p.schema.Resolve(tr)
+	if !ok {
+		return fmt.Errorf("schema error: no type found matching: %v", tr)
+	}
+	if v == nil {
+		return nil
+	}
+	a = deduceAtom(a, v)
+	var err error
+	switch {
+	case a.Map != nil && v.IsMap():
+		v.AsMap().Iterate(func(key string, child value.Value) bool {
+			fieldType := a.Map.ElementType
+			if sf, ok := a.Map.FindField(key); ok {
+				fieldType = sf.Type
+			}
+			err = p.visit(fieldType, child)
+			return err == nil
+		})
+	case a.List != nil && v.IsList():
+		l := v.AsList()
+		for i := 0; i < l.Length() && err == nil; i++ {
+			err = p.visit(a.List.ElementType, l.At(i))
+		}
+	}
+	return err
+}
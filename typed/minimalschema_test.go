@@ -0,0 +1,117 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed_test
+
+import (
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+var minimalSchemaParser = func() *typed.Parser {
+	parser, err := typed.NewParser(`types:
+- name: root
+  map:
+    fields:
+    - name: spec
+      type:
+        namedType: spec
+    - name: status
+      type:
+        namedType: status
+- name: spec
+  map:
+    fields:
+    - name: containers
+      type:
+        list:
+          elementType:
+            namedType: container
+          elementRelationship: associative
+          keys:
+          - name
+    - name: volumes
+      type:
+        list:
+          elementType:
+            namedType: volume
+          elementRelationship: atomic
+- name: container
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    - name: resources
+      type:
+        namedType: resources
+- name: resources
+  map:
+    elementType:
+      namedType: quantity
+- name: quantity
+  scalar: string
+- name: volume
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+- name: status
+  map:
+    fields:
+    - name: phase
+      type:
+        scalar: string
+- name: unused
+  scalar: numeric
+`)
+	if err != nil {
+		panic(err)
+	}
+	return parser
+}()
+
+func TestMinimalSchemaFor(t *testing.T) {
+	tv, err := minimalSchemaParser.Type("root").FromYAML(`
+spec:
+  containers:
+  - name: a
+    resources:
+      cpu: "1"
+`)
+	if err != nil {
+		t.Fatalf("failed to parse object: %v", err)
+	}
+	s, err := typed.MinimalSchemaFor(tv)
+	if err != nil {
+		t.Fatalf("failed to compute minimal schema: %v", err)
+	}
+	var names []string
+	for _, td := range s.Types {
+		names = append(names, td.Name)
+	}
+	expected := []string{"root", "spec", "container", "resources", "quantity"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected types %v, got %v", expected, names)
+	}
+
+	if _, err := typed.AsTyped(tv.AsValue(), &s, tv.TypeRef()); err != nil {
+		t.Errorf("expected the object to validate against the minimal schema: %v", err)
+	}
+}
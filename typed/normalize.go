@@ -0,0 +1,116 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed
+
+import (
+	"strings"
+
+	"sigs.k8s.io/structured-merge-diff/v4/schema"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// normalizeStrings returns the unstructured copy of v where the string
+// values are normalized as required by their type (see
+// schema.Atom.Normalize), and true, if any string of v needs to be
+// normalized. Otherwise it returns false, and v can be used as is. Only the
+// maps and lists that contain normalized strings are copied. Values nested
+// deeper than depth are left as is, for the validation to report.
+func normalizeStrings(s *schema.Schema, tr schema.TypeRef, v value.Value, depth int) (interface{}, bool) {
+	if depth < 0 {
+		return nil, false
+	}
+	a, ok := s.Resolve(tr)
+	if !ok {
+		// Left for the validation to report.
+		return nil, false
+	}
+	if v.IsString() && a.Scalar != nil && a.Normalize != "" {
+		str := normalize(a.Normalize, v.AsString())
+		return str, str != v.AsString()
+	}
+	a = deduceAtom(a, v)
+	switch {
+	case a.Map != nil && v.IsMap():
+		m := v.AsMap()
+		var normalized map[string]interface{}
+		m.Iterate(func(key string, val value.Value) bool {
+			fieldType := a.Map.ElementType
+			if sf, ok := a.Map.FindField(key); ok {
+				fieldType = sf.Type
+			}
+			if u, ok := normalizeStrings(s, fieldType, val, depth-1); ok {
+				if normalized == nil {
+					normalized = map[string]interface{}{}
+				}
+				normalized[key] = u
+			}
+			return true
+		})
+		if normalized == nil {
+			return nil, false
+		}
+		out := make(map[string]interface{}, m.Length())
+		m.Iterate(func(key string, val value.Value) bool {
+			if u, ok := normalized[key]; ok {
+				out[key] = u
+			} else {
+				out[key] = val.Unstructured()
+			}
+			return true
+		})
+		return out, true
+	case a.List != nil && v.IsList():
+		l := v.AsList()
+		var normalized map[int]interface{}
+		for i := 0; i < l.Length(); i++ {
+			if u, ok := normalizeStrings(s, a.List.ElementType, l.At(i), depth-1); ok {
+				if normalized == nil {
+					normalized = map[int]interface{}{}
+				}
+				normalized[i] = u
+			}
+		}
+		if normalized == nil {
+			return nil, false
+		}
+		out := make([]interface{}, l.Length())
+		for i := range out {
+			if u, ok := normalized[i]; ok {
+				out[i] = u
+			} else {
+				out[i] = l.At(i).Unstructured()
+			}
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+// normalize applies the steps of n to str. Unknown steps are ignored.
+func normalize(n schema.Normalization, str string) string {
+	for _, step := range n.Steps() {
+		switch step {
+		case schema.NormalizeTrim:
+			str = strings.TrimSpace(str)
+		case schema.NormalizeLower:
+			str = strings.ToLower(str)
+		case schema.NormalizeUpper:
+			str = strings.ToUpper(str)
+		}
+	}
+	return str
+}
@@ -27,9 +27,19 @@ import (
 // YAMLObject is an object encoded in YAML.
 type YAMLObject string
 
+// DefaultMaxDepth is the maximum nesting depth of the values of the types
+// of a Parser whose MaxDepth is not set.
+const DefaultMaxDepth = 1000
+
 // Parser implements YAMLParser and allows introspecting the schema.
 type Parser struct {
 	Schema schema.Schema
+	// MaxDepth is the maximum number of nested maps and lists that the
+	// values of the parser's types may have. Deeper values are reported as
+	// validation errors, rather than being walked until the stack
+	// overflows, which a recursive schema would otherwise allow. If not
+	// set, DefaultMaxDepth is used.
+	MaxDepth int
 }
 
 // create builds an unvalidated parser.
@@ -59,9 +69,66 @@ func NewParser(schema YAMLObject) (*Parser, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := validateDefaults(&p.Schema); err != nil {
+		return nil, fmt.Errorf("unable to validate schema: %v", err)
+	}
+	return p, nil
+}
+
+// NewStrictParser is like NewParser, but also rejects schemas with
+// contradictory modeling that NewParser accepts for compatibility with
+// existing schemas, e.g. associative lists with keys whose elements are
+// atomic maps, or with keys that aren't scalar fields of their elements (see
+// schema.Schema.Validate).
+func NewStrictParser(schema YAMLObject) (*Parser, error) {
+	p, err := NewParser(schema)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.Schema.Validate(); err != nil {
+		return nil, fmt.Errorf("unable to validate schema: %v", err)
+	}
+	if err := validateAssociativeLists(&p.Schema); err != nil {
+		return nil, fmt.Errorf("unable to validate schema: %v", err)
+	}
 	return p, nil
 }
 
+// validateAssociativeLists checks that associative lists with keys have
+// element types that aren't atomic maps: keys address the fields of the
+// items, which can't be owned separately if the items are atomic. Such lists
+// should be atomic themselves, or have non-atomic items.
+func validateAssociativeLists(s *schema.Schema) error {
+	return s.VisitAtoms(func(a schema.Atom, path string) error {
+		if a.List == nil || a.List.ElementRelationship != schema.Associative || len(a.List.Keys) == 0 {
+			return nil
+		}
+		if elem, ok := s.Resolve(a.List.ElementType); ok && elem.Map != nil && elem.Map.ElementRelationship == schema.Atomic {
+			return fmt.Errorf("%v: associative list with keys %v can't have atomic elements", path, a.List.Keys)
+		}
+		return nil
+	})
+}
+
+// validateDefaults checks that the default values of fields conform to the
+// types of the fields.
+func validateDefaults(s *schema.Schema) error {
+	return s.VisitAtoms(func(a schema.Atom, path string) error {
+		if a.Map == nil {
+			return nil
+		}
+		for _, f := range a.Map.Fields {
+			if f.Default == nil {
+				continue
+			}
+			if _, err := AsTyped(value.NewValueInterface(f.Default), s, f.Type); err != nil {
+				return fmt.Errorf("%v.%v: invalid default value %v: %v", path, f.Name, f.Default, err)
+			}
+		}
+		return nil
+	})
+}
+
 // TypeNames returns a list of types this parser understands.
 func (p *Parser) TypeNames() (names []string) {
 	for _, td := range p.Schema.Types {
@@ -74,8 +141,9 @@ func (p *Parser) TypeNames() (names []string) {
 // errors are deferred until a further function is called.
 func (p *Parser) Type(name string) ParseableType {
 	return ParseableType{
-		Schema:  &p.Schema,
-		TypeRef: schema.TypeRef{NamedType: &name},
+		Schema:   &p.Schema,
+		TypeRef:  schema.TypeRef{NamedType: &name},
+		MaxDepth: p.MaxDepth,
 	}
 }
 
@@ -83,6 +151,10 @@ func (p *Parser) Type(name string) ParseableType {
 type ParseableType struct {
 	TypeRef schema.TypeRef
 	Schema  *schema.Schema
+	// MaxDepth is the maximum nesting depth of the objects produced, and
+	// of the objects they are merged with or compared to. If not set,
+	// DefaultMaxDepth is used.
+	MaxDepth int
 }
 
 // IsValid return true if p's schema and typename are valid.
@@ -92,14 +164,44 @@ func (p ParseableType) IsValid() bool {
 }
 
 // FromYAML parses a yaml string into an object with the current schema
-// and the type "typename" or an error if validation fails.
+// and the type "typename" or an error if validation fails. Merge keys are
+// expanded as documented by value.FromYAML. Validation errors carry the
+// position in object of the values they are about, when it can be found.
 func (p ParseableType) FromYAML(object YAMLObject, opts ...ValidationOptions) (*TypedValue, error) {
-	var v interface{}
-	err := yaml.Unmarshal([]byte(object), &v)
+	v, err := value.FromYAML([]byte(object))
+	if err != nil {
+		return nil, err
+	}
+	tv, err := asTyped(v, p.Schema, p.TypeRef, p.MaxDepth, opts...)
+	if errs, ok := err.(ValidationErrors); ok {
+		return nil, withYAMLPositions(errs, object, p.Schema, p.TypeRef, v)
+	}
+	return tv, err
+}
+
+// PreflightApply checks that config can be applied: it must validate
+// against the schema, and the items of associative lists must not rely on
+// default key values that make them ambiguous with other items. Problems
+// with config are returned as ValidationErrors, while the error is set if
+// config can't be parsed.
+func (p ParseableType) PreflightApply(config YAMLObject) (ValidationErrors, error) {
+	v, err := value.FromYAML([]byte(config))
 	if err != nil {
 		return nil, err
 	}
-	return AsTyped(value.NewValueInterface(v), p.Schema, p.TypeRef, opts...)
+	tv := TypedValue{
+		value:    v,
+		typeRef:  p.TypeRef,
+		schema:   p.Schema,
+		maxDepth: p.MaxDepth,
+	}
+	w := tv.walker()
+	w.rejectAmbiguousDefaults = true
+	defer w.finished()
+	if errs := w.validate(nil); len(errs) != 0 {
+		return errs, nil
+	}
+	return nil, nil
 }
 
 // FromUnstructured converts a go "interface{}" type, typically an
@@ -109,7 +211,7 @@ func (p ParseableType) FromYAML(object YAMLObject, opts ...ValidationOptions) (*
 // map[interface{}]interface{}, []interface{}, int types, float types,
 // string or boolean. Nested interface{} must also be one of these types.
 func (p ParseableType) FromUnstructured(in interface{}, opts ...ValidationOptions) (*TypedValue, error) {
-	return AsTyped(value.NewValueInterface(in), p.Schema, p.TypeRef, opts...)
+	return asTyped(value.NewValueInterface(in), p.Schema, p.TypeRef, p.MaxDepth, opts...)
 }
 
 // FromStructured converts a go "interface{}" type, typically an structured object in
@@ -122,7 +224,7 @@ func (p ParseableType) FromStructured(in interface{}, opts ...ValidationOptions)
 	if err != nil {
 		return nil, fmt.Errorf("error creating struct value reflector: %v", err)
 	}
-	return AsTyped(v, p.Schema, p.TypeRef, opts...)
+	return asTyped(v, p.Schema, p.TypeRef, p.MaxDepth, opts...)
 }
 
 // DeducedParseableType is a ParseableType that deduces the type from
@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"sigs.k8s.io/structured-merge-diff/v4/typed"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
 	yaml "sigs.k8s.io/yaml/goyaml.v2"
 )
 
@@ -103,3 +104,430 @@ func BenchmarkConvertUnstructured(b *testing.B) {
 		})
 	}
 }
+
+func TestNewStrictParserRejectsAtomicKeyedElements(t *testing.T) {
+	table := []struct {
+		name   string
+		schema typed.YAMLObject
+	}{
+		{
+			name: "named-element",
+			schema: `types:
+- name: root
+  map:
+    fields:
+    - name: list
+      type:
+        list:
+          elementType:
+            namedType: element
+          elementRelationship: associative
+          keys:
+          - name
+- name: element
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    elementRelationship: atomic
+`,
+		}, {
+			name: "inlined-element",
+			schema: `types:
+- name: root
+  map:
+    fields:
+    - name: list
+      type:
+        list:
+          elementType:
+            map:
+              fields:
+              - name: name
+                type:
+                  scalar: string
+              elementRelationship: atomic
+          elementRelationship: associative
+          keys:
+          - name
+`,
+		}, {
+			name: "element-reference-override",
+			schema: `types:
+- name: root
+  map:
+    fields:
+    - name: list
+      type:
+        list:
+          elementType:
+            namedType: element
+            elementRelationship: atomic
+          elementRelationship: associative
+          keys:
+          - name
+- name: element
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+`,
+		},
+	}
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := typed.NewParser(tt.schema); err != nil {
+				t.Fatalf("expected non-strict parser to accept schema: %v", err)
+			}
+			_, err := typed.NewStrictParser(tt.schema)
+			if err == nil {
+				t.Fatal("expected schema to be rejected")
+			}
+			if !strings.Contains(err.Error(), "root.list: associative list with keys [name] can't have atomic elements") {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	// Atomic elements are fine in atomic lists.
+	if _, err := typed.NewStrictParser(`types:
+- name: root
+  map:
+    fields:
+    - name: list
+      type:
+        list:
+          elementType:
+            namedType: element
+          elementRelationship: atomic
+- name: element
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    elementRelationship: atomic
+`); err != nil {
+		t.Errorf("expected atomic list of atomic elements to be accepted: %v", err)
+	}
+}
+
+func TestNewStrictParserValidatesListKeys(t *testing.T) {
+	schema := typed.YAMLObject(`types:
+- name: root
+  map:
+    fields:
+    - name: list
+      type:
+        list:
+          elementType:
+            map:
+              fields:
+              - name: name
+                type:
+                  scalar: string
+          elementRelationship: associative
+          keys:
+          - name
+          - name
+`)
+	if _, err := typed.NewParser(schema); err != nil {
+		t.Fatalf("expected non-strict parser to accept schema: %v", err)
+	}
+	_, err := typed.NewStrictParser(schema)
+	if err == nil {
+		t.Fatal("expected schema to be rejected")
+	}
+	if !strings.Contains(err.Error(), `root.list: key "name" is declared more than once`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestNewParserValidatesDefaults(t *testing.T) {
+	schema := func(fieldType, def string) typed.YAMLObject {
+		return typed.YAMLObject(`types:
+- name: root
+  map:
+    fields:
+    - name: list
+      type:
+        list:
+          elementType:
+            namedType: port
+          elementRelationship: associative
+          keys:
+          - port
+          - protocol
+- name: port
+  map:
+    fields:
+    - name: port
+      type:
+        scalar: numeric
+    - name: protocol
+      type:
+` + fieldType + `
+      default: ` + def + `
+`)
+	}
+	table := []struct {
+		name      string
+		fieldType string
+		def       string
+		err       string
+	}{
+		{
+			name:      "valid-numeric",
+			fieldType: "        scalar: numeric",
+			def:       "6",
+		}, {
+			name:      "valid-string",
+			fieldType: "        scalar: string",
+			def:       `"TCP"`,
+		}, {
+			name:      "string-for-numeric",
+			fieldType: "        scalar: numeric",
+			def:       `"TCP"`,
+			err:       "port.protocol: invalid default value TCP: expected numeric",
+		}, {
+			name:      "too-long",
+			fieldType: "        scalar: string\n        maxLength: 3",
+			def:       `"SCTP"`,
+			err:       "port.protocol: invalid default value SCTP: string is too long",
+		},
+	}
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := typed.NewParser(schema(tt.fieldType, tt.def))
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("expected schema to be valid: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected schema to be rejected")
+			}
+			if !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expected error to contain %q, got: %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestFromYAMLCaseInsensitiveFields(t *testing.T) {
+	parser, err := typed.NewParser(`types:
+- name: root
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    - name: spec
+      type:
+        namedType: spec
+    - name: annotations
+      type:
+        map:
+          elementType:
+            scalar: string
+- name: spec
+  map:
+    fields:
+    - name: replicas
+      type:
+        scalar: numeric
+    - name: containers
+      type:
+        list:
+          elementType:
+            namedType: container
+          elementRelationship: associative
+          keys:
+          - containerName
+- name: container
+  map:
+    fields:
+    - name: containerName
+      type:
+        scalar: string
+`)
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+	pt := parser.Type("root")
+
+	object := typed.YAMLObject(`{"Name": "a", "SPEC": {"Replicas": 1, "containers": [{"containername": "c"}]}, "annotations": {"Key": "v"}}`)
+	if _, err := pt.FromYAML(object); err == nil {
+		t.Errorf("expected fields with a different case to be rejected by default")
+	}
+	tv, err := pt.FromYAML(object, typed.CaseInsensitiveFields)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The keys of maps without declared fields are kept verbatim.
+	expected, err := pt.FromYAML(`{"name": "a", "spec": {"replicas": 1, "containers": [{"containerName": "c"}]}, "annotations": {"Key": "v"}}`)
+	if err != nil {
+		t.Fatalf("failed to parse expected object: %v", err)
+	}
+	if !value.Equals(tv.AsValue(), expected.AsValue()) {
+		t.Errorf("expected\n%v\nbut got\n%v", value.ToString(expected.AsValue()), value.ToString(tv.AsValue()))
+	}
+	// Existing values can't be renamed.
+	if err := tv.Validate(typed.CaseInsensitiveFields); err == nil {
+		t.Errorf("expected Validate to reject CaseInsensitiveFields")
+	}
+
+	_, err = pt.FromYAML(`{"spec": {"containers": [{"containerName": "a", "CONTAINERNAME": "b"}]}}`, typed.CaseInsensitiveFields)
+	if err == nil {
+		t.Fatal("expected fields that only differ by case to be rejected")
+	}
+	if e := `.spec.containers[0].containerName: fields "CONTAINERNAME" and "containerName" only differ by case`; !strings.Contains(err.Error(), e) {
+		t.Errorf("expected error %q, got %v", e, err)
+	}
+}
+
+func TestNewParserAliases(t *testing.T) {
+	parser, err := typed.NewParser(`types:
+- name: root
+  map:
+    fields:
+    - name: labels
+      type: &stringMap
+        map:
+          elementType:
+            scalar: string
+    - name: annotations
+      type: *stringMap
+`)
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+	tv, err := parser.Type("root").FromYAML(`
+labels: &labels {app: web}
+annotations: *labels
+`)
+	if err != nil {
+		t.Fatalf("failed to parse object: %v", err)
+	}
+	expected, err := parser.Type("root").FromYAML(`{"labels": {"app": "web"}, "annotations": {"app": "web"}}`)
+	if err != nil {
+		t.Fatalf("failed to parse expected object: %v", err)
+	}
+	if !value.Equals(tv.AsValue(), expected.AsValue()) {
+		t.Errorf("expected\n%v\nbut got\n%v", value.ToString(expected.AsValue()), value.ToString(tv.AsValue()))
+	}
+
+	_, err = typed.NewParser(`types:
+- &root
+  name: root
+  map:
+    fields:
+    - name: self
+      type:
+        <<: *root
+`)
+	if err == nil || !strings.Contains(err.Error(), "value contains itself") {
+		t.Errorf("expected a recursive alias error, got %v", err)
+	}
+}
+
+func TestFromYAMLNormalize(t *testing.T) {
+	parser, err := typed.NewParser(`types:
+- name: root
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    - name: ports
+      type:
+        list:
+          elementType:
+            namedType: port
+          elementRelationship: associative
+          keys:
+          - protocol
+    - name: labels
+      type:
+        map:
+          elementType:
+            scalar: string
+            normalize: lower
+- name: port
+  map:
+    fields:
+    - name: protocol
+      type:
+        scalar: string
+        normalize: trim,upper
+`)
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+	pt := parser.Type("root")
+
+	tv, err := pt.FromYAML(`{"name": " Web ", "ports": [{"protocol": " tcp "}, {"protocol": "UDP"}], "labels": {"App": "Web"}}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected, err := pt.FromYAML(`{"name": " Web ", "ports": [{"protocol": "TCP"}, {"protocol": "UDP"}], "labels": {"App": "web"}}`)
+	if err != nil {
+		t.Fatalf("failed to parse expected object: %v", err)
+	}
+	if !value.Equals(tv.AsValue(), expected.AsValue()) {
+		t.Errorf("expected\n%v\nbut got\n%v", value.ToString(expected.AsValue()), value.ToString(tv.AsValue()))
+	}
+
+	// Items are keyed by their normalized value.
+	if _, err := pt.FromYAML(`{"ports": [{"protocol": "TCP"}, {"protocol": " tcp"}]}`); err == nil {
+		t.Errorf("expected items with the same normalized key to be duplicates")
+	}
+
+	tv, err = pt.FromUnstructured(map[string]interface{}{"ports": []interface{}{map[string]interface{}{"protocol": " TCP "}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `ports=[protocol="TCP"]`; value.ToString(tv.AsValue()) != expected {
+		t.Errorf("expected %v, got %v", expected, value.ToString(tv.AsValue()))
+	}
+
+	// Values that are already normalized aren't copied.
+	v := value.NewValueInterface(map[string]interface{}{"ports": []interface{}{map[string]interface{}{"protocol": "TCP"}}})
+	tv, err = typed.AsTyped(v, &parser.Schema, pt.TypeRef)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tv.AsValue() != v {
+		t.Errorf("expected the normalized value to be used as is")
+	}
+}
+
+func TestFromYAMLNormalizeMaxDepth(t *testing.T) {
+	parser, err := typed.NewParser(`types:
+- name: node
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+        normalize: lower
+    - name: child
+      type:
+        namedType: node
+`)
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+	pt := parser.Type("node")
+	pt.MaxDepth = 10
+	node := map[string]interface{}{"name": "A"}
+	for i := 0; i < 20; i++ {
+		node = map[string]interface{}{"child": node}
+	}
+	if _, err := pt.FromUnstructured(node); err == nil || !strings.Contains(err.Error(), "maximum nesting depth exceeded") {
+		t.Errorf("expected the depth to be exceeded, got %v", err)
+	}
+}
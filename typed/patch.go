@@ -0,0 +1,384 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/schema"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// ChangeKind is the kind of a change of a Patch.
+type ChangeKind string
+
+const (
+	// ChangeAdded means that the path didn't exist in the old object.
+	ChangeAdded ChangeKind = "added"
+	// ChangeRemoved means that the path doesn't exist in the new object.
+	ChangeRemoved ChangeKind = "removed"
+	// ChangeModified means that the value at the path changed.
+	ChangeModified ChangeKind = "modified"
+)
+
+// Change is the change of the value at a single path.
+type Change struct {
+	Path fieldpath.Path
+	Kind ChangeKind
+	// Old is the value before the change, nil for added paths.
+	Old value.Value
+	// New is the value after the change, nil for removed paths.
+	New value.Value
+}
+
+// Patch is the list of changes between two objects, as returned by Diff.
+// It can be serialized to JSON.
+type Patch struct {
+	Changes []Change
+}
+
+// Diff compares tv with rhs like Compare, and returns the changes along
+// with their values. Paths are the top-most changed paths: an added or
+// removed map, list or list item is a single change carrying the whole
+// value, and atomic maps and lists change as a whole.
+func (tv TypedValue) Diff(rhs *TypedValue) (*Patch, error) {
+	c, err := tv.Compare(rhs)
+	if err != nil {
+		return nil, err
+	}
+	patch := &Patch{}
+	var errs ValidationErrors
+	add := func(set *fieldpath.Set, kind ChangeKind) {
+		topMostPaths(set).Iterate(func(p fieldpath.Path) {
+			change := Change{Path: p.Copy(), Kind: kind}
+			var err error
+			if kind != ChangeAdded {
+				if change.Old, err = valueAtPath(tv.schema, tv.typeRef, tv.value, p); err != nil {
+					errs = append(errs, errorf("%v", err).WithPrefix(p.String())...)
+				}
+			}
+			if kind != ChangeRemoved {
+				if change.New, err = valueAtPath(rhs.schema, rhs.typeRef, rhs.value, p); err != nil {
+					errs = append(errs, errorf("%v", err).WithPrefix(p.String())...)
+				}
+			}
+			patch.Changes = append(patch.Changes, change)
+		})
+	}
+	add(c.Removed, ChangeRemoved)
+	add(c.Modified, ChangeModified)
+	add(c.Added, ChangeAdded)
+	if len(errs) != 0 {
+		return nil, errs
+	}
+	return patch, nil
+}
+
+// Apply returns the result of applying the changes of p to base. Old
+// values aren't checked against base, but removing a path that doesn't
+// exist is an error. Removals are applied last, in descending path order,
+// so that the indexes of the list items they remove refer to the items of
+// base rather than being shifted by earlier removals. The result is
+// validated.
+func (p *Patch) Apply(base *TypedValue) (*TypedValue, error) {
+	changes := make([]Change, 0, len(p.Changes))
+	var removals []Change
+	for _, change := range p.Changes {
+		if change.Kind == ChangeRemoved {
+			removals = append(removals, change)
+		} else {
+			changes = append(changes, change)
+		}
+	}
+	sort.SliceStable(removals, func(i, j int) bool {
+		return removals[i].Path.Compare(removals[j].Path) > 0
+	})
+	changes = append(changes, removals...)
+
+	out := base.value
+	for _, change := range changes {
+		if len(change.Path) == 0 {
+			return nil, fmt.Errorf("can't apply a change without a path")
+		}
+		var replacement interface{}
+		switch change.Kind {
+		case ChangeRemoved:
+		case ChangeAdded, ChangeModified:
+			if change.New == nil {
+				return nil, fmt.Errorf("%v: missing new value", change.Path)
+			}
+			replacement = change.New.Unstructured()
+		default:
+			return nil, fmt.Errorf("%v: unknown change kind %q", change.Path, change.Kind)
+		}
+		v, err := editAtPath(base.schema, base.typeRef, out, change.Path, replacement, change.Kind == ChangeRemoved)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", change.Path, err)
+		}
+		out = value.NewValueInterface(v)
+	}
+	return asTyped(out, base.schema, base.typeRef, base.maxDepth)
+}
+
+type changeJSON struct {
+	Path []string        `json:"path"`
+	Kind ChangeKind      `json:"kind"`
+	Old  json.RawMessage `json:"old,omitempty"`
+	New  json.RawMessage `json:"new,omitempty"`
+}
+
+type patchJSON struct {
+	Changes []changeJSON `json:"changes"`
+}
+
+// MarshalJSON serializes the patch. Paths are serialized as lists of path
+// elements, formatted like in serialized field sets.
+func (p Patch) MarshalJSON() ([]byte, error) {
+	out := patchJSON{Changes: make([]changeJSON, 0, len(p.Changes))}
+	for _, change := range p.Changes {
+		c := changeJSON{Kind: change.Kind, Path: make([]string, 0, len(change.Path))}
+		for _, pe := range change.Path {
+			s, err := fieldpath.SerializePathElement(pe)
+			if err != nil {
+				return nil, err
+			}
+			c.Path = append(c.Path, s)
+		}
+		var err error
+		if change.Old != nil {
+			if c.Old, err = value.ToJSON(change.Old); err != nil {
+				return nil, err
+			}
+		}
+		if change.New != nil {
+			if c.New, err = value.ToJSON(change.New); err != nil {
+				return nil, err
+			}
+		}
+		out.Changes = append(out.Changes, c)
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON deserializes a patch serialized by MarshalJSON.
+func (p *Patch) UnmarshalJSON(data []byte) error {
+	var in patchJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	p.Changes = make([]Change, 0, len(in.Changes))
+	for _, c := range in.Changes {
+		change := Change{Kind: c.Kind, Path: make(fieldpath.Path, 0, len(c.Path))}
+		for _, s := range c.Path {
+			pe, err := fieldpath.DeserializePathElement(s)
+			if err != nil {
+				return err
+			}
+			change.Path = append(change.Path, pe)
+		}
+		var err error
+		if len(c.Old) != 0 {
+			if change.Old, err = value.FromJSON(c.Old); err != nil {
+				return err
+			}
+		}
+		if len(c.New) != 0 {
+			if change.New, err = value.FromJSON(c.New); err != nil {
+				return err
+			}
+		}
+		p.Changes = append(p.Changes, change)
+	}
+	return nil
+}
+
+// topMostPaths returns the paths of set that have no prefix in set.
+func topMostPaths(set *fieldpath.Set) *fieldpath.Set {
+	out := fieldpath.NewSet()
+	set.Iterate(func(p fieldpath.Path) {
+		for i := 1; i < len(p); i++ {
+			if set.Has(p[:i]) {
+				return
+			}
+		}
+		if len(p) != 0 {
+			out.Insert(p.Copy())
+		}
+	})
+	return out
+}
+
+// valueAtPath returns the value at path within v of type tr.
+func valueAtPath(s *schema.Schema, tr schema.TypeRef, v value.Value, path fieldpath.Path) (value.Value, error) {
+	for _, pe := range path {
+		a, ok := s.Resolve(tr)
+		if !ok {
+			return nil, fmt.Errorf("schema error: no type found matching: %v", tr)
+		}
+		a = deduceAtom(a, v)
+		switch {
+		case pe.FieldName != nil:
+			if v == nil || !v.IsMap() || a.Map == nil {
+				return nil, fmt.Errorf("expected a map for field %q", *pe.FieldName)
+			}
+			child, ok := v.AsMap().Get(*pe.FieldName)
+			if !ok {
+				return nil, fmt.Errorf("field %q not found", *pe.FieldName)
+			}
+			tr = a.Map.ElementType
+			if sf, ok := a.Map.FindField(*pe.FieldName); ok {
+				tr = sf.Type
+			}
+			v = child
+		case pe.Index != nil:
+			if v == nil || !v.IsList() || a.List == nil {
+				return nil, fmt.Errorf("expected a list for %v", pe)
+			}
+			l := v.AsList()
+			if *pe.Index < 0 || *pe.Index >= l.Length() {
+				return nil, fmt.Errorf("index %v out of range", *pe.Index)
+			}
+			v, tr = l.At(*pe.Index), a.List.ElementType
+		default:
+			if v == nil || !v.IsList() || a.List == nil {
+				return nil, fmt.Errorf("expected a list for %v", pe)
+			}
+			i, ok := findListItem(s, a.List, v.AsList(), pe)
+			if !ok {
+				return nil, fmt.Errorf("item %v not found", pe)
+			}
+			v, tr = v.AsList().At(i), a.List.ElementType
+		}
+	}
+	return v, nil
+}
+
+// findListItem returns the index of the item of l that pe refers to.
+func findListItem(s *schema.Schema, t *schema.List, l value.List, pe fieldpath.PathElement) (int, bool) {
+	for i := 0; i < l.Length(); i++ {
+		if itemPE, err := listItemToPathElement(value.HeapAllocator, s, t, l.At(i)); err == nil && itemPE.Equals(pe) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// editAtPath returns a copy of v where the value at path is replaced by
+// replacement, or removed. Maps and lists missing along the path are
+// created, except when removing, which fails if path doesn't exist. Only
+// the maps and lists along the path are copied.
+func editAtPath(s *schema.Schema, tr schema.TypeRef, v value.Value, path fieldpath.Path, replacement interface{}, remove bool) (interface{}, error) {
+	a, ok := s.Resolve(tr)
+	if !ok {
+		return nil, fmt.Errorf("schema error: no type found matching: %v", tr)
+	}
+	a = deduceAtom(a, v)
+	pe := path[0]
+	if pe.FieldName != nil {
+		if a.Map == nil {
+			return nil, fmt.Errorf("expected a map for field %q", *pe.FieldName)
+		}
+		out := map[string]interface{}{}
+		var child value.Value
+		if v != nil && v.IsMap() {
+			v.AsMap().Iterate(func(key string, val value.Value) bool {
+				out[key] = val.Unstructured()
+				if key == *pe.FieldName {
+					child = value.NewValueInterface(out[key])
+				}
+				return true
+			})
+		}
+		switch {
+		case child == nil && remove:
+			return nil, fmt.Errorf("field %q not found", *pe.FieldName)
+		case len(path) == 1 && remove:
+			delete(out, *pe.FieldName)
+		case len(path) == 1:
+			out[*pe.FieldName] = replacement
+		default:
+			fieldType := a.Map.ElementType
+			if sf, ok := a.Map.FindField(*pe.FieldName); ok {
+				fieldType = sf.Type
+			}
+			c, err := editAtPath(s, fieldType, child, path[1:], replacement, remove)
+			if err != nil {
+				return nil, err
+			}
+			out[*pe.FieldName] = c
+		}
+		return out, nil
+	}
+
+	if a.List == nil {
+		return nil, fmt.Errorf("expected a list for %v", pe)
+	}
+	var items []interface{}
+	var l value.List
+	if v != nil && v.IsList() {
+		l = v.AsList()
+		for i := 0; i < l.Length(); i++ {
+			items = append(items, l.At(i).Unstructured())
+		}
+	}
+	i, found := -1, false
+	if pe.Index != nil {
+		i = *pe.Index
+		found = i >= 0 && i < len(items)
+		if !found && i != len(items) {
+			return nil, fmt.Errorf("index %v out of range", i)
+		}
+	} else if l != nil {
+		i, found = findListItem(s, a.List, l, pe)
+	}
+	switch {
+	case !found && remove && pe.Index != nil:
+		return nil, fmt.Errorf("index %v out of range", i)
+	case !found && remove:
+		return nil, fmt.Errorf("item %v not found", pe)
+	case len(path) == 1 && remove:
+		return append(items[:i], items[i+1:]...), nil
+	case len(path) == 1 && found:
+		items[i] = replacement
+		return items, nil
+	case len(path) == 1:
+		return append(items, replacement), nil
+	}
+	var child value.Value
+	if found {
+		child = value.NewValueInterface(items[i])
+	} else if pe.Key != nil {
+		// Start the new item with its key fields.
+		item := map[string]interface{}{}
+		for _, f := range *pe.Key {
+			item[f.Name] = f.Value.Unstructured()
+		}
+		child = value.NewValueInterface(item)
+	}
+	c, err := editAtPath(s, a.List.ElementType, child, path[1:], replacement, remove)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		items[i] = c
+		return items, nil
+	}
+	return append(items, c), nil
+}
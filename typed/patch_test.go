@@ -0,0 +1,257 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+var patchParser = func() typed.ParseableType {
+	parser, err := typed.NewParser(`types:
+- name: root
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    - name: labels
+      type:
+        map:
+          elementType:
+            scalar: string
+    - name: args
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+    - name: tags
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: associative
+    - name: containers
+      type:
+        list:
+          elementType:
+            namedType: container
+          elementRelationship: associative
+          keys:
+          - name
+- name: container
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    - name: image
+      type:
+        scalar: string
+    - name: env
+      type:
+        map:
+          elementType:
+            scalar: string
+`)
+	if err != nil {
+		panic(err)
+	}
+	return parser.Type("root")
+}()
+
+func TestDiff(t *testing.T) {
+	lhs, err := patchParser.FromYAML(`{
+  "name": "a",
+  "args": ["x", "y"],
+  "tags": ["t1"],
+  "containers": [{"name": "c1", "image": "i1"}, {"name": "c2", "image": "i2", "env": {"k": "v"}}]
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rhs, err := patchParser.FromYAML(`{
+  "name": "b",
+  "labels": {"app": "web"},
+  "args": ["x", "z"],
+  "tags": ["t1", "t2"],
+  "containers": [{"name": "c1", "image": "i1b"}, {"name": "c3", "image": "i3"}]
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	patch, err := lhs.Diff(rhs)
+	if err != nil {
+		t.Fatalf("failed to diff: %v", err)
+	}
+
+	v := func(s string) value.Value {
+		v, err := value.FromJSON([]byte(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	expected := map[string]typed.Change{
+		`.name`: {Kind: typed.ChangeModified, Old: v(`"a"`), New: v(`"b"`)},
+		// Atomic lists change as a whole.
+		`.args`: {Kind: typed.ChangeModified, Old: v(`["x","y"]`), New: v(`["x","z"]`)},
+		// Added maps and list items are a single change.
+		`.labels`:                      {Kind: typed.ChangeAdded, New: v(`{"app":"web"}`)},
+		`.tags[="t2"]`:                 {Kind: typed.ChangeAdded, New: v(`"t2"`)},
+		`.containers[name="c1"].image`: {Kind: typed.ChangeModified, Old: v(`"i1"`), New: v(`"i1b"`)},
+		`.containers[name="c2"]`:       {Kind: typed.ChangeRemoved, Old: v(`{"name":"c2","image":"i2","env":{"k":"v"}}`)},
+		`.containers[name="c3"]`:       {Kind: typed.ChangeAdded, New: v(`{"name":"c3","image":"i3"}`)},
+	}
+	if len(patch.Changes) != len(expected) {
+		t.Errorf("expected %v changes, got %v: %v", len(expected), len(patch.Changes), patch.Changes)
+	}
+	equal := func(lhs, rhs value.Value) bool {
+		if lhs == nil || rhs == nil {
+			return lhs == nil && rhs == nil
+		}
+		return value.Equals(lhs, rhs)
+	}
+	for _, change := range patch.Changes {
+		e, ok := expected[change.Path.String()]
+		if !ok {
+			t.Errorf("unexpected change at %v", change.Path)
+			continue
+		}
+		if change.Kind != e.Kind || !equal(change.Old, e.Old) || !equal(change.New, e.New) {
+			t.Errorf("expected change at %v to be %v %v -> %v, got %v %v -> %v", change.Path, e.Kind, e.Old, e.New, change.Kind, change.Old, change.New)
+		}
+	}
+}
+
+func TestPatchRoundTrip(t *testing.T) {
+	table := []struct {
+		name string
+		lhs  typed.YAMLObject
+		rhs  typed.YAMLObject
+	}{
+		{
+			name: "same",
+			lhs:  `{"name": "a"}`,
+			rhs:  `{"name": "a"}`,
+		}, {
+			name: "fields",
+			lhs:  `{"name": "a", "labels": {"app": "web", "tier": "front"}}`,
+			rhs:  `{"labels": {"app": "db"}, "args": ["x"]}`,
+		}, {
+			name: "empty-map",
+			lhs:  `{"labels": {"app": "web"}}`,
+			rhs:  `{"labels": {}}`,
+		}, {
+			name: "list-items",
+			lhs:  `{"tags": ["a", "b"], "containers": [{"name": "c1", "env": {"k": "v"}}, {"name": "c2"}]}`,
+			rhs:  `{"tags": ["b", "c"], "containers": [{"name": "c1", "image": "i1", "env": {"k": "w"}}, {"name": "c3"}]}`,
+		}, {
+			name: "from-empty",
+			lhs:  `{}`,
+			rhs:  `{"name": "a", "containers": [{"name": "c1", "env": {"k": "v"}}]}`,
+		}, {
+			name: "to-empty",
+			lhs:  `{"name": "a", "containers": [{"name": "c1", "env": {"k": "v"}}]}`,
+			rhs:  `{}`,
+		},
+	}
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			lhs, err := patchParser.FromYAML(tt.lhs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rhs, err := patchParser.FromYAML(tt.rhs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			patch, err := lhs.Diff(rhs)
+			if err != nil {
+				t.Fatalf("failed to diff: %v", err)
+			}
+			data, err := json.Marshal(patch)
+			if err != nil {
+				t.Fatalf("failed to serialize patch: %v", err)
+			}
+			var decoded typed.Patch
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("failed to deserialize patch: %v\n%s", err, data)
+			}
+			got, err := decoded.Apply(lhs)
+			if err != nil {
+				t.Fatalf("failed to apply patch: %v\n%s", err, data)
+			}
+			if !value.Equals(got.AsValue(), rhs.AsValue()) {
+				t.Errorf("expected\n%v\nbut got\n%v\npatch: %s", value.ToString(rhs.AsValue()), value.ToString(got.AsValue()), data)
+			}
+		})
+	}
+}
+
+func TestPatchApplyErrors(t *testing.T) {
+	base, err := patchParser.FromYAML(`{"name": "a"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, patch := range []typed.Patch{
+		{Changes: []typed.Change{{Path: fieldpath.MakePathOrDie("name"), Kind: "replaced", New: value.NewValueInterface("b")}}},
+		{Changes: []typed.Change{{Path: fieldpath.MakePathOrDie("name"), Kind: typed.ChangeModified}}},
+		{Changes: []typed.Change{{Path: fieldpath.MakePathOrDie("name"), Kind: typed.ChangeModified, New: value.NewValueInterface(1)}}},
+		{Changes: []typed.Change{{Path: fieldpath.MakePathOrDie("name", "nested"), Kind: typed.ChangeAdded, New: value.NewValueInterface("b")}}},
+		// Missing removal targets.
+		{Changes: []typed.Change{{Path: fieldpath.MakePathOrDie("labels"), Kind: typed.ChangeRemoved}}},
+		{Changes: []typed.Change{{Path: fieldpath.MakePathOrDie("labels", "app"), Kind: typed.ChangeRemoved}}},
+		{Changes: []typed.Change{{Path: fieldpath.MakePathOrDie("args", 0), Kind: typed.ChangeRemoved}}},
+		{Changes: []typed.Change{{Path: fieldpath.MakePathOrDie("containers", fieldpath.KeyByFields("name", "c1")), Kind: typed.ChangeRemoved}}},
+	} {
+		if _, err := patch.Apply(base); err == nil {
+			t.Errorf("expected error applying %v", patch.Changes)
+		}
+	}
+}
+
+func TestPatchApplyIndexRemovals(t *testing.T) {
+	base, err := patchParser.FromYAML(`{"args": ["a", "b", "c", "d"]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The indexes refer to the items of base, whatever the order of the
+	// removals.
+	patch := typed.Patch{Changes: []typed.Change{
+		{Path: fieldpath.MakePathOrDie("args", 1), Kind: typed.ChangeRemoved},
+		{Path: fieldpath.MakePathOrDie("args", 2), Kind: typed.ChangeRemoved},
+	}}
+	got, err := patch.Apply(base)
+	if err != nil {
+		t.Fatalf("failed to apply patch: %v", err)
+	}
+	expected, err := patchParser.FromYAML(`{"args": ["a", "d"]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !value.Equals(got.AsValue(), expected.AsValue()) {
+		t.Errorf("expected\n%v\nbut got\n%v", value.ToString(expected.AsValue()), value.ToString(got.AsValue()))
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed
+
+import (
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/schema"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+	yamlv3 "sigs.k8s.io/yaml/goyaml.v3"
+)
+
+// yamlPosition is the position of a node in a YAML document.
+type yamlPosition struct {
+	line, column int
+}
+
+// withYAMLPositions sets the position in the YAML document object of the
+// values that errs are about, where it can be found. v is the value read
+// from object, and tr its type.
+func withYAMLPositions(errs ValidationErrors, object YAMLObject, s *schema.Schema, tr schema.TypeRef, v value.Value) ValidationErrors {
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal([]byte(object), &doc); err != nil || len(doc.Content) == 0 {
+		return errs
+	}
+	w := positionWalker{schema: s, positions: map[string]yamlPosition{}}
+	w.walk(doc.Content[0], v, tr, "")
+	for i := range errs {
+		if pos, ok := w.positions[errs[i].Path]; ok {
+			errs[i].Line, errs[i].Column = pos.line, pos.column
+		}
+	}
+	return errs
+}
+
+// positionWalker records the positions of the nodes of a YAML document by
+// the path of their values, as reported in validation errors.
+type positionWalker struct {
+	schema    *schema.Schema
+	positions map[string]yamlPosition
+}
+
+func (w *positionWalker) walk(n *yamlv3.Node, v value.Value, tr schema.TypeRef, path string) {
+	if _, ok := w.positions[path]; !ok {
+		w.positions[path] = yamlPosition{line: n.Line, column: n.Column}
+	}
+	if n.Kind == yamlv3.AliasNode {
+		n = n.Alias
+	}
+	a, ok := w.schema.Resolve(tr)
+	if !ok || v == nil {
+		return
+	}
+	a = deduceAtom(a, v)
+	switch {
+	case n.Kind == yamlv3.MappingNode && a.Map != nil && v.IsMap():
+		m := v.AsMap()
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i]
+			if key.Kind == yamlv3.AliasNode {
+				key = key.Alias
+			}
+			if key.Kind != yamlv3.ScalarNode || key.ShortTag() == "!!merge" {
+				continue
+			}
+			name := key.Value
+			child, ok := m.Get(name)
+			if !ok {
+				continue
+			}
+			fieldType := a.Map.ElementType
+			if sf, ok := a.Map.FindField(name); ok {
+				fieldType = sf.Type
+			}
+			w.walk(n.Content[i+1], child, fieldType, path+fieldpath.PathElement{FieldName: &name}.String())
+		}
+	case n.Kind == yamlv3.SequenceNode && a.List != nil && v.IsList():
+		l := v.AsList()
+		for i, item := range n.Content {
+			if i >= l.Length() {
+				break
+			}
+			child := l.At(i)
+			pe := fieldpath.PathElement{Index: &i}
+			if a.List.ElementRelationship == schema.Associative {
+				var err error
+				if pe, err = listItemToPathElement(value.HeapAllocator, w.schema, a.List, child); err != nil {
+					continue
+				}
+			}
+			w.walk(item, child, a.List.ElementType, path+pe.String())
+		}
+	}
+}
@@ -22,6 +22,7 @@ import (
 
 	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
 	"sigs.k8s.io/structured-merge-diff/v4/schema"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
 )
 
 var fmPool = sync.Pool{
@@ -116,6 +117,10 @@ func (v *reconcileWithSchemaWalker) finishDescent(v2 *reconcileWithSchemaWalker)
 // Supports:
 // - changing types from atomic to granular
 // - changing types from granular to atomic
+//
+// When a list owned atomically is now a keyed associative list, every
+// element of the live list must have the new keys; an error naming each
+// element that can't be keyed is returned otherwise.
 func ReconcileFieldSetWithSchema(fieldset *fieldpath.Set, tv *TypedValue) (*fieldpath.Set, error) {
 	v := fmPool.Get().(*reconcileWithSchemaWalker)
 	v.fieldSet = fieldset
@@ -191,8 +196,31 @@ func (v *reconcileWithSchemaWalker) doList(t *schema.List) (errs ValidationError
 		v.toAdd = fieldpath.NewSet(v.path)    // add the root of the atomic
 		return errs
 	}
+	// lists owned atomically that are now keyed associative lists need
+	// keys for all their elements.
+	if v.isAtomic && t.ElementRelationship == schema.Associative && len(t.Keys) > 0 {
+		errs = append(errs, v.validateListKeys(t)...)
+	}
 	if v.fieldSet != nil {
-		errs = v.visitListItems(t, v.fieldSet)
+		errs = append(errs, v.visitListItems(t, v.fieldSet)...)
+	}
+	return errs
+}
+
+// validateListKeys reports the elements of the live list at the current
+// path that can't be keyed with the keys of t.
+func (v *reconcileWithSchemaWalker) validateListKeys(t *schema.List) (errs ValidationErrors) {
+	_, live, ok := atomAtPath(v.value.schema, v.value.typeRef, v.value.value, v.path)
+	if !ok || !live.IsList() {
+		return errs
+	}
+	list := live.AsList()
+	for i := 0; i < list.Length(); i++ {
+		if _, err := keyedAssociativeListItemToPathElement(value.HeapAllocator, v.schema, t, list.At(i)); err != nil {
+			i := i
+			path := append(v.path.Copy(), fieldpath.PathElement{Index: &i})
+			errs = append(errs, errorf("element can't be keyed by %v: %v", t.Keys, err).WithPath(path.String())...)
+		}
 	}
 	return errs
 }
@@ -18,10 +18,12 @@ package typed_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
 	"sigs.k8s.io/structured-merge-diff/v4/typed"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
 )
 
 type reconcileTestCase struct {
@@ -468,3 +470,49 @@ func (tt reconcileTestCase) testReconcileCase(t *testing.T) {
 		t.Errorf("expected fieldset:\n%s\n:but got\n:%s", tt.fixedFields.String(), fixed.String())
 	}
 }
+
+func TestReconcileFieldSetWithSchemaMissingKeys(t *testing.T) {
+	// containers used to be an atomic list, it is now keyed by name.
+	parser, err := typed.NewParser(`types:
+- name: v1
+  map:
+    fields:
+    - name: containers
+      type:
+        list:
+          elementType:
+            map:
+              fields:
+              - name: name
+                type:
+                  scalar: string
+              - name: image
+                type:
+                  scalar: string
+          elementRelationship: associative
+          keys:
+          - name
+`)
+	if err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	pt := parser.Type("v1")
+	liveObject := typed.AsTypedUnvalidated(value.NewValueInterface(map[string]interface{}{
+		"containers": []interface{}{
+			map[string]interface{}{"name": "a", "image": "a:1"},
+			map[string]interface{}{"image": "b:1"},
+		},
+	}), pt.Schema, pt.TypeRef)
+
+	_, err = typed.ReconcileFieldSetWithSchema(_NS(_P("containers")), liveObject)
+	if err == nil {
+		t.Fatal("expected an error for the element without keys")
+	}
+	expected := `.containers[1]: element can't be keyed by [name]: associative list with keys has an element that omits key field "name"`
+	if !strings.Contains(err.Error(), expected) {
+		t.Errorf("expected error to contain %q, got %q", expected, err.Error())
+	}
+	if strings.Contains(err.Error(), ".containers[0]") {
+		t.Errorf("expected no error for the element with keys, got %q", err.Error())
+	}
+}
@@ -26,6 +26,12 @@ type removingWalker struct {
 	toRemove      *fieldpath.Set
 	allocator     value.Allocator
 	shouldExtract bool
+	// depth is the number of nested maps and lists left to walk.
+	depth int
+
+	// If set, notified of the nodes walked, whose path is path.
+	tracer WalkTracer
+	path   fieldpath.Path
 }
 
 // removeItemsWithSchema will walk the given value and look for items from the toRemove set.
@@ -33,19 +39,81 @@ type removingWalker struct {
 // of the input value with either:
 // 1. only the items in the toRemove set (when shouldExtract is true) or
 // 2. the items from the toRemove set removed from the value (when shouldExtract is false).
-func removeItemsWithSchema(val value.Value, toRemove *fieldpath.Set, schema *schema.Schema, typeRef schema.TypeRef, shouldExtract bool) value.Value {
+// Values nested more than depth levels deep are returned as is, and
+// reported as errors.
+func removeItemsWithSchema(val value.Value, toRemove *fieldpath.Set, schema *schema.Schema, typeRef schema.TypeRef, shouldExtract bool, depth int) (value.Value, ValidationErrors) {
+	return removeItemsWithTracer(val, toRemove, schema, typeRef, shouldExtract, depth, nil)
+}
+
+// removeItemsWithTracer is like removeItemsWithSchema, but notifies tracer, if
+// set, of the nodes walked.
+func removeItemsWithTracer(val value.Value, toRemove *fieldpath.Set, schema *schema.Schema, typeRef schema.TypeRef, shouldExtract bool, depth int, tracer WalkTracer) (value.Value, ValidationErrors) {
 	w := &removingWalker{
 		value:         val,
 		schema:        schema,
 		toRemove:      toRemove,
 		allocator:     value.NewFreelistAllocator(),
 		shouldExtract: shouldExtract,
+		depth:         depth,
+		tracer:        tracer,
+	}
+	return w.walk(typeRef)
+}
+
+// walk returns w.value, of type typeRef, with the items of w.toRemove
+// removed or extracted.
+func (w *removingWalker) walk(typeRef schema.TypeRef) (value.Value, ValidationErrors) {
+	if w.depth < 0 {
+		return w.value, errorf("maximum nesting depth exceeded")
+	}
+	if w.tracer != nil {
+		w.tracer.Enter(w.path, nil, w.value)
+	}
+	errs := resolveSchema(w.schema, typeRef, w.value, w)
+	out := value.NewValueInterface(w.out)
+	if w.tracer != nil {
+		var traced value.Value
+		if w.out != nil {
+			traced = out
+		}
+		w.tracer.Leave(w.path, traced)
+	}
+	return out, errs
+}
+
+// walkChild returns the child pe of w.value, val of type typeRef, with the
+// items of toRemove, relative to the child, removed or extracted.
+func (w *removingWalker) walkChild(pe fieldpath.PathElement, val value.Value, toRemove *fieldpath.Set, typeRef schema.TypeRef) (value.Value, ValidationErrors) {
+	w2 := *w
+	w2.value = val
+	w2.out = nil
+	w2.toRemove = toRemove
+	w2.depth = w.depth - 1
+	if w.tracer != nil {
+		w2.path = childPath(w.path, pe)
+	}
+	out, errs := w2.walk(typeRef)
+	return out, errs.WithPrefix(pe.String())
+}
+
+// traceRule notifies the tracer, if any, of the rule that decides the value
+// being walked.
+func (w *removingWalker) traceRule(rule WalkRule) {
+	if w.tracer != nil {
+		w.tracer.Rule(w.path, rule)
+	}
+}
+
+// traceRemoved notifies the tracer, if any, of the removal of the child pe,
+// val.
+func (w *removingWalker) traceRemoved(pe fieldpath.PathElement, val value.Value) {
+	if w.tracer != nil {
+		traceNode(w.tracer, childPath(w.path, pe), nil, val, WalkRuleRemove, nil)
 	}
-	resolveSchema(schema, typeRef, val, w)
-	return value.NewValueInterface(w.out)
 }
 
 func (w *removingWalker) doScalar(t *schema.Scalar) ValidationErrors {
+	w.traceRule(WalkRuleScalar)
 	w.out = w.value.Unstructured()
 	return nil
 }
@@ -64,6 +132,7 @@ func (w *removingWalker) doList(t *schema.List) (errs ValidationErrors) {
 	// atomic lists should return everything in the case of extract
 	// and nothing in the case of remove (!w.shouldExtract)
 	if t.ElementRelationship == schema.Atomic {
+		w.traceRule(WalkRuleAtomic)
 		if w.shouldExtract {
 			w.out = w.value.Unstructured()
 		}
@@ -82,13 +151,18 @@ func (w *removingWalker) doList(t *schema.List) (errs ValidationErrors) {
 		// but ignore them when we are removing (i.e. !w.shouldExtract)
 		if w.toRemove.Has(path) {
 			if w.shouldExtract {
-				newItems = append(newItems, removeItemsWithSchema(item, w.toRemove, w.schema, t.ElementType, w.shouldExtract).Unstructured())
+				extracted, itemErrs := w.walkChild(pe, item, w.toRemove, t.ElementType)
+				errs = append(errs, itemErrs...)
+				newItems = append(newItems, extracted.Unstructured())
 			} else {
+				w.traceRemoved(pe, item)
 				continue
 			}
 		}
 		if subset := w.toRemove.WithPrefix(pe); !subset.Empty() {
-			item = removeItemsWithSchema(item, subset, w.schema, t.ElementType, w.shouldExtract)
+			var itemErrs ValidationErrors
+			item, itemErrs = w.walkChild(pe, item, subset, t.ElementType)
+			errs = append(errs, itemErrs...)
 		} else {
 			// don't save items not on the path when we shouldExtract.
 			if w.shouldExtract {
@@ -100,10 +174,10 @@ func (w *removingWalker) doList(t *schema.List) (errs ValidationErrors) {
 	if len(newItems) > 0 {
 		w.out = newItems
 	}
-	return nil
+	return errs
 }
 
-func (w *removingWalker) doMap(t *schema.Map) ValidationErrors {
+func (w *removingWalker) doMap(t *schema.Map) (errs ValidationErrors) {
 	if !w.value.IsMap() {
 		return nil
 	}
@@ -119,6 +193,7 @@ func (w *removingWalker) doMap(t *schema.Map) ValidationErrors {
 	// atomic maps should return everything in the case of extract
 	// and nothing in the case of remove (!w.shouldExtract)
 	if t.ElementRelationship == schema.Atomic {
+		w.traceRule(WalkRuleAtomic)
 		if w.shouldExtract {
 			w.out = w.value.Unstructured()
 		}
@@ -142,13 +217,18 @@ func (w *removingWalker) doMap(t *schema.Map) ValidationErrors {
 		// but ignore them when we are removing (i.e. !w.shouldExtract)
 		if w.toRemove.Has(path) {
 			if w.shouldExtract {
-				newMap[k] = removeItemsWithSchema(val, w.toRemove, w.schema, fieldType, w.shouldExtract).Unstructured()
-
+				extracted, valErrs := w.walkChild(pe, val, w.toRemove, fieldType)
+				errs = append(errs, valErrs...)
+				newMap[k] = extracted.Unstructured()
+			} else {
+				w.traceRemoved(pe, val)
 			}
 			return true
 		}
 		if subset := w.toRemove.WithPrefix(pe); !subset.Empty() {
-			val = removeItemsWithSchema(val, subset, w.schema, fieldType, w.shouldExtract)
+			var valErrs ValidationErrors
+			val, valErrs = w.walkChild(pe, val, subset, fieldType)
+			errs = append(errs, valErrs...)
 		} else {
 			// don't save values not on the path when we shouldExtract.
 			if w.shouldExtract {
@@ -161,5 +241,5 @@ func (w *removingWalker) doMap(t *schema.Map) ValidationErrors {
 	if len(newMap) > 0 {
 		w.out = newMap
 	}
-	return nil
+	return errs
 }
@@ -724,6 +724,22 @@ func (tt removeTestCase) test(t *testing.T) {
 				}
 
 			}
+
+			// test SplitItems
+			splitEx, splitRm, err := tv.SplitItems(quadruplet.set)
+			if err != nil {
+				t.Fatalf("SplitItems failed: %v", err)
+			}
+			if exGot := tv.ExtractItems(quadruplet.set); !value.Equals(splitEx.AsValue(), exGot.AsValue()) {
+				t.Errorf("SplitItems expected extracted\n%v\nbut got\n%v\n",
+					value.ToString(exGot.AsValue()), value.ToString(splitEx.AsValue()),
+				)
+			}
+			if rmGot := tv.RemoveItems(quadruplet.set); !value.Equals(splitRm.AsValue(), rmGot.AsValue()) {
+				t.Errorf("SplitItems expected remainder\n%v\nbut got\n%v\n",
+					value.ToString(rmGot.AsValue()), value.ToString(splitRm.AsValue()),
+				)
+			}
 		})
 	}
 }
@@ -998,3 +1014,35 @@ func TestExtractWithKeys(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitItemsRecombines(t *testing.T) {
+	parser, err := typed.NewParser(typed.YAMLObject(nestedTypesSchema))
+	if err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	tv, err := parser.Type("type").FromYAML(`{"mapOfMaps": {"b":{"a":"x","c":"z"}, "d":{"e":"y"}}, "mapOfLists": {"b":["a","c"]}}`)
+	if err != nil {
+		t.Fatalf("unable to parser/validate object: %v", err)
+	}
+	extracted, remainder, err := tv.SplitItems(_NS(
+		_P("mapOfMaps", "b", "a"),
+		_P("mapOfMaps", "d", "e"),
+	))
+	if err != nil {
+		t.Fatalf("SplitItems failed: %v", err)
+	}
+	expected, err := parser.Type("type").FromYAML(`{"mapOfMaps": {"b":{"a":"x"}, "d":{"e":"y"}}}`)
+	if err != nil {
+		t.Fatalf("unable to parser/validate expected object: %v", err)
+	}
+	if !value.Equals(extracted.AsValue(), expected.AsValue()) {
+		t.Errorf("expected extracted\n%v\nbut got\n%v\n", value.ToString(expected.AsValue()), value.ToString(extracted.AsValue()))
+	}
+	merged, err := remainder.Merge(extracted)
+	if err != nil {
+		t.Fatalf("unable to merge the extracted value into the remainder: %v", err)
+	}
+	if !value.Equals(merged.AsValue(), tv.AsValue()) {
+		t.Errorf("expected recombined\n%v\nbut got\n%v\n", value.ToString(tv.AsValue()), value.ToString(merged.AsValue()))
+	}
+}
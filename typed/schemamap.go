@@ -0,0 +1,126 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed
+
+import (
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/schema"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// SchemaMap returns the atom of the schema that applies to each field and
+// list item of the object, including the object itself at the empty path,
+// keyed by the string representation of their path. The atoms are resolved
+// for the value at each path, e.g. a deduced type only has the member
+// matching the kind of the value, which tells whether each of them is
+// merged as a scalar, an atomic or granular map, or an atomic, set or
+// associative list. Items of non-associative lists are indexed by position.
+func (tv TypedValue) SchemaMap() (map[string]schema.Atom, error) {
+	w := &schemaMapWalker{
+		value:     tv.value,
+		schema:    tv.schema,
+		typeRef:   tv.typeRef,
+		atoms:     map[string]schema.Atom{},
+		maxDepth:  tv.depthLimit(),
+		allocator: value.NewFreelistAllocator(),
+	}
+	if errs := w.walk(); len(errs) != 0 {
+		return nil, errs
+	}
+	return w.atoms, nil
+}
+
+type schemaMapWalker struct {
+	value   value.Value
+	schema  *schema.Schema
+	typeRef schema.TypeRef
+	path    fieldpath.Path
+
+	// The atoms found so far, keyed by path.
+	atoms map[string]schema.Atom
+
+	// The maximum length of path.
+	maxDepth int
+
+	allocator value.Allocator
+}
+
+func (w *schemaMapWalker) walk() ValidationErrors {
+	if len(w.path) > w.maxDepth {
+		return errorf("maximum nesting depth exceeded")
+	}
+	a, ok := w.schema.Resolve(w.typeRef)
+	if !ok {
+		typeName := "inlined type"
+		if w.typeRef.NamedType != nil {
+			typeName = *w.typeRef.NamedType
+		}
+		return errorf("schema error: no type found matching: %v", typeName)
+	}
+	a = deduceAtom(a, w.value)
+	w.atoms[w.path.String()] = a
+	return handleAtom(a, w.typeRef, w)
+}
+
+func (w *schemaMapWalker) descend(pe fieldpath.PathElement, tr schema.TypeRef, v value.Value) ValidationErrors {
+	w2 := *w
+	w2.path = append(w.path, pe)
+	w2.typeRef = tr
+	w2.value = v
+	return w2.walk().WithPrefix(pe.String())
+}
+
+func (w *schemaMapWalker) doScalar(t *schema.Scalar) ValidationErrors {
+	return nil
+}
+
+func (w *schemaMapWalker) doList(t *schema.List) (errs ValidationErrors) {
+	list, _ := listValue(w.allocator, w.value)
+	if list == nil {
+		return nil
+	}
+	defer w.allocator.Free(list)
+	for i := 0; i < list.Length(); i++ {
+		child := list.AtUsing(w.allocator, i)
+		pe, err := listItemPathElement(w.allocator, w.schema, t, i, child)
+		if err != nil {
+			errs = append(errs, errorf("element %v: %v", i, err.Error())...)
+			w.allocator.Free(child)
+			continue
+		}
+		errs = append(errs, w.descend(pe, t.ElementType, child)...)
+		w.allocator.Free(child)
+	}
+	return errs
+}
+
+func (w *schemaMapWalker) doMap(t *schema.Map) (errs ValidationErrors) {
+	m, _ := mapValue(w.allocator, w.value)
+	if m == nil {
+		return nil
+	}
+	defer w.allocator.Free(m)
+	m.IterateUsing(w.allocator, func(key string, val value.Value) bool {
+		tr := t.ElementType
+		if sf, ok := t.FindField(key); ok {
+			tr = sf.Type
+		}
+		errs = append(errs, w.descend(fieldpath.PathElement{FieldName: &key}, tr, val)...)
+		return true
+	})
+	return errs
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/schema"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+// describeAtom summarizes how values of the atom are merged.
+func describeAtom(a schema.Atom) string {
+	switch {
+	case a.Scalar != nil && a.List == nil && a.Map == nil:
+		return "scalar"
+	case a.Map != nil && a.Scalar == nil && a.List == nil:
+		if a.Map.ElementRelationship == "" {
+			return "map separable"
+		}
+		return "map " + string(a.Map.ElementRelationship)
+	case a.List != nil && a.Scalar == nil && a.Map == nil:
+		if a.List.ElementRelationship == "" {
+			return "list atomic"
+		}
+		return "list " + string(a.List.ElementRelationship)
+	}
+	return "ambiguous"
+}
+
+func TestSchemaMap(t *testing.T) {
+	keyedItem := func(keyA, keyB string) fieldpath.PathElement {
+		return fieldpath.PathElement{Key: _KBF("keyA", keyA, "keyB", keyB)}
+	}
+	index := func(i int) fieldpath.PathElement {
+		return fieldpath.PathElement{Index: &i}
+	}
+	reconcileParser := func(t *testing.T, s typed.YAMLObject) typed.ParseableType {
+		parser, err := typed.NewParser(s)
+		if err != nil {
+			t.Fatalf("failed to create schema: %v", err)
+		}
+		return parser.Type("v1")
+	}
+	tests := []struct {
+		name     string
+		parser   func(t *testing.T) typed.ParseableType
+		object   typed.YAMLObject
+		expected map[string]string
+	}{{
+		name: "granular",
+		parser: func(t *testing.T) typed.ParseableType {
+			return reconcileParser(t, granularSchema("v1"))
+		},
+		object: basicLiveObject,
+		expected: map[string]string{
+			_P().String():                                             "map separable",
+			_P("struct").String():                                     "map separable",
+			_P("struct", "numeric").String():                          "scalar",
+			_P("struct", "string").String():                           "scalar",
+			_P("list").String():                                       "list associative",
+			_P("list", _V("one")).String():                            "scalar",
+			_P("list", _V("two")).String():                            "scalar",
+			_P("objectList").String():                                 "list associative",
+			_P("objectList", keyedItem("a1", "b1")).String():          "map separable",
+			_P("objectList", keyedItem("a1", "b1"), "keyA").String():  "scalar",
+			_P("objectList", keyedItem("a1", "b1"), "keyB").String():  "scalar",
+			_P("objectList", keyedItem("a1", "b1"), "value").String(): "scalar",
+			_P("objectList", keyedItem("a2", "b2")).String():          "map separable",
+			_P("objectList", keyedItem("a2", "b2"), "keyA").String():  "scalar",
+			_P("objectList", keyedItem("a2", "b2"), "keyB").String():  "scalar",
+			_P("objectList", keyedItem("a2", "b2"), "value").String(): "scalar",
+			_P("stringMap").String():                                  "map separable",
+			_P("stringMap", "key1").String():                          "scalar",
+			_P("unchanged").String():                                  "map separable",
+			_P("unchanged", "numeric").String():                       "scalar",
+		},
+	}, {
+		name: "atomic",
+		parser: func(t *testing.T) typed.ParseableType {
+			return reconcileParser(t, atomicSchema("v1"))
+		},
+		object: basicLiveObject,
+		expected: map[string]string{
+			_P().String():                                "map separable",
+			_P("struct").String():                        "map atomic",
+			_P("struct", "numeric").String():             "scalar",
+			_P("struct", "string").String():              "scalar",
+			_P("list").String():                          "list atomic",
+			_P("list", index(0)).String():                "scalar",
+			_P("list", index(1)).String():                "scalar",
+			_P("objectList").String():                    "list atomic",
+			_P("objectList", index(0)).String():          "map separable",
+			_P("objectList", index(0), "keyA").String():  "scalar",
+			_P("objectList", index(0), "keyB").String():  "scalar",
+			_P("objectList", index(0), "value").String(): "scalar",
+			_P("objectList", index(1)).String():          "map separable",
+			_P("objectList", index(1), "keyA").String():  "scalar",
+			_P("objectList", index(1), "keyB").String():  "scalar",
+			_P("objectList", index(1), "value").String(): "scalar",
+			_P("stringMap").String():                     "map atomic",
+			_P("stringMap", "key1").String():             "scalar",
+			_P("unchanged").String():                     "map separable",
+			_P("unchanged", "numeric").String():          "scalar",
+		},
+	}, {
+		name: "deduced",
+		parser: func(t *testing.T) typed.ParseableType {
+			return typed.DeducedParseableType
+		},
+		object: `{"list": [1], "map": {"key": "value"}}`,
+		expected: map[string]string{
+			_P().String():                 "map separable",
+			_P("list").String():           "list atomic",
+			_P("list", index(0)).String(): "scalar",
+			_P("map").String():            "map separable",
+			_P("map", "key").String():     "scalar",
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tv, err := tt.parser(t).FromYAML(tt.object)
+			if err != nil {
+				t.Fatalf("failed to parse object: %v", err)
+			}
+			atoms, err := tv.SchemaMap()
+			if err != nil {
+				t.Fatalf("failed to get schema map: %v", err)
+			}
+			got := map[string]string{}
+			for path, atom := range atoms {
+				got[path] = describeAtom(atom)
+			}
+			if len(got) != len(tt.expected) {
+				t.Errorf("expected %v paths, got %v: %v", len(tt.expected), len(got), got)
+			}
+			for path, expected := range tt.expected {
+				if got[path] != expected {
+					t.Errorf("%q: expected %v, got %v", path, expected, got[path])
+				}
+			}
+		})
+	}
+}
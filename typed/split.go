@@ -0,0 +1,201 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed
+
+import (
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/schema"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// SplitItems returns both the value with only the provided list or map
+// items extracted, as returned by ExtractItems, and the remainder of the
+// value without them, as returned by RemoveItems, in a single traversal.
+// When items has no list items, merging the extracted value into the
+// remainder yields the original value.
+func (tv TypedValue) SplitItems(items *fieldpath.Set) (extracted, remainder *TypedValue, err error) {
+	ex, rem, errs := splitItemsWithSchema(tv.value, items, tv.schema, tv.typeRef, tv.depthLimit())
+	if len(errs) > 0 {
+		return nil, nil, errs
+	}
+	extracted, remainder = &tv, &TypedValue{
+		value:    value.NewValueInterface(rem),
+		typeRef:  tv.typeRef,
+		schema:   tv.schema,
+		maxDepth: tv.maxDepth,
+	}
+	extracted.value = value.NewValueInterface(ex)
+	return extracted, remainder, nil
+}
+
+type splittingWalker struct {
+	value     value.Value
+	extracted interface{}
+	remainder interface{}
+	schema    *schema.Schema
+	toSplit   *fieldpath.Set
+	allocator value.Allocator
+	// depth is the number of nested maps and lists left to walk.
+	depth int
+}
+
+// splitItemsWithSchema walks the given value like removeItemsWithSchema,
+// but returns both the items of the toSplit set extracted from the value and
+// the value with these items removed. Values nested more than depth levels
+// deep are reported as errors.
+func splitItemsWithSchema(val value.Value, toSplit *fieldpath.Set, schema *schema.Schema, typeRef schema.TypeRef, depth int) (extracted, remainder interface{}, errs ValidationErrors) {
+	if depth < 0 {
+		return nil, nil, errorf("maximum nesting depth exceeded")
+	}
+	w := &splittingWalker{
+		value:     val,
+		schema:    schema,
+		toSplit:   toSplit,
+		allocator: value.NewFreelistAllocator(),
+		depth:     depth,
+	}
+	errs = resolveSchema(schema, typeRef, val, w)
+	return w.extracted, w.remainder, errs
+}
+
+func (w *splittingWalker) doScalar(t *schema.Scalar) ValidationErrors {
+	w.extracted = w.value.Unstructured()
+	w.remainder = w.extracted
+	return nil
+}
+
+func (w *splittingWalker) doList(t *schema.List) (errs ValidationErrors) {
+	if !w.value.IsList() {
+		return nil
+	}
+	l := w.value.AsListUsing(w.allocator)
+	defer w.allocator.Free(l)
+	// If list is null or empty just return
+	if l == nil || l.Length() == 0 {
+		return nil
+	}
+
+	// atomic lists are entirely extracted.
+	if t.ElementRelationship == schema.Atomic {
+		w.extracted = w.value.Unstructured()
+		return nil
+	}
+
+	var extractedItems, remainingItems []interface{}
+	iter := l.RangeUsing(w.allocator)
+	defer w.allocator.Free(iter)
+	for iter.Next() {
+		_, item := iter.Item()
+		// Ignore error because we have already validated this list
+		pe, _ := listItemToPathElement(w.allocator, w.schema, t, item)
+		path, _ := fieldpath.MakePath(pe)
+		subset := w.toSplit.WithPrefix(pe)
+		if w.toSplit.Has(path) {
+			ex, itemErrs := removeItemsWithSchema(item, w.toSplit, w.schema, t.ElementType, true, w.depth-1)
+			if len(itemErrs) > 0 {
+				errs = append(errs, itemErrs...)
+				continue
+			}
+			extractedItems = append(extractedItems, ex.Unstructured())
+			if !subset.Empty() {
+				if ex, itemErrs = removeItemsWithSchema(item, subset, w.schema, t.ElementType, true, w.depth-1); len(itemErrs) > 0 {
+					errs = append(errs, itemErrs...)
+					continue
+				}
+				extractedItems = append(extractedItems, ex.Unstructured())
+			}
+			continue
+		}
+		if subset.Empty() {
+			remainingItems = append(remainingItems, item.Unstructured())
+			continue
+		}
+		ex, rem, itemErrs := splitItemsWithSchema(item, subset, w.schema, t.ElementType, w.depth-1)
+		errs = append(errs, itemErrs...)
+		extractedItems = append(extractedItems, ex)
+		remainingItems = append(remainingItems, rem)
+	}
+	if len(extractedItems) > 0 {
+		w.extracted = extractedItems
+	}
+	if len(remainingItems) > 0 {
+		w.remainder = remainingItems
+	}
+	return errs
+}
+
+func (w *splittingWalker) doMap(t *schema.Map) (errs ValidationErrors) {
+	if !w.value.IsMap() {
+		return nil
+	}
+	m := w.value.AsMapUsing(w.allocator)
+	if m != nil {
+		defer w.allocator.Free(m)
+	}
+	// If map is null or empty just return
+	if m == nil || m.Empty() {
+		return nil
+	}
+
+	// atomic maps are entirely extracted.
+	if t.ElementRelationship == schema.Atomic {
+		w.extracted = w.value.Unstructured()
+		return nil
+	}
+
+	fieldTypes := map[string]schema.TypeRef{}
+	for _, structField := range t.Fields {
+		fieldTypes[structField.Name] = structField.Type
+	}
+
+	extractedMap := map[string]interface{}{}
+	remainingMap := map[string]interface{}{}
+	m.Iterate(func(k string, val value.Value) bool {
+		pe := fieldpath.PathElement{FieldName: &k}
+		path, _ := fieldpath.MakePath(pe)
+		fieldType := t.ElementType
+		if ft, ok := fieldTypes[k]; ok {
+			fieldType = ft
+		}
+		if w.toSplit.Has(path) {
+			ex, valErrs := removeItemsWithSchema(val, w.toSplit, w.schema, fieldType, true, w.depth-1)
+			if len(valErrs) > 0 {
+				errs = append(errs, valErrs...)
+				return true
+			}
+			extractedMap[k] = ex.Unstructured()
+			return true
+		}
+		subset := w.toSplit.WithPrefix(pe)
+		if subset.Empty() {
+			remainingMap[k] = val.Unstructured()
+			return true
+		}
+		ex, rem, valErrs := splitItemsWithSchema(val, subset, w.schema, fieldType, w.depth-1)
+		errs = append(errs, valErrs...)
+		extractedMap[k] = ex
+		remainingMap[k] = rem
+		return true
+	})
+	if len(extractedMap) > 0 {
+		w.extracted = extractedMap
+	}
+	if len(remainingMap) > 0 {
+		w.remainder = remainingMap
+	}
+	return errs
+}
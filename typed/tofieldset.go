@@ -34,6 +34,7 @@ func (tv TypedValue) toFieldSetWalker() *toFieldSetWalker {
 	v.schema = tv.schema
 	v.typeRef = tv.typeRef
 	v.set = &fieldpath.Set{}
+	v.maxDepth = tv.depthLimit()
 	v.allocator = value.NewFreelistAllocator()
 	return v
 }
@@ -54,6 +55,9 @@ type toFieldSetWalker struct {
 	set  *fieldpath.Set
 	path fieldpath.Path
 
+	// The maximum length of path.
+	maxDepth int
+
 	// Allocate only as many walkers as needed for the depth by storing them here.
 	spareWalkers *[]*toFieldSetWalker
 	allocator    value.Allocator
@@ -84,6 +88,9 @@ func (v *toFieldSetWalker) finishDescent(v2 *toFieldSetWalker) {
 }
 
 func (v *toFieldSetWalker) toFieldSet() ValidationErrors {
+	if len(v.path) > v.maxDepth {
+		return errorf("maximum nesting depth exceeded")
+	}
 	return resolveSchema(v.schema, v.typeRef, v.value, v)
 }
 
@@ -154,6 +161,10 @@ func (v *toFieldSetWalker) visitMapItems(t *schema.Map, m value.Map) (errs Valid
 
 		tr := t.ElementType
 		if sf, ok := t.FindField(key); ok {
+			if sf.Computed {
+				// Computed fields can't be owned.
+				return true
+			}
 			tr = sf.Type
 		}
 		v2 := v.prepareDescent(pe, tr)
@@ -0,0 +1,79 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed
+
+import (
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// WalkTracer is notified of the decisions made while merging or extracting
+// values, e.g. to debug unexpected results. The paths and values passed to
+// it are only valid during the call.
+type WalkTracer interface {
+	// Enter is called before the node at path is walked. When merging,
+	// lhs and rhs are the merged values, either of which may be nil. When
+	// extracting, only rhs is set, to the value the items are extracted
+	// from.
+	Enter(path fieldpath.Path, lhs, rhs value.Value)
+	// Rule is called when the value of the node at path is decided by
+	// rule, rather than by walking its children.
+	Rule(path fieldpath.Path, rule WalkRule)
+	// Leave is called after the node at path is walked, with its
+	// resulting value, which is nil if the node is absent from the result.
+	Leave(path fieldpath.Path, out value.Value)
+}
+
+// WalkRule is the reason why the value of a node is decided without walking
+// its children.
+type WalkRule string
+
+const (
+	// WalkRuleScalar applies to scalars, which have no children.
+	WalkRuleScalar = WalkRule("scalar")
+	// WalkRuleAtomic applies to atomic maps and lists, which are
+	// merged or extracted as a whole.
+	WalkRuleAtomic = WalkRule("atomic")
+	// WalkRuleEmpty applies to maps and lists that are empty or null on
+	// both sides of a merge, which are merged as a whole to preserve the
+	// distinction between empty and null.
+	WalkRuleEmpty = WalkRule("empty")
+	// WalkRuleReplace applies to maps and lists that replace their
+	// counterpart as a whole (see MergeOptions.Replace).
+	WalkRuleReplace = WalkRule("replace")
+	// WalkRuleComputed applies to computed fields, which keep their
+	// existing value when merged.
+	WalkRuleComputed = WalkRule("computed")
+	// WalkRuleDelete applies to fields that are null in the rhs of a
+	// merge and deleted (see MergeOptions.NullMeansDelete).
+	WalkRuleDelete = WalkRule("delete")
+	// WalkRuleRemove applies to items that are removed as a whole.
+	WalkRuleRemove = WalkRule("remove")
+)
+
+// traceNode traces the node at path, whose value is decided by rule.
+func traceNode(tracer WalkTracer, path fieldpath.Path, lhs, rhs value.Value, rule WalkRule, out value.Value) {
+	tracer.Enter(path, lhs, rhs)
+	tracer.Rule(path, rule)
+	tracer.Leave(path, out)
+}
+
+// childPath returns the path of the child pe of path, without modifying
+// the array of path.
+func childPath(path fieldpath.Path, pe fieldpath.PathElement) fieldpath.Path {
+	return append(path[:len(path):len(path)], pe)
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// recordingTracer records the nodes walked, and checks that they are
+// properly nested.
+type recordingTracer struct {
+	t      *testing.T
+	events []string
+	stack  []string
+}
+
+var _ typed.WalkTracer = &recordingTracer{}
+
+func traceString(v value.Value) string {
+	if v == nil {
+		return "-"
+	}
+	b, err := value.ToJSON(v)
+	if err != nil {
+		return err.Error()
+	}
+	return string(b)
+}
+
+func (r *recordingTracer) Enter(path fieldpath.Path, lhs, rhs value.Value) {
+	r.events = append(r.events, fmt.Sprintf("enter [%v] %v %v", path, traceString(lhs), traceString(rhs)))
+	r.stack = append(r.stack, path.String())
+}
+
+func (r *recordingTracer) Rule(path fieldpath.Path, rule typed.WalkRule) {
+	if len(r.stack) == 0 || r.stack[len(r.stack)-1] != path.String() {
+		r.t.Errorf("rule %v for %v, which wasn't entered", rule, path)
+	}
+	r.events = append(r.events, fmt.Sprintf("rule [%v] %v", path, rule))
+}
+
+func (r *recordingTracer) Leave(path fieldpath.Path, out value.Value) {
+	if len(r.stack) == 0 || r.stack[len(r.stack)-1] != path.String() {
+		r.t.Errorf("left %v, which wasn't entered", path)
+	} else {
+		r.stack = r.stack[:len(r.stack)-1]
+	}
+	r.events = append(r.events, fmt.Sprintf("leave [%v] %v", path, traceString(out)))
+}
+
+var traceParser = func() *typed.Parser {
+	parser, err := typed.NewParser(`types:
+- name: type
+  map:
+    fields:
+    - name: set
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: associative
+    - name: atomic
+      type:
+        map:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+    - name: struct
+      type:
+        map:
+          fields:
+          - name: leaf
+            type:
+              scalar: string
+`)
+	if err != nil {
+		panic(err)
+	}
+	return parser
+}()
+
+func TestMergeTracer(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		lhs      typed.YAMLObject
+		rhs      typed.YAMLObject
+		opts     typed.MergeOptions
+		expected []string
+	}{{
+		name: "set",
+		lhs:  `{"set":["a","b"]}`,
+		rhs:  `{"set":["b","c"]}`,
+		expected: []string{
+			`enter [] {"set":["a","b"]} {"set":["b","c"]}`,
+			`enter [.set] ["a","b"] ["b","c"]`,
+			`enter [.set[="a"]] "a" -`,
+			`rule [.set[="a"]] scalar`,
+			`leave [.set[="a"]] "a"`,
+			`enter [.set[="b"]] "b" "b"`,
+			`rule [.set[="b"]] scalar`,
+			`leave [.set[="b"]] "b"`,
+			`enter [.set[="c"]] - "c"`,
+			`rule [.set[="c"]] scalar`,
+			`leave [.set[="c"]] "c"`,
+			`leave [.set] ["a","b","c"]`,
+			`leave [] {"set":["a","b","c"]}`,
+		},
+	}, {
+		name: "atomic",
+		lhs:  `{"atomic":{"a":"x"}}`,
+		rhs:  `{"atomic":{"b":"y"}}`,
+		expected: []string{
+			`enter [] {"atomic":{"a":"x"}} {"atomic":{"b":"y"}}`,
+			`enter [.atomic] {"a":"x"} {"b":"y"}`,
+			`rule [.atomic] atomic`,
+			`leave [.atomic] {"b":"y"}`,
+			`leave [] {"atomic":{"b":"y"}}`,
+		},
+	}, {
+		name: "delete",
+		lhs:  `{"struct":{"leaf":"a"}}`,
+		rhs:  `{"struct":{"leaf":null}}`,
+		opts: typed.MergeOptions{NullMeansDelete: true},
+		expected: []string{
+			`enter [] {"struct":{"leaf":"a"}} {"struct":{"leaf":null}}`,
+			`enter [.struct] {"leaf":"a"} {"leaf":null}`,
+			`enter [.struct.leaf] "a" null`,
+			`rule [.struct.leaf] delete`,
+			`leave [.struct.leaf] -`,
+			`leave [.struct] {}`,
+			`leave [] {"struct":{}}`,
+		},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := traceParser.Type("type")
+			lhs, err := pt.FromYAML(tt.lhs)
+			if err != nil {
+				t.Fatalf("unable to parse lhs: %v", err)
+			}
+			rhs, err := pt.FromYAML(tt.rhs)
+			if err != nil {
+				t.Fatalf("unable to parse rhs: %v", err)
+			}
+			tracer := &recordingTracer{t: t}
+			tt.opts.Tracer = tracer
+			if _, err := lhs.MergeWithOptions(rhs, tt.opts); err != nil {
+				t.Fatalf("merge failed: %v", err)
+			}
+			if got, expected := strings.Join(tracer.events, "\n"), strings.Join(tt.expected, "\n"); got != expected {
+				t.Errorf("expected trace:\n%v\ngot:\n%v", expected, got)
+			}
+		})
+	}
+}
+
+func TestExtractTracer(t *testing.T) {
+	tv, err := traceParser.Type("type").FromYAML(`{"struct":{"leaf":"a"}}`)
+	if err != nil {
+		t.Fatalf("unable to parse: %v", err)
+	}
+	tracer := &recordingTracer{t: t}
+	tv.ExtractItems(_NS(_P("struct", "leaf")), typed.WithTracer(tracer))
+	expected := []string{
+		`enter [] - {"struct":{"leaf":"a"}}`,
+		`enter [.struct] - {"leaf":"a"}`,
+		`enter [.struct.leaf] - "a"`,
+		`rule [.struct.leaf] scalar`,
+		`leave [.struct.leaf] "a"`,
+		`leave [.struct] {"leaf":"a"}`,
+		`leave [] {"struct":{"leaf":"a"}}`,
+	}
+	if got, expected := strings.Join(tracer.events, "\n"), strings.Join(expected, "\n"); got != expected {
+		t.Errorf("expected trace:\n%v\ngot:\n%v", expected, got)
+	}
+}
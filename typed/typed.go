@@ -17,6 +17,7 @@ limitations under the License.
 package typed
 
 import (
+	"fmt"
 	"sync"
 
 	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
@@ -30,11 +31,33 @@ type ValidationOptions int
 const (
 	// AllowDuplicates means that sets and associative lists can have duplicate similar items.
 	AllowDuplicates ValidationOptions = iota
+	// ExplainTypes attaches to each validation error the names of the
+	// schema types resolved on the way to the failing value (see
+	// ValidationError.TypeChain).
+	ExplainTypes
+	// AllowMarkers accepts unset markers (see MarkerKey) where they can be
+	// extracted by ExtractMarkers, in place of fields and associative list
+	// items, as well as replace markers on the granular maps and
+	// associative lists found there. The markers are kept in the value.
+	AllowMarkers
+	// DisallowUnknownFields reports every field of a map that is not among
+	// the fields declared by its schema, even if the map also declares an
+	// element type for the remaining fields. Maps that declare no fields at
+	// all, such as untyped or deduced sections, still accept any field.
+	DisallowUnknownFields
+	// CaseInsensitiveFields renames the fields of maps that only match a
+	// field declared by the schema when ignoring case to the declared
+	// spelling, before validating. Fields that only differ by case are
+	// reported. Since it changes the value, it can only be given when
+	// creating a TypedValue, e.g. with AsTyped or ParseableType.FromYAML,
+	// and is rejected by TypedValue.Validate.
+	CaseInsensitiveFields
 )
 
 // extractItemsOptions is the options available when extracting items.
 type extractItemsOptions struct {
 	appendKeyFields bool
+	tracer          WalkTracer
 }
 
 type ExtractItemsOption func(*extractItemsOptions)
@@ -47,16 +70,50 @@ func WithAppendKeyFields() ExtractItemsOption {
 	}
 }
 
+// WithTracer configures ExtractItems to notify tracer of the nodes walked.
+func WithTracer(tracer WalkTracer) ExtractItemsOption {
+	return func(opts *extractItemsOptions) {
+		opts.tracer = tracer
+	}
+}
+
 // AsTyped accepts a value and a type and returns a TypedValue. 'v' must have
 // type 'typeName' in the schema. An error is returned if the v doesn't conform
-// to the schema.
+// to the schema. String values are normalized as required by their type (see
+// schema.Atom.Normalize).
 func AsTyped(v value.Value, s *schema.Schema, typeRef schema.TypeRef, opts ...ValidationOptions) (*TypedValue, error) {
+	return asTyped(v, s, typeRef, 0, opts...)
+}
+
+func asTyped(v value.Value, s *schema.Schema, typeRef schema.TypeRef, maxDepth int, opts ...ValidationOptions) (*TypedValue, error) {
+	validateOpts := make([]ValidationOptions, 0, len(opts))
+	canonicalize := false
+	for _, opt := range opts {
+		if opt == CaseInsensitiveFields {
+			canonicalize = true
+		} else {
+			validateOpts = append(validateOpts, opt)
+		}
+	}
+	if canonicalize {
+		u, errs := canonicalizeFields(s, typeRef, v)
+		if len(errs) != 0 {
+			return nil, errs
+		}
+		v = value.NewValueInterface(u)
+	}
 	tv := &TypedValue{
-		value:   v,
-		typeRef: typeRef,
-		schema:  s,
+		value:    v,
+		typeRef:  typeRef,
+		schema:   s,
+		maxDepth: maxDepth,
+	}
+	if s.HasNormalizations() {
+		if u, ok := normalizeStrings(s, typeRef, v, tv.depthLimit()); ok {
+			tv.value = value.NewValueInterface(u)
+		}
 	}
-	if err := tv.Validate(opts...); err != nil {
+	if err := tv.Validate(validateOpts...); err != nil {
 		return nil, err
 	}
 	return tv, nil
@@ -84,6 +141,17 @@ type TypedValue struct {
 	value   value.Value
 	typeRef schema.TypeRef
 	schema  *schema.Schema
+	// maxDepth is the maximum nesting depth of value, DefaultMaxDepth if
+	// not set.
+	maxDepth int
+}
+
+// depthLimit returns the maximum nesting depth of tv's value.
+func (tv TypedValue) depthLimit() int {
+	if tv.maxDepth <= 0 {
+		return DefaultMaxDepth
+	}
+	return tv.maxDepth
 }
 
 // TypeRef is the type of the value.
@@ -104,13 +172,21 @@ func (tv TypedValue) Schema() *schema.Schema {
 // Validate returns an error with a list of every spec violation.
 func (tv TypedValue) Validate(opts ...ValidationOptions) error {
 	w := tv.walker()
+	defer w.finished()
 	for _, opt := range opts {
 		switch opt {
 		case AllowDuplicates:
 			w.allowDuplicates = true
+		case ExplainTypes:
+			w.explainTypes = true
+		case AllowMarkers:
+			w.allowMarkers = true
+		case DisallowUnknownFields:
+			w.disallowUnknownFields = true
+		case CaseInsensitiveFields:
+			return errorf("CaseInsensitiveFields can only be used when creating a value")
 		}
 	}
-	defer w.finished()
 	if errs := w.validate(nil); len(errs) != 0 {
 		return errs
 	}
@@ -136,12 +212,18 @@ func (tv TypedValue) ToFieldSet() (*fieldpath.Set, error) {
 //   - Container typed elements will have their items ordered:
 //     1. like tv, if pso doesn't change anything in the container
 //     2. like pso, if pso does change something in the container.
+//   - Fields marked as computed in the schema keep tv's value, if any.
 //
 // tv and pso must both be of the same type (their Schema and TypeRef must
 // match), or an error will be returned. Validation errors will be returned if
 // the objects don't conform to the schema.
 func (tv TypedValue) Merge(pso *TypedValue) (*TypedValue, error) {
-	return merge(&tv, pso, ruleKeepRHS, nil)
+	return merge(&tv, pso, ruleKeepRHS, nil, MergeOptions{})
+}
+
+// MergeWithOptions is like Merge, but customized by opts.
+func (tv TypedValue) MergeWithOptions(pso *TypedValue, opts MergeOptions) (*TypedValue, error) {
+	return merge(&tv, pso, ruleKeepRHS, nil, opts)
 }
 
 var cmpwPool = sync.Pool{
@@ -155,6 +237,12 @@ var cmpwPool = sync.Pool{
 // match), or an error will be returned. Validation errors will be returned if
 // the objects don't conform to the schema.
 func (tv TypedValue) Compare(rhs *TypedValue) (c *Comparison, err error) {
+	return tv.CompareWithOptions(rhs, CompareOptions{})
+}
+
+// CompareWithOptions is like Compare, but leaves are compared as described
+// by opts.
+func (tv TypedValue) CompareWithOptions(rhs *TypedValue, opts CompareOptions) (c *Comparison, err error) {
 	lhs := tv
 	if lhs.schema != rhs.schema {
 		return nil, errorf("expected objects with types from the same schema")
@@ -171,6 +259,8 @@ func (tv TypedValue) Compare(rhs *TypedValue) (c *Comparison, err error) {
 		cmpw.typeRef = schema.TypeRef{}
 		cmpw.comparison = nil
 		cmpw.inLeaf = false
+		cmpw.equalities = nil
+		cmpw.scalarEqual = nil
 
 		cmpwPool.Put(cmpw)
 	}()
@@ -179,6 +269,9 @@ func (tv TypedValue) Compare(rhs *TypedValue) (c *Comparison, err error) {
 	cmpw.rhs = rhs.value
 	cmpw.schema = lhs.schema
 	cmpw.typeRef = lhs.typeRef
+	cmpw.equalities = opts.Equalities
+	cmpw.scalarEqual = opts.ScalarEqual
+	cmpw.maxDepth = lhs.depthLimit()
 	cmpw.comparison = &Comparison{
 		Removed:  fieldpath.NewSet(),
 		Modified: fieldpath.NewSet(),
@@ -196,13 +289,48 @@ func (tv TypedValue) Compare(rhs *TypedValue) (c *Comparison, err error) {
 }
 
 // RemoveItems removes each provided list or map item from the value.
+// Values nested deeper than its maximum depth are kept as is (see
+// RemoveItemsWithError).
 func (tv TypedValue) RemoveItems(items *fieldpath.Set) *TypedValue {
-	tv.value = removeItemsWithSchema(tv.value, items, tv.schema, tv.typeRef, false)
-	return &tv
+	out, _ := tv.removeItems(items)
+	return out
+}
+
+// RemoveItemsWithError is like RemoveItems, but fails if the value is
+// nested deeper than its maximum depth.
+func (tv TypedValue) RemoveItemsWithError(items *fieldpath.Set) (*TypedValue, error) {
+	out, errs := tv.removeItems(items)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return out, nil
+}
+
+func (tv TypedValue) removeItems(items *fieldpath.Set) (*TypedValue, ValidationErrors) {
+	v, errs := removeItemsWithSchema(tv.value, items, tv.schema, tv.typeRef, false, tv.depthLimit())
+	tv.value = v
+	return &tv, errs
 }
 
 // ExtractItems returns a value with only the provided list or map items extracted from the value.
+// Values nested deeper than its maximum depth are extracted as is (see
+// ExtractItemsWithError).
 func (tv TypedValue) ExtractItems(items *fieldpath.Set, opts ...ExtractItemsOption) *TypedValue {
+	out, _ := tv.extractItems(items, opts...)
+	return out
+}
+
+// ExtractItemsWithError is like ExtractItems, but fails if the value is
+// nested deeper than its maximum depth.
+func (tv TypedValue) ExtractItemsWithError(items *fieldpath.Set, opts ...ExtractItemsOption) (*TypedValue, error) {
+	out, errs := tv.extractItems(items, opts...)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return out, nil
+}
+
+func (tv TypedValue) extractItems(items *fieldpath.Set, opts ...ExtractItemsOption) (*TypedValue, ValidationErrors) {
 	options := &extractItemsOptions{}
 	for _, opt := range opts {
 		opt(options)
@@ -233,8 +361,9 @@ func (tv TypedValue) ExtractItems(items *fieldpath.Set, opts ...ExtractItemsOpti
 		}
 	}
 
-	tv.value = removeItemsWithSchema(tv.value, items, tv.schema, tv.typeRef, true)
-	return &tv
+	v, errs := removeItemsWithTracer(tv.value, items, tv.schema, tv.typeRef, true, tv.depthLimit(), options.tracer)
+	tv.value = v
+	return &tv, errs
 }
 
 func (tv TypedValue) Empty() *TypedValue {
@@ -246,7 +375,7 @@ var mwPool = sync.Pool{
 	New: func() interface{} { return &mergingWalker{} },
 }
 
-func merge(lhs, rhs *TypedValue, rule, postRule mergeRule) (*TypedValue, error) {
+func merge(lhs, rhs *TypedValue, rule, postRule mergeRule, opts MergeOptions) (*TypedValue, error) {
 	if lhs.schema != rhs.schema {
 		return nil, errorf("expected objects with types from the same schema")
 	}
@@ -264,6 +393,10 @@ func merge(lhs, rhs *TypedValue, rule, postRule mergeRule) (*TypedValue, error)
 		mw.postItemHook = nil
 		mw.out = nil
 		mw.inLeaf = false
+		mw.budget = nil
+		mw.replace = nil
+		mw.nullMeansDelete = false
+		mw.tracer = nil
 
 		mwPool.Put(mw)
 	}()
@@ -274,18 +407,30 @@ func merge(lhs, rhs *TypedValue, rule, postRule mergeRule) (*TypedValue, error)
 	mw.typeRef = lhs.typeRef
 	mw.rule = rule
 	mw.postItemHook = postRule
+	mw.replace = opts.Replace
+	mw.nullMeansDelete = opts.NullMeansDelete
+	mw.tracer = opts.Tracer
+	mw.maxDepth = lhs.depthLimit()
 	if mw.allocator == nil {
 		mw.allocator = value.NewFreelistAllocator()
 	}
 
+	if opts.MaxSize > 0 {
+		mw.budget = &mergeBudget{max: opts.MaxSize}
+	}
+
 	errs := mw.merge(nil)
+	if mw.budget != nil && mw.budget.exceeded {
+		return nil, fmt.Errorf("merge aborted: result exceeds the maximum size of %v nodes", opts.MaxSize)
+	}
 	if len(errs) > 0 {
 		return nil, errs
 	}
 
 	out := &TypedValue{
-		schema:  lhs.schema,
-		typeRef: lhs.typeRef,
+		schema:   lhs.schema,
+		typeRef:  lhs.typeRef,
+		maxDepth: lhs.maxDepth,
 	}
 	if mw.out != nil {
 		out.value = value.NewValueInterface(*mw.out)
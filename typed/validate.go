@@ -17,7 +17,10 @@ limitations under the License.
 package typed
 
 import (
+	"fmt"
+	"math"
 	"sync"
+	"unicode/utf8"
 
 	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
 	"sigs.k8s.io/structured-merge-diff/v4/schema"
@@ -34,6 +37,14 @@ func (tv TypedValue) walker() *validatingObjectWalker {
 	v.schema = tv.schema
 	v.typeRef = tv.typeRef
 	v.allowDuplicates = false
+	v.description = ""
+	v.explainTypes = false
+	v.typeChain = nil
+	v.rejectAmbiguousDefaults = false
+	v.allowMarkers = false
+	v.disallowUnknownFields = false
+	v.depth = 0
+	v.maxDepth = tv.depthLimit()
 	if v.allocator == nil {
 		v.allocator = value.NewFreelistAllocator()
 	}
@@ -53,6 +64,25 @@ type validatingObjectWalker struct {
 	// If set to true, duplicates will be allowed in
 	// associativeLists/sets.
 	allowDuplicates bool
+	// description of the field being validated, if any.
+	description string
+	// If set to true, errors are annotated with typeChain, the named
+	// types resolved so far.
+	explainTypes bool
+	typeChain    []string
+	// If set to true, duplicate list items that rely on default key
+	// values are reported as ambiguous.
+	rejectAmbiguousDefaults bool
+	// If set to true, markers are accepted in place of fields and
+	// associative list items. Cleared within atomic maps and lists.
+	allowMarkers bool
+	// If set to true, every field missing from a map's declared fields is
+	// reported, rather than validated against the map's element type.
+	disallowUnknownFields bool
+	// depth is the number of maps and lists the value is nested in, which
+	// can't exceed maxDepth.
+	depth    int
+	maxDepth int
 
 	// Allocate only as many walkers as needed for the depth by storing them here.
 	spareWalkers *[]*validatingObjectWalker
@@ -72,6 +102,8 @@ func (v *validatingObjectWalker) prepareDescent(tr schema.TypeRef) *validatingOb
 	}
 	*v2 = *v
 	v2.typeRef = tr
+	v2.description = ""
+	v2.depth++
 	return v2
 }
 
@@ -82,7 +114,37 @@ func (v *validatingObjectWalker) finishDescent(v2 *validatingObjectWalker) {
 }
 
 func (v *validatingObjectWalker) validate(prefixFn func() string) ValidationErrors {
-	return resolveSchema(v.schema, v.typeRef, v.value, v).WithLazyPrefix(prefixFn)
+	if v.depth > v.maxDepth {
+		return errorf("maximum nesting depth exceeded").WithLazyPrefix(prefixFn)
+	}
+	if v.allowMarkers {
+		if marker, ok, err := isMarker(v.allocator, v.value); err != nil {
+			return errorf("%v", err).WithLazyPrefix(prefixFn)
+		} else if ok && marker == UnsetMarker {
+			return nil
+		} else if ok {
+			if a, ok := v.schema.Resolve(v.typeRef); ok {
+				if err := checkReplaceMarker(deduceAtom(a, v.value)); err != nil {
+					return errorf("%v", err).WithLazyPrefix(prefixFn)
+				}
+			}
+		}
+	}
+	if !v.explainTypes {
+		return resolveSchema(v.schema, v.typeRef, v.value, v).WithLazyPrefix(prefixFn)
+	}
+	if v.typeRef.NamedType != nil {
+		// Copy so that siblings don't share the chain.
+		v.typeChain = append(v.typeChain[:len(v.typeChain):len(v.typeChain)], *v.typeRef.NamedType)
+	}
+	errs := resolveSchema(v.schema, v.typeRef, v.value, v)
+	for i := range errs {
+		// Errors from deeper values already have a longer chain.
+		if errs[i].TypeChain == nil {
+			errs[i].TypeChain = v.typeChain
+		}
+	}
+	return errs.WithLazyPrefix(prefixFn)
 }
 
 func validateScalar(t *schema.Scalar, v value.Value, prefix string) (errs ValidationErrors) {
@@ -93,11 +155,20 @@ func validateScalar(t *schema.Scalar, v value.Value, prefix string) (errs Valida
 		return nil
 	}
 	switch *t {
-	case schema.Numeric:
+	case schema.Numeric, schema.Number:
 		if !v.IsFloat() && !v.IsInt() {
-			// TODO: should the schema separate int and float?
 			return errorf("%vexpected numeric (int or float), got %T", prefix, v.Unstructured())
 		}
+	case schema.Integer:
+		if v.IsInt() {
+			break
+		}
+		if !v.IsFloat() {
+			return errorf("%vexpected integer, got %T", prefix, v.Unstructured())
+		}
+		if f := v.AsFloat(); math.IsInf(f, 0) || math.Trunc(f) != f {
+			return errorf("%vexpected integer, got %v", prefix, f)
+		}
 	case schema.String:
 		if !v.IsString() {
 			return errorf("%vexpected string, got %#v", prefix, v)
@@ -117,17 +188,95 @@ func validateScalar(t *schema.Scalar, v value.Value, prefix string) (errs Valida
 }
 
 func (v *validatingObjectWalker) doScalar(t *schema.Scalar) ValidationErrors {
-	if errs := validateScalar(t, v.value, ""); len(errs) > 0 {
+	errs := validateScalar(t, v.value, "")
+	if len(errs) == 0 && *t == schema.String && v.value != nil && v.value.IsString() {
+		errs = v.validateStringLength(v.value.AsString())
+	}
+	if len(errs) > 0 {
+		if d := v.describe(); d != "" {
+			for i := range errs {
+				errs[i].ErrorMessage = fmt.Sprintf("(%v) %v", d, errs[i].ErrorMessage)
+			}
+		}
 		return errs
 	}
 	return nil
 }
 
+// validateStringLength checks the length of s against the bounds of the
+// type being validated.
+func (v *validatingObjectWalker) validateStringLength(s string) ValidationErrors {
+	a, ok := v.schema.Resolve(v.typeRef)
+	if !ok || (a.MinLength == nil && a.MaxLength == nil) {
+		return nil
+	}
+	length := utf8.RuneCountInString(s)
+	if a.MinLength != nil && length < *a.MinLength {
+		return errorf("string is too short: %v characters, expected at least %v", length, *a.MinLength)
+	}
+	if a.MaxLength != nil && length > *a.MaxLength {
+		return errorf("string is too long: %v characters, expected at most %v", length, *a.MaxLength)
+	}
+	return nil
+}
+
+// omittedKeys returns the key fields of t omitted by child or by the item
+// previously observed with the same key pe, if ambiguous defaults are
+// rejected.
+func (v *validatingObjectWalker) omittedKeys(t *schema.List, child value.Value, observed fieldpath.PathElementValueMap, pe fieldpath.PathElement) []string {
+	if !v.rejectAmbiguousDefaults {
+		return nil
+	}
+	items := []value.Value{child}
+	if first, ok := observed.Get(pe); ok {
+		items = append(items, first)
+	}
+	return omittedKeyFields(t, items...)
+}
+
+// omittedKeyFields returns the key fields of t omitted by any of items.
+func omittedKeyFields(t *schema.List, items ...value.Value) []string {
+	var omitted []string
+	for _, key := range t.Keys {
+		for _, item := range items {
+			if !item.IsMap() {
+				continue
+			}
+			if _, ok := item.AsMap().Get(key); !ok {
+				omitted = append(omitted, key)
+				break
+			}
+		}
+	}
+	return omitted
+}
+
+// describe returns the description of the value being validated: the
+// description of its field if any, or else that of its named type.
+func (v *validatingObjectWalker) describe() string {
+	if v.description != "" {
+		return v.description
+	}
+	if v.typeRef.NamedType != nil {
+		if td, ok := v.schema.FindNamedType(*v.typeRef.NamedType); ok {
+			return td.Description
+		}
+	}
+	return ""
+}
+
 func (v *validatingObjectWalker) visitListItems(t *schema.List, list value.List) (errs ValidationErrors) {
 	observedKeys := fieldpath.MakePathElementSet(list.Length())
+	var observedItems fieldpath.PathElementValueMap
+	if v.rejectAmbiguousDefaults {
+		observedItems = fieldpath.MakePathElementValueMap(list.Length())
+	}
 	for i := 0; i < list.Length(); i++ {
 		child := list.AtUsing(v.allocator, i)
 		defer v.allocator.Free(child)
+		if v.allowMarkers && isReplaceItem(v.allocator, child) {
+			continue
+		}
 		var pe fieldpath.PathElement
 		if t.ElementRelationship != schema.Associative {
 			pe.Index = &i
@@ -142,9 +291,18 @@ func (v *validatingObjectWalker) visitListItems(t *schema.List, list value.List)
 				return
 			}
 			if observedKeys.Has(pe) && !v.allowDuplicates {
-				errs = append(errs, errorf("duplicate entries for key %v", pe.String())...)
+				if omitted := v.omittedKeys(t, child, observedItems, pe); len(omitted) > 0 {
+					errs = append(errs, errorf("element %v: key %v is ambiguous because key fields %q are defaulted", i, pe.String(), omitted)...)
+				} else {
+					errs = append(errs, errorf("duplicate entries for key %v", pe.String())...)
+				}
 			}
 			observedKeys.Insert(pe)
+			if v.rejectAmbiguousDefaults {
+				if _, ok := observedItems.Get(pe); !ok {
+					observedItems.Insert(pe, child)
+				}
+			}
 		}
 		v2 := v.prepareDescent(t.ElementType)
 		v2.value = child
@@ -165,6 +323,9 @@ func (v *validatingObjectWalker) doList(t *schema.List) (errs ValidationErrors)
 	}
 
 	defer v.allocator.Free(list)
+	if t.ElementRelationship != schema.Associative {
+		v.allowMarkers = false
+	}
 	errs = v.visitListItems(t, list)
 
 	return errs
@@ -172,16 +333,26 @@ func (v *validatingObjectWalker) doList(t *schema.List) (errs ValidationErrors)
 
 func (v *validatingObjectWalker) visitMapItems(t *schema.Map, m value.Map) (errs ValidationErrors) {
 	m.IterateUsing(v.allocator, func(key string, val value.Value) bool {
+		if v.allowMarkers && key == MarkerKey {
+			// The replace marker of the map, checked by validate.
+			return true
+		}
 		pe := fieldpath.PathElement{FieldName: &key}
 		tr := t.ElementType
+		description := ""
 		if sf, ok := t.FindField(key); ok {
 			tr = sf.Type
+			description = sf.Description
 		} else if (t.ElementType == schema.TypeRef{}) {
 			errs = append(errs, errorf("field not declared in schema").WithPrefix(pe.String())...)
-			return false
+			return v.disallowUnknownFields
+		} else if v.disallowUnknownFields && len(t.Fields) != 0 {
+			errs = append(errs, errorf("field not declared in schema").WithPrefix(pe.String())...)
+			return true
 		}
 		v2 := v.prepareDescent(tr)
 		v2.value = val
+		v2.description = description
 		// Giving pe.String as a parameter actually increases the allocations.
 		errs = append(errs, v2.validate(func() string { return pe.String() })...)
 		v.finishDescent(v2)
@@ -199,6 +370,9 @@ func (v *validatingObjectWalker) doMap(t *schema.Map) (errs ValidationErrors) {
 		return nil
 	}
 	defer v.allocator.Free(m)
+	if t.ElementRelationship == schema.Atomic {
+		v.allowMarkers = false
+	}
 	errs = v.visitMapItems(t, m)
 
 	return errs
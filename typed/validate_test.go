@@ -18,11 +18,15 @@ package typed_test
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
 	"sigs.k8s.io/structured-merge-diff/v4/schema"
 	"sigs.k8s.io/structured-merge-diff/v4/typed"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
 )
 
 type validationTestCase struct {
@@ -237,6 +241,47 @@ var validationCases = []validationTestCase{{
 	}, duplicatesObjects: []typed.YAMLObject{
 		`{"list":[{"key":"a","id":1},{"key":"a","id":1}]}`,
 	},
+}, {
+	name:         "integers",
+	rootTypeName: "deployment",
+	schema: `types:
+- name: deployment
+  map:
+    fields:
+    - name: replicas
+      type:
+        scalar: integer
+    - name: ratio
+      type:
+        scalar: numeric
+    - name: weight
+      type:
+        scalar: number
+`,
+	validObjects: []typed.YAMLObject{
+		`{"replicas":3}`,
+		`{"replicas":-3}`,
+		`{"replicas":null}`,
+		// Floats with an integral value are the same number in JSON.
+		`{"replicas":3.0}`,
+		`{"replicas":1e3}`,
+		`{"ratio":3}`,
+		`{"ratio":3.5}`,
+		`{"weight":3}`,
+		`{"weight":3.5}`,
+	},
+	invalidObjects: []typed.YAMLObject{
+		`{"replicas":3.5}`,
+		`{"replicas":-0.1}`,
+		`{"replicas":.inf}`,
+		`{"replicas":.nan}`,
+		`{"replicas":"3"}`,
+		`{"replicas":true}`,
+		`{"replicas":[3]}`,
+		`{"ratio":"3.5"}`,
+		`{"weight":"3.5"}`,
+		`{"weight":true}`,
+	},
 }}
 
 func (tt validationTestCase) test(t *testing.T) {
@@ -307,6 +352,270 @@ func TestSchemaSchema(t *testing.T) {
 	}
 }
 
+func TestValidationErrorDescriptions(t *testing.T) {
+	parser, err := typed.NewParser(`types:
+- name: deployment
+  map:
+    fields:
+    - name: replicas
+      description: desired replica count
+      type:
+        scalar: numeric
+    - name: paused
+      type:
+        scalar: boolean
+    - name: image
+      type:
+        namedType: image
+    - name: labels
+      description: user labels
+      type:
+        map:
+          elementType:
+            scalar: string
+- name: image
+  description: container image reference
+  scalar: string
+`)
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+	pt := parser.Type("deployment")
+
+	tests := []struct {
+		object   typed.YAMLObject
+		expected string
+	}{{
+		object:   `{"replicas": "three"}`,
+		expected: ".replicas: (desired replica count) expected numeric (int or float), got string",
+	}, {
+		object:   `{"image": 1}`,
+		expected: ".image: (container image reference) expected string, got ",
+	}, {
+		object:   `{"paused": "yes"}`,
+		expected: ".paused: expected boolean, got ",
+	}, {
+		// The description of the map doesn't apply to its elements.
+		object:   `{"labels": {"a": 1}}`,
+		expected: ".labels.a: expected string, got ",
+	}}
+	for _, tt := range tests {
+		t.Run(string(tt.object), func(t *testing.T) {
+			_, err := pt.FromYAML(tt.object)
+			if err == nil {
+				t.Fatal("expected a validation error")
+			}
+			if !strings.HasPrefix(err.Error(), tt.expected) {
+				t.Errorf("expected error starting with %q, got %q", tt.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestValidationExplainTypes(t *testing.T) {
+	parser, err := typed.NewParser(`types:
+- name: pod
+  map:
+    fields:
+    - name: spec
+      type:
+        namedType: podSpec
+    - name: name
+      type:
+        scalar: string
+- name: podSpec
+  map:
+    fields:
+    - name: containers
+      type:
+        list:
+          elementType:
+            namedType: container
+          elementRelationship: atomic
+- name: container
+  map:
+    fields:
+    - name: port
+      type:
+        namedType: port
+- name: port
+  scalar: numeric
+`)
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+	pt := parser.Type("pod")
+
+	object := typed.YAMLObject(`{"name": 1, "spec": {"containers": [{"port": 80}, {"port": "http"}]}}`)
+	_, err = pt.FromYAML(object, typed.ExplainTypes)
+	errs, ok := err.(typed.ValidationErrors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("expected two validation errors, got %v", err)
+	}
+	expected := map[string][]string{
+		".name":                    {"pod"},
+		".spec.containers[1].port": {"pod", "podSpec", "container", "port"},
+	}
+	for _, e := range errs {
+		if !reflect.DeepEqual(e.TypeChain, expected[e.Path]) {
+			t.Errorf("expected type chain %v for %v, got %v", expected[e.Path], e.Path, e.TypeChain)
+		}
+	}
+	if !strings.Contains(err.Error(), "(types: pod -> podSpec -> container -> port)") {
+		t.Errorf("expected the type chain in the error message, got %v", err)
+	}
+
+	// Type chains are only reported when asked for.
+	_, err = pt.FromYAML(object)
+	for _, e := range err.(typed.ValidationErrors) {
+		if e.TypeChain != nil {
+			t.Errorf("expected no type chain for %v, got %v", e.Path, e.TypeChain)
+		}
+	}
+}
+
+func TestValidationDisallowUnknownFields(t *testing.T) {
+	parser, err := typed.NewParser(`types:
+- name: object
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    - name: spec
+      type:
+        namedType: spec
+    - name: extra
+      type:
+        namedType: __untyped_deduced_
+    - name: labels
+      type:
+        map:
+          elementType:
+            scalar: string
+- name: spec
+  map:
+    fields:
+    - name: replicas
+      type:
+        scalar: numeric
+    elementType:
+      scalar: string
+- name: __untyped_deduced_
+  scalar: untyped
+  list:
+    elementType:
+      namedType: __untyped_atomic_
+    elementRelationship: atomic
+  map:
+    elementType:
+      namedType: __untyped_deduced_
+    elementRelationship: separable
+- name: __untyped_atomic_
+  scalar: untyped
+  list:
+    elementType:
+      namedType: __untyped_atomic_
+    elementRelationship: atomic
+  map:
+    elementType:
+      namedType: __untyped_atomic_
+    elementRelationship: atomic
+`)
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+	pt := parser.Type("object")
+
+	// Untyped sections and maps without declared fields accept any field.
+	valid := typed.YAMLObject(`{"name": "a", "spec": {"replicas": 1}, "extra": {"anything": {"goes": [1]}}, "labels": {"app": "a"}}`)
+	if _, err := pt.FromYAML(valid, typed.DisallowUnknownFields); err != nil {
+		t.Errorf("expected %v to be valid, got %v", valid, err)
+	}
+
+	// The spec accepts unknown string fields unless they are disallowed.
+	object := typed.YAMLObject(`{"name": "a", "nmae": "b", "naem": "c", "spec": {"replicas": 1, "replicsa": "2"}}`)
+	if _, err := pt.FromYAML(object); err == nil || strings.Contains(err.Error(), "replicsa") {
+		t.Errorf("expected only the root fields to be rejected, got %v", err)
+	}
+	_, err = pt.FromYAML(object, typed.DisallowUnknownFields)
+	errs, ok := err.(typed.ValidationErrors)
+	if !ok {
+		t.Fatalf("expected validation errors, got %v", err)
+	}
+	paths := map[string]bool{}
+	for _, e := range errs {
+		paths[e.Path] = true
+	}
+	expected := map[string]bool{".nmae": true, ".naem": true, ".spec.replicsa": true}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected errors for %v, got %v", expected, err)
+	}
+}
+
+func TestValidateStringLength(t *testing.T) {
+	parser, err := typed.NewParser(`types:
+- name: object
+  map:
+    fields:
+    - name: name
+      type:
+        namedType: name
+    - name: code
+      type:
+        scalar: string
+        minLength: 2
+        maxLength: 2
+- name: name
+  scalar: string
+  minLength: 1
+  maxLength: 5
+`)
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+	pt := parser.Type("object")
+
+	tests := []struct {
+		object   typed.YAMLObject
+		expected string
+	}{
+		{object: `{"name": "a"}`},
+		{object: `{"name": "abcde"}`},
+		{object: `{"name": "héllo"}`},
+		{object: `{"name": "日本語"}`},
+		{object: `{"code": "ab"}`},
+		{object: `{"code": "é!"}`},
+		{
+			object:   `{"name": ""}`,
+			expected: ".name: string is too short: 0 characters, expected at least 1",
+		}, {
+			object:   `{"name": "abcdef"}`,
+			expected: ".name: string is too long: 6 characters, expected at most 5",
+		}, {
+			object:   `{"name": "日本語日本語"}`,
+			expected: ".name: string is too long: 6 characters, expected at most 5",
+		}, {
+			object:   `{"code": "é"}`,
+			expected: ".code: string is too short: 1 characters, expected at least 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.object), func(t *testing.T) {
+			_, err := pt.FromYAML(tt.object)
+			if tt.expected == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("expected error %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
 func BenchmarkValidateStructured(b *testing.B) {
 	type Primitives struct {
 		s string
@@ -401,3 +710,188 @@ func BenchmarkValidateStructured(b *testing.B) {
 		})
 	}
 }
+
+const recursiveSchema = typed.YAMLObject(`types:
+- name: node
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    - name: child
+      type:
+        namedType: node
+    - name: children
+      type:
+        list:
+          elementType:
+            namedType: node
+          elementRelationship: atomic
+`)
+
+var recursiveParser = func() *typed.Parser {
+	parser, err := typed.NewParser(recursiveSchema)
+	if err != nil {
+		panic(err)
+	}
+	return parser
+}()
+
+// deepNode returns a node with depth nested children.
+func deepNode(depth int) map[string]interface{} {
+	node := map[string]interface{}{"name": "leaf"}
+	for i := 0; i < depth; i++ {
+		node = map[string]interface{}{"child": node}
+	}
+	return node
+}
+
+// deepPath returns the path of the node depth levels down a deepNode.
+func deepPath(depth int) fieldpath.Path {
+	path := make([]interface{}, depth)
+	for i := range path {
+		path[i] = "child"
+	}
+	return _P(path...)
+}
+
+func TestValidationMaxDepth(t *testing.T) {
+	deep := deepNode(2000)
+	_, err := recursiveParser.Type("node").FromUnstructured(deep)
+	if err == nil || !strings.Contains(err.Error(), "maximum nesting depth exceeded") {
+		t.Fatalf("expected the depth to be exceeded, got %v", err)
+	}
+
+	// Lists count towards the depth too.
+	_, err = recursiveParser.Type("node").FromUnstructured(map[string]interface{}{
+		"children": []interface{}{deepNode(typed.DefaultMaxDepth)},
+	})
+	if err == nil || !strings.Contains(err.Error(), "maximum nesting depth exceeded") {
+		t.Fatalf("expected the depth to be exceeded, got %v", err)
+	}
+	if _, err := recursiveParser.Type("node").FromUnstructured(deepNode(typed.DefaultMaxDepth - 1)); err != nil {
+		t.Fatalf("expected a value within the depth to be valid, got %v", err)
+	}
+
+	// Values that weren't validated are still checked by other walks.
+	unvalidated := typed.AsTypedUnvalidated(value.NewValueInterface(deep), &recursiveParser.Schema, recursiveParser.Type("node").TypeRef)
+	if _, err := unvalidated.Merge(unvalidated); err == nil || !strings.Contains(err.Error(), "maximum nesting depth exceeded") {
+		t.Errorf("expected the depth to be exceeded when merging, got %v", err)
+	}
+	if _, err := unvalidated.Compare(unvalidated); err == nil || !strings.Contains(err.Error(), "maximum nesting depth exceeded") {
+		t.Errorf("expected the depth to be exceeded when comparing, got %v", err)
+	}
+	if _, err := unvalidated.ToFieldSet(); err == nil || !strings.Contains(err.Error(), "maximum nesting depth exceeded") {
+		t.Errorf("expected the depth to be exceeded when building the field set, got %v", err)
+	}
+	child := fieldpath.NewSet(_P("child"))
+	// Items are extracted along the whole depth of the value, unless the
+	// depth error is asked for.
+	if extracted := unvalidated.ExtractItems(child); !reflect.DeepEqual(extracted.AsValue().Unstructured(), deep) {
+		t.Errorf("expected the whole value to be extracted")
+	}
+	if _, err := unvalidated.ExtractItemsWithError(child); err == nil || !strings.Contains(err.Error(), "maximum nesting depth exceeded") {
+		t.Errorf("expected the depth to be exceeded when extracting, got %v", err)
+	}
+	if _, err := unvalidated.RemoveItemsWithError(fieldpath.NewSet(deepPath(typed.DefaultMaxDepth + 2))); err == nil || !strings.Contains(err.Error(), "maximum nesting depth exceeded") {
+		t.Errorf("expected the depth to be exceeded when removing, got %v", err)
+	}
+
+	// The limit can be raised on the parser.
+	parser, err := typed.NewParser(recursiveSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser.MaxDepth = 3000
+	tv, err := parser.Type("node").FromUnstructured(deep)
+	if err != nil {
+		t.Fatalf("expected the depth to be within the parser's limit, got %v", err)
+	}
+	merged, err := tv.Merge(tv)
+	if err != nil {
+		t.Fatalf("unexpected error merging: %v", err)
+	}
+	if _, err := merged.Compare(tv); err != nil {
+		t.Fatalf("unexpected error comparing: %v", err)
+	}
+	if _, err := merged.ToFieldSet(); err != nil {
+		t.Fatalf("unexpected error building the field set: %v", err)
+	}
+	if _, err := merged.ExtractItemsWithError(child); err != nil {
+		t.Fatalf("unexpected error extracting: %v", err)
+	}
+	if _, err := merged.RemoveItemsWithError(fieldpath.NewSet(deepPath(2000))); err != nil {
+		t.Fatalf("unexpected error removing: %v", err)
+	}
+}
+
+func TestValidationErrorPositions(t *testing.T) {
+	parser, err := typed.NewParser(`types:
+- name: pod
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    - name: containers
+      type:
+        list:
+          elementType:
+            namedType: container
+          elementRelationship: associative
+          keys:
+          - name
+    - name: args
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: atomic
+- name: container
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    - name: port
+      type:
+        scalar: numeric
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = parser.Type("pod").FromYAML(`name: web
+containers:
+- name: a
+  port: 80
+- name: b
+  port: http
+args:
+- run
+-   {x: 1}
+`)
+	errs, ok := err.(typed.ValidationErrors)
+	if !ok {
+		t.Fatalf("expected validation errors, got %v", err)
+	}
+	type position struct {
+		path         string
+		line, column int
+	}
+	var got []position
+	for _, e := range errs {
+		got = append(got, position{e.Path, e.Line, e.Column})
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i].line < got[j].line })
+	expected := []position{
+		{`.containers[name="b"].port`, 6, 9},
+		{`.args[1]`, 9, 5},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected positions %v, got %v", expected, got)
+	}
+	// The position isn't part of the message.
+	if strings.Contains(err.Error(), "line") {
+		t.Errorf("unexpected position in error %q", err)
+	}
+}
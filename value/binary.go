@@ -0,0 +1,236 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// binaryVersion is written as the first byte of every binary encoded
+// value, so that the format can evolve without misreading older data.
+const binaryVersion byte = 1
+
+// Tags identifying the kind of each encoded value.
+const (
+	binaryNull byte = iota
+	binaryFalse
+	binaryTrue
+	binaryInt
+	binaryFloat
+	binaryString
+	binaryList
+	binaryMap
+)
+
+// MarshalBinary encodes v in a compact tag-length-value binary form, which
+// is much faster to decode than JSON. Every value starts with a one byte
+// tag; ints and floats are followed by their 8 bytes, strings by their
+// length and bytes, lists by their length and items, and maps by their
+// length and their key/value pairs sorted by key. Lengths are encoded as
+// unsigned varints. The encoding of a given value is therefore stable.
+func MarshalBinary(v Value) ([]byte, error) {
+	buf := []byte{binaryVersion}
+	return appendBinary(buf, v)
+}
+
+func appendBinary(buf []byte, v Value) ([]byte, error) {
+	var err error
+	switch {
+	case v.IsNull():
+		buf = append(buf, binaryNull)
+	case v.IsBool():
+		if v.AsBool() {
+			buf = append(buf, binaryTrue)
+		} else {
+			buf = append(buf, binaryFalse)
+		}
+	case v.IsInt():
+		buf = append(buf, binaryInt)
+		buf = appendUint64(buf, uint64(v.AsInt()))
+	case v.IsFloat():
+		buf = append(buf, binaryFloat)
+		buf = appendUint64(buf, math.Float64bits(v.AsFloat()))
+	case v.IsString():
+		buf = append(buf, binaryString)
+		buf = appendBinaryString(buf, v.AsString())
+	case v.IsList():
+		l := v.AsList()
+		buf = append(buf, binaryList)
+		buf = appendUvarint(buf, uint64(l.Length()))
+		for i := 0; i < l.Length(); i++ {
+			if buf, err = appendBinary(buf, l.At(i)); err != nil {
+				return nil, err
+			}
+		}
+	case v.IsMap():
+		m := v.AsMap()
+		keys := make([]string, 0, m.Length())
+		m.Iterate(func(key string, _ Value) bool {
+			keys = append(keys, key)
+			return true
+		})
+		sort.Strings(keys)
+		buf = append(buf, binaryMap)
+		buf = appendUvarint(buf, uint64(len(keys)))
+		for _, key := range keys {
+			child, _ := m.Get(key)
+			buf = appendBinaryString(buf, key)
+			if buf, err = appendBinary(buf, child); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unable to encode value of unknown type: %v", v.Unstructured())
+	}
+	return buf, nil
+}
+
+func appendUint64(buf []byte, u uint64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], u)
+	return append(buf, b[:]...)
+}
+
+func appendUvarint(buf []byte, u uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], u)
+	return append(buf, b[:n]...)
+}
+
+func appendBinaryString(buf []byte, s string) []byte {
+	buf = appendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+var errBinaryTruncated = errors.New("truncated binary value")
+
+// UnmarshalBinary decodes a value encoded by MarshalBinary.
+func UnmarshalBinary(data []byte) (Value, error) {
+	if len(data) == 0 {
+		return nil, errBinaryTruncated
+	}
+	if data[0] != binaryVersion {
+		return nil, fmt.Errorf("unsupported binary value version %v", data[0])
+	}
+	d := binaryDecoder{data: data[1:]}
+	v, err := d.decode()
+	if err != nil {
+		return nil, err
+	}
+	if len(d.data) != 0 {
+		return nil, fmt.Errorf("unexpected %v trailing bytes after binary value", len(d.data))
+	}
+	return NewValueInterface(v), nil
+}
+
+type binaryDecoder struct {
+	data []byte
+}
+
+func (d *binaryDecoder) decode() (interface{}, error) {
+	if len(d.data) == 0 {
+		return nil, errBinaryTruncated
+	}
+	tag := d.data[0]
+	d.data = d.data[1:]
+	switch tag {
+	case binaryNull:
+		return nil, nil
+	case binaryFalse:
+		return false, nil
+	case binaryTrue:
+		return true, nil
+	case binaryInt:
+		u, err := d.uint64()
+		return int64(u), err
+	case binaryFloat:
+		u, err := d.uint64()
+		return math.Float64frombits(u), err
+	case binaryString:
+		return d.string()
+	case binaryList:
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		l := make([]interface{}, n)
+		for i := range l {
+			if l[i], err = d.decode(); err != nil {
+				return nil, err
+			}
+		}
+		return l, nil
+	case binaryMap:
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[string]interface{}, n)
+		for i := 0; i < n; i++ {
+			key, err := d.string()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := m[key]; ok {
+				return nil, fmt.Errorf("duplicate key %q in binary map", key)
+			}
+			if m[key], err = d.decode(); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	default:
+		return nil, fmt.Errorf("unknown binary value tag %v", tag)
+	}
+}
+
+func (d *binaryDecoder) uint64() (uint64, error) {
+	if len(d.data) < 8 {
+		return 0, errBinaryTruncated
+	}
+	u := binary.BigEndian.Uint64(d.data)
+	d.data = d.data[8:]
+	return u, nil
+}
+
+// length reads a length, which can't exceed the number of remaining
+// bytes since every item takes at least one byte.
+func (d *binaryDecoder) length() (int, error) {
+	n, size := binary.Uvarint(d.data)
+	if size <= 0 {
+		return 0, errBinaryTruncated
+	}
+	d.data = d.data[size:]
+	if n > uint64(len(d.data)) {
+		return 0, errBinaryTruncated
+	}
+	return int(n), nil
+}
+
+func (d *binaryDecoder) string() (string, error) {
+	n, err := d.length()
+	if err != nil {
+		return "", err
+	}
+	s := string(d.data[:n])
+	d.data = d.data[n:]
+	return s, nil
+}
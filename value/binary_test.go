@@ -0,0 +1,156 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	table := []struct {
+		name string
+		v    interface{}
+	}{
+		{"null", nil},
+		{"true", true},
+		{"false", false},
+		{"zero", int64(0)},
+		{"max-int", int64(math.MaxInt64)},
+		{"min-int", int64(math.MinInt64)},
+		{"float", 1.5},
+		{"max-float", math.MaxFloat64},
+		{"smallest-float", math.SmallestNonzeroFloat64},
+		{"empty-string", ""},
+		{"string", "héllo\x00world"},
+		{"empty-list", []interface{}{}},
+		{"empty-map", map[string]interface{}{}},
+		{"nested", map[string]interface{}{
+			"b": []interface{}{int64(1), "two", 3.5, nil, map[string]interface{}{"c": true}},
+			"a": map[string]interface{}{"": "empty key", "d": []interface{}{}},
+		}},
+	}
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			v := value.NewValueInterface(tt.v)
+			data, err := value.MarshalBinary(v)
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+			got, err := value.UnmarshalBinary(data)
+			if err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+			if !value.Equals(v, got) {
+				t.Errorf("expected %v, got %v", value.ToString(v), value.ToString(got))
+			}
+		})
+	}
+}
+
+func TestBinaryRoundTripLarge(t *testing.T) {
+	v := largeList(1000)
+	data, err := value.MarshalBinary(v)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	got, err := value.UnmarshalBinary(data)
+	if err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if !value.Equals(v, got) {
+		t.Errorf("large value didn't round-trip")
+	}
+}
+
+func TestBinaryStable(t *testing.T) {
+	data, err := value.MarshalBinary(value.NewValueInterface(map[string]interface{}{
+		"b": int64(1),
+		"a": []interface{}{true, "x"},
+	}))
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	expected := []byte{
+		1,    // version
+		7, 2, // map of 2
+		1, 'a', // key "a"
+		6, 2, 2, // list of 2, true
+		5, 1, 'x', // "x"
+		1, 'b', // key "b"
+		3, 0, 0, 0, 0, 0, 0, 0, 1, // int 1
+	}
+	if !bytes.Equal(data, expected) {
+		t.Errorf("expected %v, got %v", expected, data)
+	}
+}
+
+func TestUnmarshalBinaryErrors(t *testing.T) {
+	table := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", []byte{}},
+		{"bad-version", []byte{2, 0}},
+		{"no-value", []byte{1}},
+		{"unknown-tag", []byte{1, 42}},
+		{"truncated-int", []byte{1, 3, 0, 0}},
+		{"truncated-string", []byte{1, 5, 3, 'a'}},
+		{"truncated-list", []byte{1, 6, 2, 0}},
+		{"huge-length", []byte{1, 6, 0xff, 0xff, 0xff, 0xff, 0x0f}},
+		{"duplicate-key", []byte{1, 7, 2, 1, 'a', 0, 1, 'a', 0}},
+		{"trailing-bytes", []byte{1, 0, 0}},
+	}
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			if v, err := value.UnmarshalBinary(tt.data); err == nil {
+				t.Errorf("expected an error, got %v", value.ToString(v))
+			}
+		})
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	v := largeList(1000)
+	jsonData, err := value.ToJSON(v)
+	if err != nil {
+		b.Fatal(err)
+	}
+	binaryData, err := value.MarshalBinary(v)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("JSON", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := value.FromJSON(jsonData); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Binary", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := value.UnmarshalBinary(binaryData); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
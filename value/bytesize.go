@@ -0,0 +1,68 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value
+
+// scalarByteSize is the size counted for numbers and booleans.
+const scalarByteSize = 8
+
+// ByteSize returns the approximate size of v in bytes: the sum of the
+// lengths of its strings and map keys, plus 8 for every number and
+// boolean.
+func ByteSize(v Value) int {
+	size, _ := ByteSizeCapped(v, -1)
+	return size
+}
+
+// ByteSizeCapped is like ByteSize, but stops walking v as soon as the size
+// exceeds limit, in which case the partial size is returned along with
+// false. A negative limit means no limit.
+func ByteSizeCapped(v Value, limit int) (int, bool) {
+	s := byteSizer{limit: limit}
+	s.walk(v)
+	return s.size, !s.exceeded()
+}
+
+type byteSizer struct {
+	size  int
+	limit int
+}
+
+func (s *byteSizer) exceeded() bool {
+	return s.limit >= 0 && s.size > s.limit
+}
+
+func (s *byteSizer) walk(v Value) {
+	switch {
+	case v.IsMap():
+		v.AsMap().Iterate(func(key string, child Value) bool {
+			s.size += len(key)
+			if !s.exceeded() {
+				s.walk(child)
+			}
+			return !s.exceeded()
+		})
+	case v.IsList():
+		l := v.AsList()
+		for i := 0; i < l.Length() && !s.exceeded(); i++ {
+			s.walk(l.At(i))
+		}
+	case v.IsString():
+		s.size += len(v.AsString())
+	case v.IsInt(), v.IsFloat(), v.IsBool():
+		s.size += scalarByteSize
+	}
+}
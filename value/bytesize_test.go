@@ -0,0 +1,67 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+const byteSizeDocument = `{
+  "name": "abc",
+  "count": 3,
+  "ratio": 0.5,
+  "enabled": true,
+  "nothing": null,
+  "list": ["de", "f", 1],
+  "nested": {"k": "vwxyz"}
+}`
+
+func TestByteSize(t *testing.T) {
+	v := parseValue(t, byteSizeDocument)
+	// Keys: name(4) count(5) ratio(5) enabled(7) nothing(7) list(4) nested(6) k(1) = 39.
+	// Strings: abc(3) de(2) f(1) vwxyz(5) = 11.
+	// Numbers and booleans: count, ratio, enabled, 1 = 4*8 = 32.
+	const expected = 39 + 11 + 32
+	if got := value.ByteSize(v); got != expected {
+		t.Fatalf("expected size %v, got %v", expected, got)
+	}
+
+	if got, ok := value.ByteSizeCapped(v, expected); !ok || got != expected {
+		t.Errorf("expected size %v within the limit, got %v, %v", expected, got, ok)
+	}
+	if got, ok := value.ByteSizeCapped(v, expected-1); ok || got <= expected-1 {
+		t.Errorf("expected size over the limit, got %v, %v", got, ok)
+	}
+}
+
+func TestByteSizeCappedShortCircuits(t *testing.T) {
+	items := make([]interface{}, 1000)
+	for i := range items {
+		items[i] = "0123456789"
+	}
+	v := value.NewValueInterface(items)
+	if got := value.ByteSize(v); got != 10000 {
+		t.Fatalf("expected size 10000, got %v", got)
+	}
+	// The walk stops at the first item over the limit.
+	got, ok := value.ByteSizeCapped(v, 25)
+	if ok || got != 30 {
+		t.Errorf("expected to stop at 30 bytes, got %v, %v", got, ok)
+	}
+}
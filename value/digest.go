@@ -0,0 +1,126 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+	"sort"
+)
+
+// Tags written ahead of each value fed to the digest.
+const (
+	digestNull byte = iota
+	digestFalse
+	digestTrue
+	digestNumber
+	digestString
+	digestList
+	digestMap
+)
+
+// canonicalNaN is the bit pattern hashed for every NaN.
+const canonicalNaN uint64 = 0x7ff8000000000000
+
+// FNVDigest returns the 64-bit FNV-1a hash of a canonical encoding of v,
+// so that values that are Equals hash the same. The encoding is simple
+// enough to be reimplemented independently:
+//
+//   - null is the byte 0x00, false is 0x01 and true is 0x02.
+//   - Numbers are the byte 0x03 followed by the 8 big-endian bytes of their
+//     IEEE 754 float64 representation. Ints are converted to float64 first,
+//     as Equals does when comparing them to floats, negative zero is
+//     hashed as positive zero and every NaN as 0x7ff8000000000000.
+//   - Strings are the byte 0x04 followed by their length in bytes, as an
+//     8 byte big-endian unsigned integer, and their bytes.
+//   - Lists are the byte 0x05 followed by their length, as an 8 byte
+//     big-endian unsigned integer, and then each item in order.
+//   - Maps are the byte 0x06 followed by their number of fields, as an 8
+//     byte big-endian unsigned integer, and then for each field, sorted by
+//     the bytes of its key, the key's length and bytes (encoded as for a
+//     string but without the leading 0x04) followed by the value.
+func FNVDigest(v Value) uint64 {
+	h := fnv.New64a()
+	writeDigest(h, v)
+	return h.Sum64()
+}
+
+func writeDigest(h hash.Hash64, v Value) {
+	switch {
+	case v.IsNull():
+		h.Write([]byte{digestNull})
+	case v.IsBool():
+		if v.AsBool() {
+			h.Write([]byte{digestTrue})
+		} else {
+			h.Write([]byte{digestFalse})
+		}
+	case v.IsInt():
+		writeDigestNumber(h, float64(v.AsInt()))
+	case v.IsFloat():
+		writeDigestNumber(h, v.AsFloat())
+	case v.IsString():
+		h.Write([]byte{digestString})
+		writeDigestString(h, v.AsString())
+	case v.IsList():
+		l := v.AsList()
+		h.Write([]byte{digestList})
+		writeDigestUint64(h, uint64(l.Length()))
+		for i := 0; i < l.Length(); i++ {
+			writeDigest(h, l.At(i))
+		}
+	case v.IsMap():
+		m := v.AsMap()
+		keys := make([]string, 0, m.Length())
+		m.Iterate(func(key string, _ Value) bool {
+			keys = append(keys, key)
+			return true
+		})
+		sort.Strings(keys)
+		h.Write([]byte{digestMap})
+		writeDigestUint64(h, uint64(len(keys)))
+		for _, key := range keys {
+			child, _ := m.Get(key)
+			writeDigestString(h, key)
+			writeDigest(h, child)
+		}
+	}
+}
+
+func writeDigestNumber(h hash.Hash64, f float64) {
+	bits := math.Float64bits(f)
+	if f == 0 {
+		bits = 0
+	} else if math.IsNaN(f) {
+		bits = canonicalNaN
+	}
+	h.Write([]byte{digestNumber})
+	writeDigestUint64(h, bits)
+}
+
+func writeDigestString(h hash.Hash64, s string) {
+	writeDigestUint64(h, uint64(len(s)))
+	h.Write([]byte(s))
+}
+
+func writeDigestUint64(h hash.Hash64, u uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], u)
+	h.Write(b[:])
+}
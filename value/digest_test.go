@@ -0,0 +1,96 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value_test
+
+import (
+	"math"
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// The expected digests are locked in so that changes to the encoding are
+// noticed; other implementations of FNVDigest must produce the same values.
+func TestFNVDigest(t *testing.T) {
+	table := []struct {
+		name     string
+		v        interface{}
+		expected uint64
+	}{
+		{"null", nil, 0xaf63bd4c8601b7df},
+		{"false", false, 0xaf63bc4c8601b62c},
+		{"true", true, 0xaf63bf4c8601bb45},
+		{"zero", int64(0), 0x796ed797b92b1fd2},
+		{"int", int64(42), 0x938a44a70a8d78c1},
+		{"negative-int", int64(-7), 0x7adf4ad9276ab54e},
+		{"float", 1.5, 0xeb21e031b870dad5},
+		{"integral-float", 42.0, 0x938a44a70a8d78c1},
+		{"negative-zero", math.Copysign(0, -1), 0x796ed797b92b1fd2},
+		{"nan", math.NaN(), 0x5311679d5e2cd095},
+		{"empty-string", "", 0x985b2cc3d2245173},
+		{"string", "hello", 0x63e9b08f6cab980c},
+		{"unicode-string", "héllo", 0xbc14c666e1dac960},
+		{"empty-list", []interface{}{}, 0x04f0d7663d895b60},
+		{"list", []interface{}{int64(1), "a", nil}, 0x52b04a0b4f7dcf35},
+		{"empty-map", map[string]interface{}{}, 0xbf2fd77efb5a3d99},
+		{"map", map[string]interface{}{"b": int64(1), "a": "x", "B": true}, 0xa354b7ad1c41c801},
+		{"nested", map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "c", "ports": []interface{}{int64(80), int64(443)}},
+				},
+				"replicas": int64(3),
+			},
+		}, 0x2b510bbdcd7b34cf},
+	}
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := value.FNVDigest(value.NewValueInterface(tt.v)); got != tt.expected {
+				t.Errorf("expected digest %#016x, got %#016x", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFNVDigestMatchesAcrossBackings(t *testing.T) {
+	type container struct {
+		Name  string  `json:"name"`
+		Ports []int64 `json:"ports"`
+	}
+	reflected := struct {
+		Containers []container `json:"containers"`
+		Replicas   int32       `json:"replicas"`
+	}{
+		Containers: []container{{Name: "c", Ports: []int64{80, 443}}},
+		Replicas:   3,
+	}
+	fromJSON, err := value.FromJSON([]byte(`{"replicas": 3, "containers": [{"ports": [80, 443], "name": "c"}]}`))
+	if err != nil {
+		t.Fatalf("failed to parse json: %v", err)
+	}
+	expected := value.FNVDigest(fromJSON)
+	rv, err := value.NewValueReflect(&reflected)
+	if err != nil {
+		t.Fatalf("failed to reflect: %v", err)
+	}
+	if got := value.FNVDigest(rv); got != expected {
+		t.Errorf("expected reflected digest %#016x, got %#016x", expected, got)
+	}
+	if got := value.FNVDigest(value.Freeze(fromJSON)); got != expected {
+		t.Errorf("expected frozen digest %#016x, got %#016x", expected, got)
+	}
+}
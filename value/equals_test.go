@@ -86,3 +86,129 @@ func BenchmarkEquals(b *testing.B) {
 		})
 	}
 }
+
+func TestEqualsIgnoresKeyOrder(t *testing.T) {
+	docs := []string{
+		`{"a": 1, "b": {"c": [{"d": "x", "e": "y"}], "f": {"g": true, "h": null}}, "i": 1.5}`,
+		`{"i": 1.5, "b": {"f": {"h": null, "g": true}, "c": [{"e": "y", "d": "x"}]}, "a": 1}`,
+		`{"b": {"f": {"g": true, "h": null}, "c": [{"d": "x", "e": "y"}]}, "i": 1.5, "a": 1}`,
+	}
+	backings := map[string]func(t *testing.T, doc string) value.Value{
+		"json": func(t *testing.T, doc string) value.Value {
+			v, err := value.FromJSON([]byte(doc))
+			if err != nil {
+				t.Fatalf("failed to parse %v: %v", doc, err)
+			}
+			return v
+		},
+		"yaml": func(t *testing.T, doc string) value.Value {
+			var obj interface{}
+			if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+				t.Fatalf("failed to parse %v: %v", doc, err)
+			}
+			return value.NewValueInterface(obj)
+		},
+		"frozen": func(t *testing.T, doc string) value.Value {
+			v, err := value.FromJSON([]byte(doc))
+			if err != nil {
+				t.Fatalf("failed to parse %v: %v", doc, err)
+			}
+			return value.Freeze(v)
+		},
+		"reflect": func(t *testing.T, doc string) value.Value {
+			v, err := value.FromJSON([]byte(doc))
+			if err != nil {
+				t.Fatalf("failed to parse %v: %v", doc, err)
+			}
+			m := v.Unstructured().(map[string]interface{})
+			rv, err := value.NewValueReflect(&m)
+			if err != nil {
+				t.Fatalf("failed to reflect %v: %v", doc, err)
+			}
+			return rv
+		},
+	}
+	for lname, lhsOf := range backings {
+		for rname, rhsOf := range backings {
+			t.Run(lname+"-"+rname, func(t *testing.T) {
+				for _, ldoc := range docs {
+					for _, rdoc := range docs {
+						lhs, rhs := lhsOf(t, ldoc), rhsOf(t, rdoc)
+						if !value.Equals(lhs, rhs) {
+							t.Errorf("expected %v to equal %v", ldoc, rdoc)
+						}
+						if c := value.Compare(lhs, rhs); c != 0 {
+							t.Errorf("expected %v to compare equal to %v, got %v", ldoc, rdoc, c)
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+type nestedObject struct {
+	Name     string            `json:"name"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Children []nestedObject    `json:"children,omitempty"`
+	Next     *nestedObject     `json:"next,omitempty"`
+}
+
+func deeplyNestedObject(depth int) *nestedObject {
+	obj := &nestedObject{Name: "leaf"}
+	for i := 0; i < depth; i++ {
+		obj = &nestedObject{
+			Name:     "node",
+			Labels:   map[string]string{"depth": string(rune('a' + i%26)), "kind": "node"},
+			Children: []nestedObject{{Name: "first"}, {Name: "second", Labels: map[string]string{"x": "y"}}},
+			Next:     obj,
+		}
+	}
+	return obj
+}
+
+func BenchmarkEqualsDeeplyNested(b *testing.B) {
+	obj := deeplyNestedObject(32)
+	lhs, err := value.NewValueReflect(obj)
+	if err != nil {
+		b.Fatal(err)
+	}
+	// Compare against an unstructured copy, since two reflect-backed values
+	// can be compared with reflect.DeepEqual.
+	rhs := value.NewValueInterface(lhs.Unstructured())
+
+	b.Run("Equals", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if !value.Equals(lhs, rhs) {
+				b.Fatalf("Object should be equal")
+			}
+		}
+	})
+	b.Run("EqualsUsingFreelist", func(b *testing.B) {
+		b.ReportAllocs()
+		a := value.NewFreelistAllocator()
+		for i := 0; i < b.N; i++ {
+			if !value.EqualsUsing(a, lhs, rhs) {
+				b.Fatalf("Object should be equal")
+			}
+		}
+	})
+	b.Run("Compare", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if value.Compare(lhs, rhs) != 0 {
+				b.Fatalf("Object should be equal")
+			}
+		}
+	})
+	b.Run("CompareUsingFreelist", func(b *testing.B) {
+		b.ReportAllocs()
+		a := value.NewFreelistAllocator()
+		for i := 0; i < b.N; i++ {
+			if value.CompareUsing(a, lhs, rhs) != 0 {
+				b.Fatalf("Object should be equal")
+			}
+		}
+	})
+}
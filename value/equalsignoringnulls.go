@@ -0,0 +1,53 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value
+
+// EqualsIgnoringNulls is like Equals, but map entries whose value is null
+// are treated as absent, at any depth: {"a": null, "b": 1} is equal to
+// {"b": 1}. Null items of lists are still compared.
+func EqualsIgnoringNulls(lhs, rhs Value) bool {
+	switch {
+	case lhs.IsMap() && rhs.IsMap():
+		lm, rm := lhs.AsMap(), rhs.AsMap()
+		return containsIgnoringNulls(lm, rm) && containsIgnoringNulls(rm, lm)
+	case lhs.IsList() && rhs.IsList():
+		ll, rl := lhs.AsList(), rhs.AsList()
+		if ll.Length() != rl.Length() {
+			return false
+		}
+		for i := 0; i < ll.Length(); i++ {
+			if !EqualsIgnoringNulls(ll.At(i), rl.At(i)) {
+				return false
+			}
+		}
+		return true
+	default:
+		return Equals(lhs, rhs)
+	}
+}
+
+// containsIgnoringNulls returns true if every non-null entry of lhs is also
+// in rhs, with an equal value.
+func containsIgnoringNulls(lhs, rhs Map) bool {
+	return lhs.Iterate(func(key string, l Value) bool {
+		if l.IsNull() {
+			return true
+		}
+		r, ok := rhs.Get(key)
+		return ok && EqualsIgnoringNulls(l, r)
+	})
+}
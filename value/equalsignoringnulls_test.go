@@ -0,0 +1,75 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+func TestEqualsIgnoringNulls(t *testing.T) {
+	table := []struct {
+		name     string
+		lhs      string
+		rhs      string
+		expected bool
+	}{
+		{
+			name:     "null-field",
+			lhs:      `{"a": null, "b": 1}`,
+			rhs:      `{"b": 1}`,
+			expected: true,
+		}, {
+			name:     "null-fields-on-both-sides",
+			lhs:      `{"a": null, "b": 1}`,
+			rhs:      `{"b": 1, "c": null}`,
+			expected: true,
+		}, {
+			name:     "nested-null-field",
+			lhs:      `{"a": {"b": null, "c": [{"d": null, "e": 2}]}}`,
+			rhs:      `{"a": {"c": [{"e": 2}]}}`,
+			expected: true,
+		}, {
+			name:     "different-value",
+			lhs:      `{"a": null, "b": 1}`,
+			rhs:      `{"b": 2}`,
+			expected: false,
+		}, {
+			name:     "null-instead-of-value",
+			lhs:      `{"a": null, "b": 1}`,
+			rhs:      `{"a": 1, "b": 1}`,
+			expected: false,
+		}, {
+			name:     "null-list-item",
+			lhs:      `{"a": [null, 1]}`,
+			rhs:      `{"a": [1]}`,
+			expected: false,
+		},
+	}
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			lhs, rhs := parseValue(t, tt.lhs), parseValue(t, tt.rhs)
+			if got := value.EqualsIgnoringNulls(lhs, rhs); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+			if got := value.EqualsIgnoringNulls(rhs, lhs); got != tt.expected {
+				t.Errorf("expected %v when reversed, got %v", tt.expected, got)
+			}
+		})
+	}
+}
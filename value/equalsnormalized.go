@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value
+
+import (
+	"sort"
+)
+
+// EqualsNormalized is like Equals, but lists whose path is in listKeyFns are
+// compared regardless of the order of their items: items of both lists are
+// sorted by the key returned by the list's function before being compared.
+// Lists are named by the field names leading to them joined with dots,
+// ignoring list items, as in fieldpath.Set.Rename: "spec.containers" names
+// the containers list, and "spec.containers.ports" the ports list of every
+// container.
+func EqualsNormalized(lhs, rhs Value, listKeyFns map[string]func(Value) string) bool {
+	return equalsNormalized("", lhs, rhs, listKeyFns)
+}
+
+func equalsNormalized(path string, lhs, rhs Value, listKeyFns map[string]func(Value) string) bool {
+	switch {
+	case lhs.IsMap() && rhs.IsMap():
+		lm, rm := lhs.AsMap(), rhs.AsMap()
+		if lm.Length() != rm.Length() {
+			return false
+		}
+		return lm.Iterate(func(key string, l Value) bool {
+			r, ok := rm.Get(key)
+			if !ok {
+				return false
+			}
+			if path == "" {
+				return equalsNormalized(key, l, r, listKeyFns)
+			}
+			return equalsNormalized(path+"."+key, l, r, listKeyFns)
+		})
+	case lhs.IsList() && rhs.IsList():
+		ll, rl := lhs.AsList(), rhs.AsList()
+		if ll.Length() != rl.Length() {
+			return false
+		}
+		litems, ritems := listItems(ll), listItems(rl)
+		if keyFn, ok := listKeyFns[path]; ok {
+			sortByKey(litems, keyFn)
+			sortByKey(ritems, keyFn)
+		}
+		for i := range litems {
+			if !equalsNormalized(path, litems[i], ritems[i], listKeyFns) {
+				return false
+			}
+		}
+		return true
+	default:
+		return Equals(lhs, rhs)
+	}
+}
+
+func listItems(l List) []Value {
+	items := make([]Value, l.Length())
+	for i := range items {
+		items[i] = l.At(i)
+	}
+	return items
+}
+
+func sortByKey(items []Value, keyFn func(Value) string) {
+	keys := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = keyFn(item)
+	}
+	sort.Stable(byKey{items: items, keys: keys})
+}
+
+// byKey sorts items by their precomputed keys.
+type byKey struct {
+	items []Value
+	keys  []string
+}
+
+func (b byKey) Len() int           { return len(b.items) }
+func (b byKey) Less(i, j int) bool { return b.keys[i] < b.keys[j] }
+func (b byKey) Swap(i, j int) {
+	b.items[i], b.items[j] = b.items[j], b.items[i]
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+}
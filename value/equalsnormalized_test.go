@@ -0,0 +1,114 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+func byName(v value.Value) string {
+	if !v.IsMap() {
+		return ""
+	}
+	name, ok := v.AsMap().Get("name")
+	if !ok || !name.IsString() {
+		return ""
+	}
+	return name.AsString()
+}
+
+func TestEqualsNormalized(t *testing.T) {
+	lhs := parseValue(t, `{
+  "spec": {
+    "containers": [
+      {"name": "a", "ports": [80, 443]},
+      {"name": "b", "ports": [8080]}
+    ],
+    "args": ["x", "y"]
+  }
+}`)
+	table := []struct {
+		name       string
+		rhs        string
+		listKeyFns map[string]func(value.Value) string
+		expected   bool
+	}{
+		{
+			name: "reordered-normalized",
+			rhs: `{"spec": {"containers": [
+  {"name": "b", "ports": [8080]},
+  {"name": "a", "ports": [80, 443]}
+], "args": ["x", "y"]}}`,
+			listKeyFns: map[string]func(value.Value) string{"spec.containers": byName},
+			expected:   true,
+		}, {
+			name: "reordered-not-normalized",
+			rhs: `{"spec": {"containers": [
+  {"name": "b", "ports": [8080]},
+  {"name": "a", "ports": [80, 443]}
+], "args": ["x", "y"]}}`,
+			expected: false,
+		}, {
+			name: "reordered-other-list",
+			rhs: `{"spec": {"containers": [
+  {"name": "a", "ports": [80, 443]},
+  {"name": "b", "ports": [8080]}
+], "args": ["y", "x"]}}`,
+			listKeyFns: map[string]func(value.Value) string{"spec.containers": byName},
+			expected:   false,
+		}, {
+			name: "reordered-nested",
+			rhs: `{"spec": {"containers": [
+  {"name": "b", "ports": [8080]},
+  {"name": "a", "ports": [443, 80]}
+], "args": ["x", "y"]}}`,
+			listKeyFns: map[string]func(value.Value) string{
+				"spec.containers":       byName,
+				"spec.containers.ports": value.ToString,
+			},
+			expected: true,
+		}, {
+			name: "different-item",
+			rhs: `{"spec": {"containers": [
+  {"name": "b", "ports": [8081]},
+  {"name": "a", "ports": [80, 443]}
+], "args": ["x", "y"]}}`,
+			listKeyFns: map[string]func(value.Value) string{"spec.containers": byName},
+			expected:   false,
+		}, {
+			name: "missing-item",
+			rhs: `{"spec": {"containers": [
+  {"name": "a", "ports": [80, 443]}
+], "args": ["x", "y"]}}`,
+			listKeyFns: map[string]func(value.Value) string{"spec.containers": byName},
+			expected:   false,
+		},
+	}
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			rhs := parseValue(t, tt.rhs)
+			if got := value.EqualsNormalized(lhs, rhs, tt.listKeyFns); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+			if got := value.EqualsNormalized(rhs, lhs, tt.listKeyFns); got != tt.expected {
+				t.Errorf("expected %v when reversed, got %v", tt.expected, got)
+			}
+		})
+	}
+}
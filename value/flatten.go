@@ -0,0 +1,201 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Flatten returns an entry for every scalar leaf of v, keyed by the string
+// representation of its path (as formatted by fieldpath.Path.String):
+// ".field" for map fields and "[index]" for list items. Empty maps and
+// lists are leaves too, so that Unflatten can restore them. A scalar v is
+// returned under the empty path.
+//
+// Paths are not escaped, map keys that contain "." or "[" can't be
+// unflattened.
+func Flatten(v Value) map[string]Value {
+	flat := map[string]Value{}
+	flatten(v, "", flat)
+	return flat
+}
+
+func flatten(v Value, path string, flat map[string]Value) {
+	switch {
+	case v.IsMap() && v.AsMap().Length() > 0:
+		v.AsMap().Iterate(func(key string, child Value) bool {
+			flatten(child, path+"."+key, flat)
+			return true
+		})
+	case v.IsList() && v.AsList().Length() > 0:
+		l := v.AsList()
+		for i := 0; i < l.Length(); i++ {
+			flatten(l.At(i), path+"["+strconv.Itoa(i)+"]", flat)
+		}
+	default:
+		// Values passed to Iterate may be reused, keep a copy.
+		flat[path] = NewValueInterface(v.Unstructured())
+	}
+}
+
+// Unflatten is the inverse of Flatten. It returns an error if a path can't
+// be parsed, if two paths disagree on the type of a value, or if a list
+// misses some indices.
+func Unflatten(flat map[string]Value) (Value, error) {
+	paths := make([]string, 0, len(flat))
+	for path := range flat {
+		paths = append(paths, path)
+	}
+	// Sorted for deterministic errors.
+	sort.Strings(paths)
+
+	root := &flatNode{}
+	for _, path := range paths {
+		tokens, err := parseFlatPath(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := root.insert(tokens, flat[path]); err != nil {
+			return nil, fmt.Errorf("%q: %v", path, err)
+		}
+	}
+	u, err := root.unstructured("")
+	if err != nil {
+		return nil, err
+	}
+	return NewValueInterface(u), nil
+}
+
+// flatToken is a map field or a list index of a flattened path.
+type flatToken struct {
+	field string
+	index int
+	list  bool
+}
+
+func parseFlatPath(path string) ([]flatToken, error) {
+	tokens := []flatToken{}
+	for rest := path; len(rest) > 0; {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end == -1 {
+				end = len(rest) - 1
+			}
+			tokens = append(tokens, flatToken{field: rest[1 : end+1]})
+			rest = rest[end+1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid path %q: unterminated index", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid path %q: invalid index %q", path, rest[1:end])
+			}
+			tokens = append(tokens, flatToken{index: index, list: true})
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid path %q: expected '.' or '['", path)
+		}
+	}
+	return tokens, nil
+}
+
+// flatNode is a value being rebuilt by Unflatten. At most one of leaf,
+// fields and items is set.
+type flatNode struct {
+	leaf   Value
+	fields map[string]*flatNode
+	items  map[int]*flatNode
+}
+
+func (n *flatNode) empty() bool {
+	return n.leaf == nil && n.fields == nil && n.items == nil
+}
+
+func (n *flatNode) insert(tokens []flatToken, v Value) error {
+	if len(tokens) == 0 {
+		if !n.empty() {
+			return fmt.Errorf("value conflicts with another path")
+		}
+		n.leaf = v
+		return nil
+	}
+	var child *flatNode
+	token := tokens[0]
+	if token.list {
+		if n.leaf != nil || n.fields != nil {
+			return fmt.Errorf("list index [%d] conflicts with another path", token.index)
+		}
+		if n.items == nil {
+			n.items = map[int]*flatNode{}
+		}
+		if child = n.items[token.index]; child == nil {
+			child = &flatNode{}
+			n.items[token.index] = child
+		}
+	} else {
+		if n.leaf != nil || n.items != nil {
+			return fmt.Errorf("field %q conflicts with another path", token.field)
+		}
+		if n.fields == nil {
+			n.fields = map[string]*flatNode{}
+		}
+		if child = n.fields[token.field]; child == nil {
+			child = &flatNode{}
+			n.fields[token.field] = child
+		}
+	}
+	return child.insert(tokens[1:], v)
+}
+
+func (n *flatNode) unstructured(path string) (interface{}, error) {
+	switch {
+	case n.fields != nil:
+		m := make(map[string]interface{}, len(n.fields))
+		for key, child := range n.fields {
+			u, err := child.unstructured(path + "." + key)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = u
+		}
+		return m, nil
+	case n.items != nil:
+		l := make([]interface{}, len(n.items))
+		for i := range l {
+			child, ok := n.items[i]
+			if !ok {
+				return nil, fmt.Errorf("%q: missing list index [%d]", path, i)
+			}
+			u, err := child.unstructured(path + "[" + strconv.Itoa(i) + "]")
+			if err != nil {
+				return nil, err
+			}
+			l[i] = u
+		}
+		return l, nil
+	case n.leaf != nil:
+		return n.leaf.Unstructured(), nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+func TestFlatten(t *testing.T) {
+	v := parseValue(t, `{
+  "name": "a",
+  "spec": {
+    "replicas": 3,
+    "paused": false,
+    "containers": [{"name": "c", "ports": [80, 443]}, {"name": "d", "args": []}],
+    "labels": {}
+  },
+  "status": null
+}`)
+	expected := map[string]string{
+		".name":                        `"a"`,
+		".spec.replicas":               `3`,
+		".spec.paused":                 `false`,
+		".spec.containers[0].name":     `"c"`,
+		".spec.containers[0].ports[0]": `80`,
+		".spec.containers[0].ports[1]": `443`,
+		".spec.containers[1].name":     `"d"`,
+		".spec.containers[1].args":     `[]`,
+		".spec.labels":                 `{}`,
+		".status":                      `null`,
+	}
+	flat := value.Flatten(v)
+	if len(flat) != len(expected) {
+		t.Errorf("expected %v entries, got %v", len(expected), len(flat))
+	}
+	for path, s := range expected {
+		got, ok := flat[path]
+		if !ok {
+			t.Errorf("missing entry for %v", path)
+			continue
+		}
+		if !value.Equals(got, parseValue(t, s)) {
+			t.Errorf("expected %v at %v, got %v", s, path, value.ToString(got))
+		}
+	}
+
+	unflattened, err := value.Unflatten(flat)
+	if err != nil {
+		t.Fatalf("failed to unflatten: %v", err)
+	}
+	if !value.Equals(v, unflattened) {
+		t.Errorf("expected round trip to return\n%v\ngot\n%v", value.ToString(v), value.ToString(unflattened))
+	}
+}
+
+func TestFlattenScalar(t *testing.T) {
+	v := value.NewValueInterface("scalar")
+	unflattened, err := value.Unflatten(value.Flatten(v))
+	if err != nil {
+		t.Fatalf("failed to unflatten: %v", err)
+	}
+	if !value.Equals(v, unflattened) {
+		t.Errorf("expected %v, got %v", value.ToString(v), value.ToString(unflattened))
+	}
+}
+
+func TestUnflattenErrors(t *testing.T) {
+	table := []map[string]value.Value{
+		{"name": value.NewValueInterface("a")},
+		{".list[x]": value.NewValueInterface("a")},
+		{".list[0": value.NewValueInterface("a")},
+		{".list[1]": value.NewValueInterface("a")},
+		{".a": value.NewValueInterface("a"), ".a.b": value.NewValueInterface("b")},
+		{".a[0]": value.NewValueInterface("a"), ".a.b": value.NewValueInterface("b")},
+	}
+	for _, flat := range table {
+		if v, err := value.Unflatten(flat); err == nil {
+			t.Errorf("expected an error unflattening %v, got %v", flat, value.ToString(v))
+		}
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value
+
+import (
+	"errors"
+)
+
+// FromJSONC reads a JSON document that may contain comments, either line
+// comments starting with "//" or block comments enclosed in "/*" and "*/".
+// Comments are replaced with whitespace before the document is read with
+// FromJSONFast, so the positions in parsing errors are preserved. Other
+// JSON5 extensions, like trailing commas, are not supported.
+func FromJSONC(input []byte) (Value, error) {
+	stripped, err := stripJSONComments(input)
+	if err != nil {
+		return nil, err
+	}
+	return FromJSONFast(stripped)
+}
+
+// stripJSONComments returns a copy of input with the comments outside of
+// string literals replaced with spaces. Newlines are kept.
+func stripJSONComments(input []byte) ([]byte, error) {
+	out := make([]byte, len(input))
+	copy(out, input)
+	inString := false
+	for i := 0; i < len(out); i++ {
+		switch c := out[i]; {
+		case inString:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			out[i], out[i+1] = ' ', ' '
+			for i += 2; ; i++ {
+				if i+1 >= len(out) {
+					return nil, errors.New("unterminated block comment")
+				}
+				if out[i] == '*' && out[i+1] == '/' {
+					out[i], out[i+1] = ' ', ' '
+					i++
+					break
+				}
+				if out[i] != '\n' {
+					out[i] = ' '
+				}
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+func TestFromJSONC(t *testing.T) {
+	table := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "no-comments",
+			input:    `{"a": [1, 2]}`,
+			expected: `{"a": [1, 2]}`,
+		}, {
+			name: "line-comments",
+			input: `// leading
+{
+  "a": 1, // trailing
+  // own line
+  "b": 2
+}
+// at the end`,
+			expected: `{"a": 1, "b": 2}`,
+		}, {
+			name: "block-comments",
+			input: `/* leading */ {"a": /* inline */ 1, /* multi
+line */ "b": [/**/2]} /* at the end */`,
+			expected: `{"a": 1, "b": [2]}`,
+		}, {
+			name:     "comments-in-strings",
+			input:    `{"a": "http://example.com", "b": "/* not a comment */", "c": "// nor this"}`,
+			expected: `{"a": "http://example.com", "b": "/* not a comment */", "c": "// nor this"}`,
+		}, {
+			name:     "escaped-quotes",
+			input:    `{"a": "quote \" // still in string", "b\\": 1} // comment`,
+			expected: `{"a": "quote \" // still in string", "b\\": 1}`,
+		},
+	}
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := value.FromJSONC([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			expected, err := value.FromJSON([]byte(tt.expected))
+			if err != nil {
+				t.Fatalf("failed to parse expected value: %v", err)
+			}
+			if !value.Equals(got, expected) {
+				t.Errorf("expected %v, got %v", value.ToString(expected), value.ToString(got))
+			}
+		})
+	}
+}
+
+func TestFromJSONCErrors(t *testing.T) {
+	for _, input := range []string{
+		`{"a": 1} /* unterminated`,
+		// Trailing commas are not supported.
+		`{"a": [1, 2,]}`,
+		`{"a": 1,}`,
+	} {
+		if v, err := value.FromJSONC([]byte(input)); err == nil {
+			t.Errorf("expected an error parsing %q, got %v", input, value.ToString(v))
+		}
+	}
+}
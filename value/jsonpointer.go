@@ -0,0 +1,149 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parsePointer splits a JSON Pointer (RFC 6901) into its unescaped
+// reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must be empty or start with '/'", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		// Order matters: "~01" must unescape to "~1", not "/".
+		tokens[i] = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+// pointerIndex parses a list index token. It returns -1 for the "-" token,
+// which refers to the (nonexistent) element after the last one.
+func pointerIndex(token string, length int) (int, error) {
+	if token == "-" {
+		return -1, nil
+	}
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid list index %q", token)
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 {
+		return 0, fmt.Errorf("invalid list index %q", token)
+	}
+	if i >= length {
+		return 0, fmt.Errorf("list index %v out of range (length %v)", i, length)
+	}
+	return i, nil
+}
+
+// GetPointer returns the value referenced by the given JSON Pointer (RFC
+// 6901) in root, or false if the pointer is invalid or doesn't reference
+// anything.
+func GetPointer(root Value, pointer string) (Value, bool) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, false
+	}
+	v := root
+	for _, token := range tokens {
+		switch {
+		case v.IsMap():
+			child, ok := v.AsMap().Get(token)
+			if !ok {
+				return nil, false
+			}
+			v = child
+		case v.IsList():
+			l := v.AsList()
+			i, err := pointerIndex(token, l.Length())
+			if err != nil || i < 0 {
+				return nil, false
+			}
+			v = l.At(i)
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// SetPointer sets the value referenced by the given JSON Pointer (RFC 6901)
+// in root to v, and returns the new root. The last token of the pointer may
+// reference a new map key, or "-" to append v to a list. Maps are modified
+// in place, while lists are copied, so callers must always use the returned
+// root.
+func SetPointer(root Value, pointer string, v Value) (Value, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	return setPointer(root, tokens, v, "")
+}
+
+func setPointer(cur Value, tokens []string, v Value, prefix string) (Value, error) {
+	if len(tokens) == 0 {
+		return v, nil
+	}
+	token, rest := tokens[0], tokens[1:]
+	path := prefix + "/" + strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+	switch {
+	case cur.IsMap():
+		m := cur.AsMap()
+		child, ok := m.Get(token)
+		if !ok && len(rest) > 0 {
+			return nil, fmt.Errorf("%v: not found", path)
+		}
+		child, err := setPointer(child, rest, v, path)
+		if err != nil {
+			return nil, err
+		}
+		m.Set(token, child)
+		return cur, nil
+	case cur.IsList():
+		l := cur.AsList()
+		i, err := pointerIndex(token, l.Length())
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", path, err)
+		}
+		if i < 0 && len(rest) > 0 {
+			return nil, fmt.Errorf("%v: can't reference past the end of the list", path)
+		}
+		items := make([]interface{}, l.Length(), l.Length()+1)
+		for j := range items {
+			items[j] = l.At(j).Unstructured()
+		}
+		if i < 0 {
+			items = append(items, v.Unstructured())
+			return NewValueInterface(items), nil
+		}
+		child, err := setPointer(l.At(i), rest, v, path)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = child.Unstructured()
+		return NewValueInterface(items), nil
+	}
+	return nil, fmt.Errorf("%v: can't reference a child of %v", path, ToString(cur))
+}
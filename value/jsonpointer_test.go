@@ -0,0 +1,130 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+	yaml "sigs.k8s.io/yaml/goyaml.v2"
+)
+
+func parseValue(t *testing.T, s string) value.Value {
+	t.Helper()
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("failed to parse %q: %v", s, err)
+	}
+	return value.NewValueInterface(v)
+}
+
+const pointerDocument = `{
+  "foo": ["bar", "baz"],
+  "": 0,
+  "a/b": 1,
+  "m~n": 8,
+  "nested": {"list": [{"name": "a"}, {"name": "b", "x~/y": true}]}
+}`
+
+func TestGetPointer(t *testing.T) {
+	root := parseValue(t, pointerDocument)
+	table := []struct {
+		pointer  string
+		expected string
+	}{
+		{"", pointerDocument},
+		{"/foo", `["bar", "baz"]`},
+		{"/foo/0", `"bar"`},
+		{"/foo/1", `"baz"`},
+		{"/", `0`},
+		{"/a~1b", `1`},
+		{"/m~0n", `8`},
+		{"/nested/list/1/name", `"b"`},
+		{"/nested/list/1/x~0~1y", `true`},
+	}
+	for _, tt := range table {
+		got, ok := value.GetPointer(root, tt.pointer)
+		if !ok {
+			t.Errorf("%q: expected to find %v", tt.pointer, tt.expected)
+			continue
+		}
+		if expected := parseValue(t, tt.expected); !value.Equals(got, expected) {
+			t.Errorf("%q: expected %v, got %v", tt.pointer, value.ToString(expected), value.ToString(got))
+		}
+	}
+
+	for _, pointer := range []string{
+		"foo",
+		"/missing",
+		"/foo/2",
+		"/foo/-",
+		"/foo/01",
+		"/foo/bar",
+		"/a~1b/c",
+	} {
+		if got, ok := value.GetPointer(root, pointer); ok {
+			t.Errorf("%q: expected no value, got %v", pointer, value.ToString(got))
+		}
+	}
+}
+
+func TestSetPointer(t *testing.T) {
+	table := []struct {
+		pointer  string
+		value    string
+		expected string
+	}{
+		{"", `1`, `1`},
+		{"/foo/1", `"qux"`, `{"foo": ["bar", "qux"], "nested": {"list": [{"name": "a"}]}}`},
+		{"/foo/-", `"qux"`, `{"foo": ["bar", "baz", "qux"], "nested": {"list": [{"name": "a"}]}}`},
+		{"/new", `{"a": 1}`, `{"foo": ["bar", "baz"], "new": {"a": 1}, "nested": {"list": [{"name": "a"}]}}`},
+		{"/a~1b", `2`, `{"foo": ["bar", "baz"], "a/b": 2, "nested": {"list": [{"name": "a"}]}}`},
+		{"/nested/list/0/name", `"z"`, `{"foo": ["bar", "baz"], "nested": {"list": [{"name": "z"}]}}`},
+		{"/nested/list/-", `{"name": "b"}`, `{"foo": ["bar", "baz"], "nested": {"list": [{"name": "a"}, {"name": "b"}]}}`},
+	}
+	for _, tt := range table {
+		root := parseValue(t, `{"foo": ["bar", "baz"], "nested": {"list": [{"name": "a"}]}}`)
+		got, err := value.SetPointer(root, tt.pointer, parseValue(t, tt.value))
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.pointer, err)
+			continue
+		}
+		if expected := parseValue(t, tt.expected); !value.Equals(got, expected) {
+			t.Errorf("%q: expected %v, got %v", tt.pointer, value.ToString(expected), value.ToString(got))
+		}
+		if strings.HasSuffix(tt.pointer, "/-") {
+			continue
+		}
+		if v, ok := value.GetPointer(got, tt.pointer); !ok || !value.Equals(v, parseValue(t, tt.value)) {
+			t.Errorf("%q: expected pointer to reference the new value", tt.pointer)
+		}
+	}
+
+	for _, pointer := range []string{
+		"foo",
+		"/missing/a",
+		"/foo/2",
+		"/foo/-/a",
+		"/foo/0/a",
+	} {
+		root := parseValue(t, `{"foo": ["bar", "baz"]}`)
+		if _, err := value.SetPointer(root, pointer, parseValue(t, `1`)); err == nil {
+			t.Errorf("%q: expected error", pointer)
+		}
+	}
+}
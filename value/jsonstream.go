@@ -0,0 +1,116 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value
+
+import (
+	"io"
+	"sort"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// streamFlushThreshold is the number of buffered bytes after which
+// ToJSONStream flushes to the underlying writer.
+const streamFlushThreshold = 32 * 1024
+
+// ToJSONStream writes v as JSON to w. Unlike ToJSON, maps and lists are
+// written element by element and the output is flushed periodically, so
+// the whole document is never buffered in memory. The output is
+// identical to ToJSON's.
+func ToJSONStream(v Value, w io.Writer) error {
+	stream := writePool.BorrowStream(w)
+	defer writePool.ReturnStream(stream)
+	a := NewFreelistAllocator()
+	if err := writeJSONUsing(a, v, stream, true); err != nil {
+		return err
+	}
+	return stream.Flush()
+}
+
+// writeJSONUsing writes v to stream without converting the values that
+// aren't already unstructured, such as reflected values, to a copy with
+// Unstructured first: their maps and lists are written element by element
+// instead. Byte slices are still written as the base64 string they are
+// converted to, once. If flush is true, unstructured values are walked too,
+// and the stream is flushed whenever it has buffered streamFlushThreshold
+// bytes. The walk stops at the first error of the stream.
+func writeJSONUsing(a Allocator, v Value, stream *jsoniter.Stream, flush bool) error {
+	switch {
+	case !flush && isUnstructured(v):
+		stream.WriteVal(v.Unstructured())
+	case v.IsMap():
+		m := v.AsMapUsing(a)
+		defer a.Free(m)
+		keys := m.Keys()
+		// Keys are sorted so that the output doesn't depend on the
+		// iteration order of the map.
+		sort.Strings(keys)
+		stream.WriteObjectStart()
+		for i, key := range keys {
+			if i > 0 {
+				stream.WriteMore()
+			}
+			writeObjectField(stream, key)
+			child, _ := m.GetUsing(a, key)
+			err := writeJSONUsing(a, child, stream, flush)
+			a.Free(child)
+			if err != nil {
+				return err
+			}
+		}
+		stream.WriteObjectEnd()
+	case v.IsList():
+		l := v.AsListUsing(a)
+		defer a.Free(l)
+		stream.WriteArrayStart()
+		for i := 0; i < l.Length(); i++ {
+			if i > 0 {
+				stream.WriteMore()
+			}
+			child := l.AtUsing(a, i)
+			err := writeJSONUsing(a, child, stream, flush)
+			a.Free(child)
+			if err != nil {
+				return err
+			}
+		}
+		stream.WriteArrayEnd()
+	default:
+		stream.WriteVal(v.Unstructured())
+	}
+	if stream.Error != nil {
+		return stream.Error
+	}
+	if flush && stream.Buffered() >= streamFlushThreshold {
+		return stream.Flush()
+	}
+	return nil
+}
+
+// writeObjectField writes the key of an object field to stream, escaped
+// like values are, e.g. HTML characters, unlike Stream.WriteObjectField.
+func writeObjectField(stream *jsoniter.Stream, key string) {
+	stream.WriteVal(key)
+	stream.WriteRaw(":")
+}
+
+// isUnstructured returns true if v is backed by an unstructured value,
+// which Unstructured returns as is.
+func isUnstructured(v Value) bool {
+	_, ok := v.(*valueUnstructured)
+	return ok
+}
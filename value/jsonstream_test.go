@@ -0,0 +1,256 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// largeList returns an EndpointSlice-like object with n endpoints.
+func largeList(n int) value.Value {
+	endpoints := make([]interface{}, n)
+	for i := range endpoints {
+		endpoints[i] = map[string]interface{}{
+			"addresses":  []interface{}{fmt.Sprintf("10.0.%d.%d", i/256, i%256)},
+			"conditions": map[string]interface{}{"ready": i%2 == 0},
+			"nodeName":   fmt.Sprintf("node-%d", i),
+			"weight":     float64(i) / 3,
+		}
+	}
+	return value.NewValueInterface(map[string]interface{}{
+		"apiVersion": "discovery.k8s.io/v1",
+		"kind":       "EndpointSlice",
+		"endpoints":  endpoints,
+		"ports":      []interface{}{map[string]interface{}{"port": int64(443)}},
+		"empty":      map[string]interface{}{},
+		"none":       nil,
+	})
+}
+
+func TestToJSONStream(t *testing.T) {
+	for _, v := range []value.Value{
+		value.NewValueInterface(nil),
+		value.NewValueInterface("string"),
+		value.NewValueInterface([]interface{}{}),
+		parseValue(t, pointerDocument),
+		largeList(10000),
+	} {
+		expected, err := value.ToJSON(v)
+		if err != nil {
+			t.Fatalf("failed to serialize: %v", err)
+		}
+		var buf bytes.Buffer
+		if err := value.ToJSONStream(v, &buf); err != nil {
+			t.Fatalf("failed to stream: %v", err)
+		}
+		if !bytes.Equal(buf.Bytes(), expected) {
+			t.Errorf("expected streamed output to match ToJSON:\n%s\ngot:\n%s", expected, buf.Bytes())
+		}
+	}
+}
+
+// flushCounter counts the writes it receives.
+type flushCounter struct {
+	writes int
+}
+
+func (f *flushCounter) Write(p []byte) (int, error) {
+	f.writes++
+	return len(p), nil
+}
+
+func TestToJSONStreamFlushes(t *testing.T) {
+	var w flushCounter
+	if err := value.ToJSONStream(largeList(10000), &w); err != nil {
+		t.Fatalf("failed to stream: %v", err)
+	}
+	if w.writes < 2 {
+		t.Errorf("expected the output to be flushed incrementally, got %v writes", w.writes)
+	}
+}
+
+func BenchmarkToJSONStream(b *testing.B) {
+	v := largeList(10000)
+	b.Run("Buffered", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			data, err := value.ToJSON(v)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := ioutil.Discard.Write(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Stream", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := value.ToJSONStream(v, ioutil.Discard); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+type podContainerPort struct {
+	Name          string `json:"name,omitempty"`
+	ContainerPort int32  `json:"containerPort"`
+	Protocol      string `json:"protocol,omitempty"`
+}
+
+type podEnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+}
+
+type podResources struct {
+	Limits   map[string]string `json:"limits,omitempty"`
+	Requests map[string]string `json:"requests,omitempty"`
+}
+
+type podContainer struct {
+	Name      string             `json:"name"`
+	Image     string             `json:"image,omitempty"`
+	Args      []string           `json:"args,omitempty"`
+	Ports     []podContainerPort `json:"ports,omitempty"`
+	Env       []podEnvVar        `json:"env,omitempty"`
+	Resources podResources       `json:"resources,omitempty"`
+	Stdin     bool               `json:"stdin,omitempty"`
+}
+
+type podSpec struct {
+	Containers                    []podContainer    `json:"containers"`
+	NodeSelector                  map[string]string `json:"nodeSelector,omitempty"`
+	ServiceAccountName            string            `json:"serviceAccountName,omitempty"`
+	TerminationGracePeriodSeconds *int64            `json:"terminationGracePeriodSeconds,omitempty"`
+	Priority                      *int32            `json:"priority,omitempty"`
+	Overhead                      map[string]string `json:"overhead,omitempty"`
+	Payload                       []byte            `json:"payload,omitempty"`
+}
+
+// typicalPodSpec returns a reflected pod spec with a few containers.
+func typicalPodSpec() value.Value {
+	grace := int64(30)
+	spec := &podSpec{
+		NodeSelector:                  map[string]string{"kubernetes.io/os": "linux", "zone": "a"},
+		ServiceAccountName:            "default",
+		TerminationGracePeriodSeconds: &grace,
+		Payload:                       []byte("<binary payload>"),
+	}
+	for i := 0; i < 3; i++ {
+		spec.Containers = append(spec.Containers, podContainer{
+			Name:  fmt.Sprintf("container-%d", i),
+			Image: "registry.k8s.io/app:v1.2.3",
+			Args:  []string{"--port=8080", "--verbose", "--config=/etc/app/<config>.yaml"},
+			Ports: []podContainerPort{
+				{Name: "http", ContainerPort: 8080, Protocol: "TCP"},
+				{Name: "metrics", ContainerPort: 9090},
+			},
+			Env: []podEnvVar{
+				{Name: "POD_NAME", Value: "pod"},
+				{Name: "LOG_LEVEL", Value: "info"},
+				{Name: "EMPTY"},
+			},
+			Resources: podResources{
+				Limits:   map[string]string{"cpu": "1", "memory": "1Gi"},
+				Requests: map[string]string{"cpu": "100m", "memory": "128Mi"},
+			},
+		})
+	}
+	v, err := value.NewValueReflect(spec)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func TestToJSONReflect(t *testing.T) {
+	for _, v := range []value.Value{
+		typicalPodSpec(),
+		value.Freeze(typicalPodSpec()),
+	} {
+		got, err := value.ToJSON(v)
+		if err != nil {
+			t.Fatalf("failed to serialize: %v", err)
+		}
+		expected, err := value.ToJSON(value.NewValueInterface(v.Unstructured()))
+		if err != nil {
+			t.Fatalf("failed to serialize: %v", err)
+		}
+		if !bytes.Equal(got, expected) {
+			t.Errorf("expected output to match the unstructured value's:\n%s\ngot:\n%s", expected, got)
+		}
+	}
+	// Keys are HTML-escaped like values are.
+	htmlKeys, err := value.NewValueReflect(&map[string]string{"<a>&": "<b>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []value.Value{htmlKeys, value.NewValueInterface(htmlKeys.Unstructured())} {
+		var buf bytes.Buffer
+		if err := value.ToJSONStream(v, &buf); err != nil {
+			t.Fatalf("failed to stream: %v", err)
+		}
+		got, err := value.ToJSON(v)
+		if err != nil {
+			t.Fatalf("failed to serialize: %v", err)
+		}
+		expected := `{"\u003ca\u003e\u0026":"\u003cb\u003e"}`
+		if string(got) != expected {
+			t.Errorf("expected %v, got %s", expected, got)
+		}
+		if buf.String() != expected {
+			t.Errorf("expected streamed %v, got %s", expected, buf.Bytes())
+		}
+	}
+
+	// Byte slices are base64 encoded once, like encoding/json does.
+	payload, _ := typicalPodSpec().AsMap().Get("payload")
+	got, err := value.ToJSON(payload)
+	if err != nil {
+		t.Fatalf("failed to serialize: %v", err)
+	}
+	if expected := `"PGJpbmFyeSBwYXlsb2FkPg=="`; string(got) != expected {
+		t.Errorf("expected %v, got %s", expected, got)
+	}
+}
+
+func BenchmarkToJSONReflect(b *testing.B) {
+	v := typicalPodSpec()
+	b.Run("Unstructured", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := value.ToJSON(value.NewValueInterface(v.Unstructured())); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Direct", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := value.ToJSON(v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -22,6 +22,7 @@ import (
 
 type listReflect struct {
 	Value reflect.Value
+	mode  reflectMode
 }
 
 func (r listReflect) Length() int {
@@ -31,12 +32,12 @@ func (r listReflect) Length() int {
 
 func (r listReflect) At(i int) Value {
 	val := r.Value
-	return mustWrapValueReflect(val.Index(i), nil, nil)
+	return mustWrapValueReflect(val.Index(i), nil, nil, r.mode)
 }
 
 func (r listReflect) AtUsing(a Allocator, i int) Value {
 	val := r.Value
-	return a.allocValueReflect().mustReuse(val.Index(i), nil, nil, nil)
+	return a.allocValueReflect().mustReuse(val.Index(i), nil, nil, nil, r.mode)
 }
 
 func (r listReflect) Unstructured() interface{} {
@@ -61,6 +62,7 @@ func (r listReflect) RangeUsing(a Allocator) ListRange {
 	rr.list = r.Value
 	rr.i = -1
 	rr.entry = TypeReflectEntryOf(r.Value.Type().Elem())
+	rr.mode = r.mode
 	return rr
 }
 
@@ -79,6 +81,7 @@ type listReflectRange struct {
 	vr    *valueReflect
 	i     int
 	entry *TypeReflectCacheEntry
+	mode  reflectMode
 }
 
 func (r *listReflectRange) Next() bool {
@@ -94,5 +97,5 @@ func (r *listReflectRange) Item() (index int, value Value) {
 		panic("Item() called on ListRange with no more items")
 	}
 	v := r.list.Index(r.i)
-	return r.i, r.vr.mustReuse(v, r.entry, nil, nil)
+	return r.i, r.vr.mustReuse(v, r.entry, nil, nil, r.mode)
 }
@@ -51,6 +51,8 @@ type Map interface {
 	IterateUsing(Allocator, func(key string, value Value) bool) bool
 	// Length returns the number of items in the map.
 	Length() int
+	// Keys returns the keys that Iterate visits, in no particular order.
+	Keys() []string
 	// Empty returns true if the map is empty.
 	Empty() bool
 	// Zip iterates over the entries of two maps together. If both maps contain a value for a given key, fn is called
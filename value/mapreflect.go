@@ -29,6 +29,18 @@ func (r mapReflect) Length() int {
 	return val.Len()
 }
 
+func (r mapReflect) Keys() []string {
+	keys := make([]string, 0, r.Value.Len())
+	iter := r.Value.MapRange()
+	for iter.Next() {
+		if !iter.Value().IsValid() {
+			continue
+		}
+		keys = append(keys, iter.Key().String())
+	}
+	return keys
+}
+
 func (r mapReflect) Empty() bool {
 	val := r.Value
 	return val.Len() == 0
@@ -43,7 +55,7 @@ func (r mapReflect) GetUsing(a Allocator, key string) (Value, bool) {
 	if !ok {
 		return nil, false
 	}
-	return a.allocValueReflect().mustReuse(v, nil, &r.Value, &k), true
+	return a.allocValueReflect().mustReuse(v, nil, &r.Value, &k, r.mode), true
 }
 
 func (r mapReflect) get(k string) (key, value reflect.Value, ok bool) {
@@ -62,10 +74,16 @@ func (r mapReflect) Has(key string) bool {
 }
 
 func (r mapReflect) Set(key string, val Value) {
+	if r.mode&reflectReadOnly != 0 {
+		panic("Set called on a read-only reflected map")
+	}
 	r.Value.SetMapIndex(r.toMapKey(key), reflect.ValueOf(val.Unstructured()))
 }
 
 func (r mapReflect) Delete(key string) {
+	if r.mode&reflectReadOnly != 0 {
+		panic("Delete called on a read-only reflected map")
+	}
 	val := r.Value
 	val.SetMapIndex(r.toMapKey(key), reflect.Value{})
 }
@@ -87,7 +105,7 @@ func (r mapReflect) IterateUsing(a Allocator, fn func(string, Value) bool) bool
 	v := a.allocValueReflect()
 	defer a.Free(v)
 	return eachMapEntry(r.Value, func(e *TypeReflectCacheEntry, key reflect.Value, value reflect.Value) bool {
-		return fn(key.String(), v.mustReuse(value, e, &r.Value, &key))
+		return fn(key.String(), v.mustReuse(value, e, &r.Value, &key, r.mode))
 	})
 }
 
@@ -131,12 +149,12 @@ func (r mapReflect) EqualsUsing(a Allocator, m Map) bool {
 	vr := a.allocValueReflect()
 	defer a.Free(vr)
 	entry := TypeReflectEntryOf(r.Value.Type().Elem())
-	return m.Iterate(func(key string, value Value) bool {
+	return m.IterateUsing(a, func(key string, value Value) bool {
 		_, lhsVal, ok := r.get(key)
 		if !ok {
 			return false
 		}
-		return EqualsUsing(a, vr.mustReuse(lhsVal, entry, nil, nil), value)
+		return EqualsUsing(a, vr.mustReuse(lhsVal, entry, nil, nil, r.mode), value)
 	})
 }
 
@@ -179,11 +197,11 @@ func (r mapReflect) unorderedReflectZip(a Allocator, other *mapReflect, fn func(
 			if !next.IsValid() {
 				continue
 			}
-			rhsVal := vrhs.mustReuse(next, rhsEntry, &rhs, &key)
+			rhsVal := vrhs.mustReuse(next, rhsEntry, &rhs, &key, other.mode)
 			visited[keyString] = struct{}{}
 			var lhsVal Value
 			if _, v, ok := r.get(keyString); ok {
-				lhsVal = vlhs.mustReuse(v, lhsEntry, &lhs, &key)
+				lhsVal = vlhs.mustReuse(v, lhsEntry, &lhs, &key, r.mode)
 			}
 			if !fn(keyString, lhsVal, rhsVal) {
 				return false
@@ -201,7 +219,7 @@ func (r mapReflect) unorderedReflectZip(a Allocator, other *mapReflect, fn func(
 		if !next.IsValid() {
 			continue
 		}
-		if !fn(key.String(), vlhs.mustReuse(next, lhsEntry, &lhs, &key), nil) {
+		if !fn(key.String(), vlhs.mustReuse(next, lhsEntry, &lhs, &key, r.mode), nil) {
 			return false
 		}
 	}
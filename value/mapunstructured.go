@@ -69,6 +69,16 @@ func (m mapUnstructuredInterface) Length() int {
 	return len(m)
 }
 
+func (m mapUnstructuredInterface) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		if ks, ok := k.(string); ok {
+			keys = append(keys, ks)
+		}
+	}
+	return keys
+}
+
 func (m mapUnstructuredInterface) Empty() bool {
 	return len(m) == 0
 }
@@ -153,6 +163,14 @@ func (m mapUnstructuredString) Length() int {
 	return len(m)
 }
 
+func (m mapUnstructuredString) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
 func (m mapUnstructuredString) Equals(other Map) bool {
 	return m.EqualsUsing(HeapAllocator, other)
 }
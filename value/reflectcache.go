@@ -18,6 +18,7 @@ package value
 
 import (
 	"bytes"
+	"encoding"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -39,6 +40,17 @@ type UnstructuredConverter interface {
 	ToUnstructured() interface{}
 }
 
+// FromUnstructuredConverter defines how a type can be converted directly from unstructured.
+// Types that implement json.Unmarshaler may also optionally implement this interface to provide a more
+// direct and more efficient conversion. All types that choose to implement this interface must still
+// implement this same conversion via json.Unmarshaler.
+type FromUnstructuredConverter interface {
+	json.Unmarshaler // require that json.Unmarshaler is implemented
+
+	// FromUnstructured sets the value from its unstructured representation.
+	FromUnstructured(interface{}) error
+}
+
 // TypeReflectCacheEntry keeps data gathered using reflection about how a type is converted to/from unstructured.
 type TypeReflectCacheEntry struct {
 	isJsonMarshaler        bool
@@ -48,6 +60,16 @@ type TypeReflectCacheEntry struct {
 	isStringConvertable    bool
 	ptrIsStringConvertable bool
 
+	isFromUnstructuredConverter    bool
+	ptrIsFromUnstructuredConverter bool
+
+	// Types that are neither JSON marshalers nor unmarshalers are
+	// converted to and from strings by their text marshalers, like
+	// encoding/json does.
+	isTextMarshaler    bool
+	ptrIsTextMarshaler bool
+	isTextUnmarshaler  bool
+
 	structFields        map[string]*FieldCacheEntry
 	orderedStructFields []*FieldCacheEntry
 }
@@ -84,6 +106,9 @@ func (f *FieldCacheEntry) GetFrom(structVal reflect.Value) reflect.Value {
 var marshalerType = reflect.TypeOf(new(json.Marshaler)).Elem()
 var unmarshalerType = reflect.TypeOf(new(json.Unmarshaler)).Elem()
 var unstructuredConvertableType = reflect.TypeOf(new(UnstructuredConverter)).Elem()
+var fromUnstructuredConverterType = reflect.TypeOf(new(FromUnstructuredConverter)).Elem()
+var textMarshalerType = reflect.TypeOf(new(encoding.TextMarshaler)).Elem()
+var textUnmarshalerType = reflect.TypeOf(new(encoding.TextUnmarshaler)).Elem()
 var defaultReflectCache = newReflectCache()
 
 // TypeReflectEntryOf returns the TypeReflectCacheEntry of the provided reflect.Type.
@@ -115,6 +140,13 @@ func typeReflectEntryOf(cm reflectCacheMap, t reflect.Type, updates reflectCache
 		isJsonUnmarshaler:      reflect.PtrTo(t).Implements(unmarshalerType),
 		isStringConvertable:    t.Implements(unstructuredConvertableType),
 		ptrIsStringConvertable: reflect.PtrTo(t).Implements(unstructuredConvertableType),
+
+		isFromUnstructuredConverter:    t.Implements(fromUnstructuredConverterType),
+		ptrIsFromUnstructuredConverter: reflect.PtrTo(t).Implements(fromUnstructuredConverterType),
+
+		isTextMarshaler:    t.Implements(textMarshalerType),
+		ptrIsTextMarshaler: reflect.PtrTo(t).Implements(textMarshalerType),
+		isTextUnmarshaler:  reflect.PtrTo(t).Implements(textUnmarshalerType),
 	}
 	if t.Kind() == reflect.Struct {
 		fieldEntries := map[string]*FieldCacheEntry{}
@@ -178,7 +210,8 @@ func (e TypeReflectCacheEntry) OrderedFields() []*FieldCacheEntry {
 
 // CanConvertToUnstructured returns true if this TypeReflectCacheEntry can convert values of its type to unstructured.
 func (e TypeReflectCacheEntry) CanConvertToUnstructured() bool {
-	return e.isJsonMarshaler || e.ptrIsJsonMarshaler || e.isStringConvertable || e.ptrIsStringConvertable
+	return e.isJsonMarshaler || e.ptrIsJsonMarshaler || e.isStringConvertable || e.ptrIsStringConvertable ||
+		e.isTextMarshaler || e.ptrIsTextMarshaler
 }
 
 // ToUnstructured converts the provided value to unstructured and returns it.
@@ -255,19 +288,39 @@ func (e TypeReflectCacheEntry) ToUnstructured(sv reflect.Value) (interface{}, er
 			}
 		}
 	}
+	if marshaler, ok := e.getTextMarshaler(sv); ok {
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return string(text), nil
+	}
 
 	return nil, fmt.Errorf("provided type cannot be converted: %v", sv.Type())
 }
 
 // CanConvertFromUnstructured returns true if this TypeReflectCacheEntry can convert objects of the type from unstructured.
 func (e TypeReflectCacheEntry) CanConvertFromUnstructured() bool {
-	return e.isJsonUnmarshaler
+	return e.isJsonUnmarshaler || e.isTextUnmarshaler
 }
 
 // FromUnstructured converts the provided source value from unstructured into the provided destination value.
 func (e TypeReflectCacheEntry) FromUnstructured(sv, dv reflect.Value) error {
-	// TODO: this could be made much more efficient using direct conversions like
-	// UnstructuredConverter.ToUnstructured provides.
+	// Check if the object has a custom unstructured converter and use it if available, since it is much more
+	// efficient than round tripping through json.
+	if converter, ok := e.getFromUnstructuredConverter(dv); ok {
+		return converter.FromUnstructured(sv.Interface())
+	}
+	if !e.isJsonUnmarshaler && e.isTextUnmarshaler {
+		switch text := sv.Interface().(type) {
+		case nil:
+			// Like encoding/json, null leaves the value unchanged.
+			return nil
+		case string:
+			return dv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text))
+		}
+		return fmt.Errorf("unable to unmarshal %v into %v, expected a string", sv.Type(), dv.Type())
+	}
 	st := dv.Type()
 	data, err := json.Marshal(sv.Interface())
 	if err != nil {
@@ -299,6 +352,20 @@ func (e TypeReflectCacheEntry) getJsonMarshaler(v reflect.Value) (json.Marshaler
 	return nil, false
 }
 
+func (e TypeReflectCacheEntry) getTextMarshaler(v reflect.Value) (encoding.TextMarshaler, bool) {
+	if e.isTextMarshaler {
+		return v.Interface().(encoding.TextMarshaler), true
+	}
+	if e.ptrIsTextMarshaler {
+		// Check pointer receivers if v is not a pointer
+		if v.Kind() != reflect.Ptr && v.CanAddr() {
+			v = v.Addr()
+			return v.Interface().(encoding.TextMarshaler), true
+		}
+	}
+	return nil, false
+}
+
 func (e TypeReflectCacheEntry) getJsonUnmarshaler(v reflect.Value) (json.Unmarshaler, bool) {
 	if !e.isJsonUnmarshaler {
 		return nil, false
@@ -320,6 +387,20 @@ func (e TypeReflectCacheEntry) getUnstructuredConverter(v reflect.Value) (Unstru
 	return nil, false
 }
 
+func (e TypeReflectCacheEntry) getFromUnstructuredConverter(v reflect.Value) (FromUnstructuredConverter, bool) {
+	if e.isFromUnstructuredConverter {
+		return v.Interface().(FromUnstructuredConverter), true
+	}
+	if e.ptrIsFromUnstructuredConverter {
+		// Check pointer receivers if v is not a pointer
+		if v.CanAddr() {
+			v = v.Addr()
+			return v.Interface().(FromUnstructuredConverter), true
+		}
+	}
+	return nil, false
+}
+
 type typeReflectCache struct {
 	// use an atomic and copy-on-write since there are a fixed (typically very small) number of structs compiled into any
 	// go program using this cache
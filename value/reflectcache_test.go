@@ -57,6 +57,62 @@ func (t Time) ToUnstructured() interface{} {
 	return string(buf)
 }
 
+// FromUnstructured implements the value.FromUnstructuredConverter interface.
+func (t *Time) FromUnstructured(u interface{}) error {
+	switch u := u.(type) {
+	case nil:
+		t.Time = time.Time{}
+		return nil
+	case string:
+		parsed, err := time.Parse(time.RFC3339, u)
+		if err != nil {
+			return err
+		}
+		t.Time = parsed.Local()
+		return nil
+	}
+	return fmt.Errorf("unexpected type %T for time", u)
+}
+
+// JSONTime is converted from unstructured through its UnmarshalJSON only.
+type JSONTime struct {
+	time.Time
+}
+
+// Protocol only implements the text marshalers.
+type Protocol int
+
+func (p Protocol) MarshalText() ([]byte, error) {
+	switch p {
+	case 0:
+		return []byte("TCP"), nil
+	case 1:
+		return []byte("UDP"), nil
+	}
+	return nil, fmt.Errorf("unknown protocol %d", int(p))
+}
+
+func (p *Protocol) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "TCP":
+		*p = 0
+	case "UDP":
+		*p = 1
+	default:
+		return fmt.Errorf("unknown protocol %q", text)
+	}
+	return nil
+}
+
+// Level implements TextMarshaler with a pointer receiver.
+type Level struct {
+	name string
+}
+
+func (l *Level) MarshalText() ([]byte, error) {
+	return []byte(l.name), nil
+}
+
 func TestToUnstructured(t *testing.T) {
 	testcases := []struct {
 		Data                 string
@@ -142,6 +198,133 @@ func TestTimeToUnstructured(t *testing.T) {
 	}
 }
 
+func TestTimeFromUnstructured(t *testing.T) {
+	expected := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, tc := range []struct {
+		Name         string
+		Unstructured interface{}
+		Expected     time.Time
+		ExpectError  bool
+	}{
+		{Name: "nil", Unstructured: nil},
+		{Name: "string", Unstructured: "2020-01-02T03:04:05Z", Expected: expected},
+		{Name: "invalid", Unstructured: "yesterday", ExpectError: true},
+		{Name: "int", Unstructured: int64(1), ExpectError: true},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			dv := reflect.New(reflect.TypeOf(Time{})).Elem()
+			err := TypeReflectEntryOf(dv.Type()).FromUnstructured(reflect.ValueOf(&tc.Unstructured).Elem(), dv)
+			if tc.ExpectError {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", dv.Interface())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := dv.Interface().(Time); !got.Equal(tc.Expected) {
+				t.Errorf("expected %v but got %v", tc.Expected, got)
+			}
+		})
+	}
+}
+
+func TestTextMarshaler(t *testing.T) {
+	udp := Protocol(1)
+	level := &Level{name: "debug"}
+	for _, tc := range []struct {
+		Name     string
+		Value    reflect.Value
+		Expected interface{}
+	}{
+		{Name: "value", Value: reflect.ValueOf(udp), Expected: "UDP"},
+		{Name: "pointer", Value: reflect.ValueOf(&udp), Expected: "UDP"},
+		{Name: "nil", Value: reflect.ValueOf((*Protocol)(nil)), Expected: nil},
+		{Name: "pointer-receiver", Value: reflect.ValueOf(level).Elem(), Expected: "debug"},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			entry := TypeReflectEntryOf(tc.Value.Type())
+			if !entry.CanConvertToUnstructured() {
+				t.Fatalf("expected %v to be convertible to unstructured", tc.Value.Type())
+			}
+			result, err := entry.ToUnstructured(tc.Value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result, tc.Expected) {
+				t.Errorf("expected %#v but got %#v", tc.Expected, result)
+			}
+		})
+	}
+
+	v, err := NewValueReflect(&struct {
+		Protocol Protocol `json:"protocol"`
+	}{Protocol: udp})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := v.AsMap().Get("protocol"); !ok || !got.IsString() || got.AsString() != "UDP" {
+		t.Errorf("expected the protocol field to be converted to %q, got %v", "UDP", got)
+	}
+
+	entry := TypeReflectEntryOf(reflect.TypeOf(udp))
+	if !entry.CanConvertFromUnstructured() {
+		t.Fatalf("expected %v to be convertible from unstructured", reflect.TypeOf(udp))
+	}
+	for _, tc := range []struct {
+		Name         string
+		Unstructured interface{}
+		Expected     Protocol
+		ExpectError  bool
+	}{
+		{Name: "string", Unstructured: "UDP", Expected: udp},
+		{Name: "nil", Unstructured: nil},
+		{Name: "unknown", Unstructured: "SCTP", ExpectError: true},
+		{Name: "int", Unstructured: int64(1), ExpectError: true},
+	} {
+		t.Run("from-"+tc.Name, func(t *testing.T) {
+			dv := reflect.New(reflect.TypeOf(udp)).Elem()
+			err := entry.FromUnstructured(reflect.ValueOf(&tc.Unstructured).Elem(), dv)
+			if tc.ExpectError {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", dv.Interface())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := dv.Interface().(Protocol); got != tc.Expected {
+				t.Errorf("expected %v but got %v", tc.Expected, got)
+			}
+		})
+	}
+}
+
+func BenchmarkFromUnstructuredTime(b *testing.B) {
+	sv := reflect.ValueOf("2020-01-02T03:04:05Z")
+	for _, tc := range []struct {
+		Name string
+		Type reflect.Type
+	}{
+		{Name: "json", Type: reflect.TypeOf(JSONTime{})},
+		{Name: "converter", Type: reflect.TypeOf(Time{})},
+	} {
+		b.Run(tc.Name, func(b *testing.B) {
+			entry := TypeReflectEntryOf(tc.Type)
+			dv := reflect.New(tc.Type).Elem()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := entry.FromUnstructured(sv, dv); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
 func TestTypeReflectEntryOf(t *testing.T) {
 	testString := ""
 	tests := map[string]struct {
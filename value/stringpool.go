@@ -0,0 +1,97 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value
+
+import (
+	"sync"
+)
+
+// defaultMaxInternedLength is the length of the longest strings interned
+// by a StringPool created with NewStringPool.
+const defaultMaxInternedLength = 64
+
+// StringPool interns strings so that identical strings share their backing
+// storage. It is safe for concurrent use. Only short strings are interned,
+// since long strings are unlikely to be repeated.
+type StringPool struct {
+	// MaxLength is the length of the longest strings interned.
+	MaxLength int
+
+	lock    sync.Mutex
+	strings map[string]string
+}
+
+// NewStringPool returns an empty pool.
+func NewStringPool() *StringPool {
+	return &StringPool{
+		MaxLength: defaultMaxInternedLength,
+		strings:   map[string]string{},
+	}
+}
+
+// Intern returns a string equal to s, shared with previous calls.
+func (p *StringPool) Intern(s string) string {
+	if len(s) > p.MaxLength {
+		return s
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if interned, ok := p.strings[s]; ok {
+		return interned
+	}
+	p.strings[s] = s
+	return s
+}
+
+// Size returns the number of strings in the pool.
+func (p *StringPool) Size() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return len(p.strings)
+}
+
+// FromJSONInterned is like FromJSON, but the string values and map keys of
+// the document are interned in pool, which reduces the memory used by
+// documents that repeat the same strings, e.g. enum values in long lists.
+// A nil pool doesn't intern anything.
+func FromJSONInterned(input []byte, pool *StringPool) (Value, error) {
+	v, err := FromJSON(input)
+	if err != nil || pool == nil {
+		return v, err
+	}
+	return NewValueInterface(pool.internUnstructured(v.Unstructured())), nil
+}
+
+func (p *StringPool) internUnstructured(v interface{}) interface{} {
+	switch t := v.(type) {
+	case string:
+		return p.Intern(t)
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for key, child := range t {
+			m[p.Intern(key)] = p.internUnstructured(child)
+		}
+		return m
+	case []interface{}:
+		for i := range t {
+			t[i] = p.internUnstructured(t[i])
+		}
+		return t
+	default:
+		return v
+	}
+}
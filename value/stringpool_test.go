@@ -0,0 +1,119 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// enumList returns a JSON list of n objects repeating the same enum values.
+func enumList(n int) []byte {
+	items := make([]string, n)
+	for i := range items {
+		items[i] = fmt.Sprintf(`{"name": "port-%d", "protocol": "TCP", "pullPolicy": "Always", "apiVersion": "v1"}`, i)
+	}
+	return []byte("[" + strings.Join(items, ",") + "]")
+}
+
+// stringData returns the address of the bytes of s.
+func stringData(s string) uintptr {
+	return (*[2]uintptr)(unsafe.Pointer(&s))[0]
+}
+
+func TestFromJSONInterned(t *testing.T) {
+	data := enumList(10)
+	expected, err := value.FromJSON(data)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	pool := value.NewStringPool()
+	got, err := value.FromJSONInterned(data, pool)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if !value.Equals(expected, got) {
+		t.Errorf("expected %v, got %v", value.ToString(expected), value.ToString(got))
+	}
+
+	// 10 names, 3 enum values and 4 keys.
+	if pool.Size() != 17 {
+		t.Errorf("expected 17 strings in the pool, got %v", pool.Size())
+	}
+	list := got.AsList()
+	first, _ := list.At(0).AsMap().Get("protocol")
+	for i := 1; i < list.Length(); i++ {
+		protocol, _ := list.At(i).AsMap().Get("protocol")
+		if stringData(protocol.AsString()) != stringData(first.AsString()) {
+			t.Errorf("expected item %v to share its protocol string", i)
+		}
+	}
+
+	if _, err := value.FromJSONInterned([]byte(`{"a": `), pool); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+	got, err = value.FromJSONInterned(data, nil)
+	if err != nil || !value.Equals(expected, got) {
+		t.Errorf("expected a nil pool to parse normally, got %v, %v", got, err)
+	}
+}
+
+func TestStringPoolMaxLength(t *testing.T) {
+	pool := value.NewStringPool()
+	long := strings.Repeat("a", pool.MaxLength+1)
+	pool.Intern(long)
+	if pool.Size() != 0 {
+		t.Errorf("expected long strings not to be interned, got %v strings", pool.Size())
+	}
+}
+
+func BenchmarkFromJSONInterned(b *testing.B) {
+	data := enumList(1000)
+	b.Run("FromJSON", func(b *testing.B) {
+		b.ReportAllocs()
+		var retained uint64
+		for i := 0; i < b.N; i++ {
+			retained += heapAllocated(func() interface{} {
+				v, err := value.FromJSON(data)
+				if err != nil {
+					b.Fatal(err)
+				}
+				return v
+			})
+		}
+		b.ReportMetric(float64(retained)/float64(b.N), "retained-B/op")
+	})
+	b.Run("FromJSONInterned", func(b *testing.B) {
+		b.ReportAllocs()
+		pool := value.NewStringPool()
+		var retained uint64
+		for i := 0; i < b.N; i++ {
+			retained += heapAllocated(func() interface{} {
+				v, err := value.FromJSONInterned(data, pool)
+				if err != nil {
+					b.Fatal(err)
+				}
+				return v
+			})
+		}
+		b.ReportMetric(float64(retained)/float64(b.N), "retained-B/op")
+	})
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package structpbconv converts values from and to protobuf
+// google.protobuf.Value messages.
+package structpbconv
+
+import (
+	"fmt"
+	"math"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// maxExactFloatInt is the largest integer up to which all the integers are
+// represented exactly by a float64.
+const maxExactFloatInt = 1 << 53
+
+// FromStructpb converts a protobuf google.protobuf.Value to a value. Since
+// numbers are always doubles in protobuf, numbers that are integers of at
+// most 2^53 in absolute value are read as ints, and other numbers as
+// floats. A nil v is null.
+func FromStructpb(v *structpb.Value) (value.Value, error) {
+	u, err := fromStructpb(v)
+	if err != nil {
+		return nil, err
+	}
+	return value.NewValueInterface(u), nil
+}
+
+func fromStructpb(v *structpb.Value) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	switch kind := v.GetKind().(type) {
+	case *structpb.Value_NullValue:
+		return nil, nil
+	case *structpb.Value_BoolValue:
+		return kind.BoolValue, nil
+	case *structpb.Value_StringValue:
+		return kind.StringValue, nil
+	case *structpb.Value_NumberValue:
+		f := kind.NumberValue
+		if f == math.Trunc(f) && math.Abs(f) <= maxExactFloatInt {
+			return int64(f), nil
+		}
+		return f, nil
+	case *structpb.Value_ListValue:
+		values := kind.ListValue.GetValues()
+		l := make([]interface{}, len(values))
+		for i, item := range values {
+			u, err := fromStructpb(item)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %v", i, err)
+			}
+			l[i] = u
+		}
+		return l, nil
+	case *structpb.Value_StructValue:
+		fields := kind.StructValue.GetFields()
+		m := make(map[string]interface{}, len(fields))
+		for key, field := range fields {
+			u, err := fromStructpb(field)
+			if err != nil {
+				return nil, fmt.Errorf(".%s: %v", key, err)
+			}
+			m[key] = u
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("value has no kind")
+}
+
+// ToStructpb converts v to a protobuf google.protobuf.Value. Since numbers
+// are always doubles in protobuf, ints of more than 2^53 in absolute
+// value, which doubles can't represent exactly, are rejected rather than
+// losing precision.
+func ToStructpb(v value.Value) (*structpb.Value, error) {
+	switch {
+	case v.IsNull():
+		return structpb.NewNullValue(), nil
+	case v.IsBool():
+		return structpb.NewBoolValue(v.AsBool()), nil
+	case v.IsInt():
+		i := v.AsInt()
+		if i > maxExactFloatInt || i < -maxExactFloatInt {
+			return nil, fmt.Errorf("integer %d can't be represented exactly as a double", i)
+		}
+		return structpb.NewNumberValue(float64(i)), nil
+	case v.IsFloat():
+		return structpb.NewNumberValue(v.AsFloat()), nil
+	case v.IsString():
+		return structpb.NewStringValue(v.AsString()), nil
+	case v.IsList():
+		list := v.AsList()
+		values := make([]*structpb.Value, list.Length())
+		for i := range values {
+			item, err := ToStructpb(list.At(i))
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %v", i, err)
+			}
+			values[i] = item
+		}
+		return structpb.NewListValue(&structpb.ListValue{Values: values}), nil
+	case v.IsMap():
+		m := v.AsMap()
+		fields := make(map[string]*structpb.Value, m.Length())
+		var err error
+		m.Iterate(func(key string, field value.Value) bool {
+			var f *structpb.Value
+			if f, err = ToStructpb(field); err != nil {
+				err = fmt.Errorf(".%s: %v", key, err)
+				return false
+			}
+			fields[key] = f
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		return structpb.NewStructValue(&structpb.Struct{Fields: fields}), nil
+	}
+	return nil, fmt.Errorf("invalid value: %v", v)
+}
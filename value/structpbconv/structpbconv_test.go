@@ -0,0 +1,108 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package structpbconv_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+	"sigs.k8s.io/structured-merge-diff/v4/value/structpbconv"
+)
+
+func TestStructpbRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   interface{}
+	}{
+		{name: "null", in: nil},
+		{name: "bool", in: true},
+		{name: "string", in: "a"},
+		{name: "int", in: int64(-42)},
+		{name: "float", in: 1.5},
+		{name: "largest exact int", in: int64(1 << 53)},
+		{name: "smallest exact int", in: int64(-1 << 53)},
+		{name: "list", in: []interface{}{int64(1), "a", nil, []interface{}{}}},
+		{name: "map", in: map[string]interface{}{
+			"a": map[string]interface{}{"b": []interface{}{true, 2.5}},
+			"c": nil,
+			"d": map[string]interface{}{},
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			in := value.NewValueInterface(tc.in)
+			pb, err := structpbconv.ToStructpb(in)
+			if err != nil {
+				t.Fatalf("failed to convert to structpb: %v", err)
+			}
+			out, err := structpbconv.FromStructpb(pb)
+			if err != nil {
+				t.Fatalf("failed to convert from structpb: %v", err)
+			}
+			if !value.Equals(in, out) {
+				t.Errorf("expected %v, got %v", value.ToString(in), value.ToString(out))
+			}
+		})
+	}
+}
+
+func TestStructpbIntegers(t *testing.T) {
+	// Integers that doubles can't represent exactly are rejected.
+	for _, i := range []int64{1<<53 + 1, -1<<53 - 1, math.MaxInt64} {
+		_, err := structpbconv.ToStructpb(value.NewValueInterface(map[string]interface{}{"a": []interface{}{i}}))
+		if err == nil || !strings.Contains(err.Error(), ".a: [0]: integer") {
+			t.Errorf("expected an error converting %v, got %v", i, err)
+		}
+	}
+
+	// Integral numbers are read as ints, and other numbers as floats.
+	for _, tc := range []struct {
+		number  float64
+		isInt   bool
+		asInt   int64
+		asFloat float64
+	}{
+		{number: 3, isInt: true, asInt: 3},
+		{number: 1 << 53, isInt: true, asInt: 1 << 53},
+		{number: 1 << 54, asFloat: 1 << 54},
+		{number: 3.5, asFloat: 3.5},
+	} {
+		v, err := structpbconv.FromStructpb(structpb.NewNumberValue(tc.number))
+		if err != nil {
+			t.Fatalf("failed to convert %v: %v", tc.number, err)
+		}
+		if v.IsInt() != tc.isInt {
+			t.Errorf("%v: expected IsInt to be %v", tc.number, tc.isInt)
+		} else if tc.isInt && v.AsInt() != tc.asInt {
+			t.Errorf("%v: expected %v, got %v", tc.number, tc.asInt, v.AsInt())
+		} else if !tc.isInt && v.AsFloat() != tc.asFloat {
+			t.Errorf("%v: expected %v, got %v", tc.number, tc.asFloat, v.AsFloat())
+		}
+	}
+}
+
+func TestFromStructpbNoKind(t *testing.T) {
+	if _, err := structpbconv.FromStructpb(&structpb.Value{}); err == nil {
+		t.Error("expected an error converting a value without kind")
+	}
+	v, err := structpbconv.FromStructpb(nil)
+	if err != nil || !v.IsNull() {
+		t.Errorf("expected a nil value to be null, got %v, %v", v, err)
+	}
+}
@@ -34,6 +34,16 @@ func (r structReflect) Length() int {
 	return i
 }
 
+// Keys returns the JSON names of the fields that aren't omitted.
+func (r structReflect) Keys() []string {
+	var keys []string
+	eachStructField(r.Value, func(_ *TypeReflectCacheEntry, s string, value reflect.Value) bool {
+		keys = append(keys, s)
+		return true
+	})
+	return keys
+}
+
 func (r structReflect) Empty() bool {
 	return eachStructField(r.Value, func(_ *TypeReflectCacheEntry, s string, value reflect.Value) bool {
 		return false // exit early if the struct is non-empty
@@ -46,7 +56,7 @@ func (r structReflect) Get(key string) (Value, bool) {
 
 func (r structReflect) GetUsing(a Allocator, key string) (Value, bool) {
 	if val, ok := r.findJsonNameField(key); ok {
-		return a.allocValueReflect().mustReuse(val, nil, nil, nil), true
+		return a.allocValueReflect().mustReuse(val, nil, nil, nil, r.mode), true
 	}
 	return nil, false
 }
@@ -57,6 +67,9 @@ func (r structReflect) Has(key string) bool {
 }
 
 func (r structReflect) Set(key string, val Value) {
+	if r.mode&reflectReadOnly != 0 {
+		panic("Set called on a read-only reflected struct")
+	}
 	fieldEntry, ok := TypeReflectEntryOf(r.Value.Type()).Fields()[key]
 	if !ok {
 		panic(fmt.Sprintf("key %s may not be set on struct %T: field does not exist", key, r.Value.Interface()))
@@ -67,6 +80,9 @@ func (r structReflect) Set(key string, val Value) {
 }
 
 func (r structReflect) Delete(key string) {
+	if r.mode&reflectReadOnly != 0 {
+		panic("Delete called on a read-only reflected struct")
+	}
 	fieldEntry, ok := TypeReflectEntryOf(r.Value.Type()).Fields()[key]
 	if !ok {
 		panic(fmt.Sprintf("key %s may not be deleted on struct %T: field does not exist", key, r.Value.Interface()))
@@ -109,7 +125,7 @@ func (r structReflect) IterateUsing(a Allocator, fn func(string, Value) bool) bo
 	vr := a.allocValueReflect()
 	defer a.Free(vr)
 	return eachStructField(r.Value, func(e *TypeReflectCacheEntry, s string, value reflect.Value) bool {
-		return fn(s, vr.mustReuse(value, e, nil, nil))
+		return fn(s, vr.mustReuse(value, e, nil, nil, r.mode))
 	})
 }
 
@@ -195,10 +211,10 @@ func (r structReflect) structZip(other *structReflect, lhsvr, rhsvr *valueReflec
 		}
 		var lhsVal, rhsVal Value
 		if !lhsOmit {
-			lhsVal = lhsvr.mustReuse(lhsFieldVal, fieldCacheEntry.TypeEntry, nil, nil)
+			lhsVal = lhsvr.mustReuse(lhsFieldVal, fieldCacheEntry.TypeEntry, nil, nil, r.mode)
 		}
 		if !rhsOmit {
-			rhsVal = rhsvr.mustReuse(rhsFieldVal, fieldCacheEntry.TypeEntry, nil, nil)
+			rhsVal = rhsvr.mustReuse(rhsFieldVal, fieldCacheEntry.TypeEntry, nil, nil, other.mode)
 		}
 		if !fn(fieldCacheEntry.JsonName, lhsVal, rhsVal) {
 			return false
@@ -0,0 +1,57 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value
+
+// AsUnordered returns a copy of v backed by plain unstructured types: maps
+// are map[string]interface{}, lists are []interface{}, and scalars are
+// int64, float64, string, bool or nil, whatever the backing of v (e.g. a
+// reflected struct or a map[interface{}]interface{} decoded from YAML). Maps
+// have no order, ToJSON writes their keys in lexical order.
+func AsUnordered(v Value) Value {
+	return NewValueInterface(unordered(v))
+}
+
+func unordered(v Value) interface{} {
+	switch {
+	case v.IsNull():
+		return nil
+	case v.IsFloat():
+		return v.AsFloat()
+	case v.IsInt():
+		return v.AsInt()
+	case v.IsString():
+		return v.AsString()
+	case v.IsBool():
+		return v.AsBool()
+	case v.IsList():
+		l := v.AsList()
+		out := make([]interface{}, l.Length())
+		for i := range out {
+			out[i] = unordered(l.At(i))
+		}
+		return out
+	case v.IsMap():
+		m := v.AsMap()
+		out := make(map[string]interface{}, m.Length())
+		m.Iterate(func(key string, val Value) bool {
+			out[key] = unordered(val)
+			return true
+		})
+		return out
+	}
+	return v.Unstructured()
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value_test
+
+import (
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+	yaml "sigs.k8s.io/yaml/goyaml.v2"
+)
+
+func TestAsUnordered(t *testing.T) {
+	var fromYAML interface{}
+	if err := yaml.Unmarshal([]byte("b: 1\na: [x, {d: 1.5, c: null}]\n"), &fromYAML); err != nil {
+		t.Fatalf("failed to parse yaml: %v", err)
+	}
+	type item struct {
+		D float64     `json:"d"`
+		C interface{} `json:"c"`
+	}
+	reflected, err := value.NewValueReflect(&struct {
+		B int32         `json:"b"`
+		A []interface{} `json:"a"`
+	}{B: 1, A: []interface{}{"x", item{D: 1.5}}})
+	if err != nil {
+		t.Fatalf("failed to reflect: %v", err)
+	}
+	expected := map[string]interface{}{
+		"a": []interface{}{"x", map[string]interface{}{"c": nil, "d": 1.5}},
+		"b": int64(1),
+	}
+	for name, v := range map[string]value.Value{
+		"yaml":      value.NewValueInterface(fromYAML),
+		"reflected": reflected,
+		"frozen":    value.Freeze(value.NewValueInterface(expected)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			got := value.AsUnordered(v)
+			if !reflect.DeepEqual(got.Unstructured(), expected) {
+				t.Errorf("expected %#v, got %#v", expected, got.Unstructured())
+			}
+			js, err := value.ToJSON(got)
+			if err != nil {
+				t.Fatalf("failed to serialize: %v", err)
+			}
+			if e := `{"a":["x",{"c":null,"d":1.5}],"b":1}`; string(js) != e {
+				t.Errorf("expected %v, got %v", e, string(js))
+			}
+		})
+	}
+}
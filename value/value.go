@@ -49,6 +49,9 @@ type Value interface {
 	// IsString returns true if the Value is a string, false
 	// otherwise.
 	IsString() bool
+	// IsScalar returns true if the Value is a bool, int, float or
+	// string, false otherwise. Null is not a scalar.
+	IsScalar() bool
 	// IsMap returns true if the Value is null, false otherwise.
 	IsNull() bool
 
@@ -93,14 +96,17 @@ func FromJSONFast(input []byte) (Value, error) {
 	return ReadJSONIter(iter)
 }
 
-// ToJSON is a helper function for producing a JSon document.
+// ToJSON is a helper function for producing a JSon document. Maps are
+// unordered, their keys are always written in lexical order.
 func ToJSON(v Value) ([]byte, error) {
 	buf := bytes.Buffer{}
 	stream := writePool.BorrowStream(&buf)
 	defer writePool.ReturnStream(stream)
-	WriteJSONStream(v, stream)
+	err := writeJSONUsing(NewFreelistAllocator(), v, stream, false)
 	b := stream.Buffer()
-	err := stream.Flush()
+	if err == nil {
+		err = stream.Flush()
+	}
 	// Help jsoniter manage its buffers--without this, the next
 	// use of the stream is likely to require an allocation. Look
 	// at the jsoniter stream code to understand why. They were probably
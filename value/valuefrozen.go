@@ -0,0 +1,334 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Freeze returns an immutable copy of v. The copy is compact: maps are
+// stored as sorted parallel slices of keys and values, and all the nodes of
+// a container are stored contiguously rather than individually allocated.
+// Reading a frozen value never allocates, and calling a mutating method (e.g.
+// Map.Set or Map.Delete) on it panics.
+//
+// Freezing a value that is already frozen returns it unchanged.
+func Freeze(v Value) Value {
+	if f, ok := v.(*frozenValue); ok {
+		return f
+	}
+	f := &frozenValue{}
+	f.freeze(HeapAllocator, v)
+	return f
+}
+
+type frozenKind uint8
+
+const (
+	frozenInvalid frozenKind = iota
+	frozenNull
+	frozenBool
+	frozenInt
+	frozenFloat
+	frozenString
+	frozenList
+	frozenMap
+)
+
+// frozenValue is a node of a frozen value. Scalars are stored in str or num
+// (which holds the bits of bools, ints and floats), lists in items, and maps
+// in keys and items, which are parallel slices sorted by key.
+type frozenValue struct {
+	kind  frozenKind
+	str   string
+	num   uint64
+	keys  []string
+	items []frozenValue
+}
+
+func (f *frozenValue) freeze(a Allocator, v Value) {
+	switch {
+	case v.IsNull():
+		f.kind = frozenNull
+	case v.IsFloat():
+		f.kind = frozenFloat
+		f.num = math.Float64bits(v.AsFloat())
+	case v.IsInt():
+		f.kind = frozenInt
+		f.num = uint64(v.AsInt())
+	case v.IsString():
+		f.kind = frozenString
+		f.str = v.AsString()
+	case v.IsBool():
+		f.kind = frozenBool
+		if v.AsBool() {
+			f.num = 1
+		}
+	case v.IsList():
+		f.kind = frozenList
+		l := v.AsListUsing(a)
+		defer a.Free(l)
+		f.items = make([]frozenValue, l.Length())
+		iter := l.RangeUsing(a)
+		defer a.Free(iter)
+		for iter.Next() {
+			i, item := iter.Item()
+			f.items[i].freeze(a, item)
+		}
+	case v.IsMap():
+		f.kind = frozenMap
+		m := v.AsMapUsing(a)
+		defer a.Free(m)
+		f.keys = make([]string, 0, m.Length())
+		m.IterateUsing(a, func(key string, _ Value) bool {
+			f.keys = append(f.keys, key)
+			return true
+		})
+		sort.Strings(f.keys)
+		f.items = make([]frozenValue, len(f.keys))
+		for i, key := range f.keys {
+			item, _ := m.GetUsing(a, key)
+			f.items[i].freeze(a, item)
+			a.Free(item)
+		}
+	}
+}
+
+func (f *frozenValue) IsMap() bool    { return f.kind == frozenMap }
+func (f *frozenValue) IsList() bool   { return f.kind == frozenList }
+func (f *frozenValue) IsBool() bool   { return f.kind == frozenBool }
+func (f *frozenValue) IsInt() bool    { return f.kind == frozenInt }
+func (f *frozenValue) IsFloat() bool  { return f.kind == frozenFloat }
+func (f *frozenValue) IsString() bool { return f.kind == frozenString }
+func (f *frozenValue) IsNull() bool   { return f.kind == frozenNull }
+
+func (f *frozenValue) IsScalar() bool {
+	switch f.kind {
+	case frozenBool, frozenInt, frozenFloat, frozenString:
+		return true
+	}
+	return false
+}
+
+func (f *frozenValue) AsMap() Map {
+	return f.AsMapUsing(HeapAllocator)
+}
+
+func (f *frozenValue) AsMapUsing(_ Allocator) Map {
+	if f.kind != frozenMap {
+		panic(fmt.Errorf("not a map: %v", ToString(f)))
+	}
+	return (*frozenMapValue)(f)
+}
+
+func (f *frozenValue) AsList() List {
+	return f.AsListUsing(HeapAllocator)
+}
+
+func (f *frozenValue) AsListUsing(_ Allocator) List {
+	if f.kind != frozenList {
+		panic(fmt.Errorf("not a list: %v", ToString(f)))
+	}
+	return (*frozenListValue)(f)
+}
+
+func (f *frozenValue) AsBool() bool {
+	if f.kind != frozenBool {
+		panic(fmt.Errorf("not a bool: %v", ToString(f)))
+	}
+	return f.num != 0
+}
+
+func (f *frozenValue) AsInt() int64 {
+	if f.kind != frozenInt {
+		panic(fmt.Errorf("not an int: %v", ToString(f)))
+	}
+	return int64(f.num)
+}
+
+func (f *frozenValue) AsFloat() float64 {
+	if f.kind != frozenFloat {
+		panic(fmt.Errorf("not a float: %v", ToString(f)))
+	}
+	return math.Float64frombits(f.num)
+}
+
+func (f *frozenValue) AsString() string {
+	if f.kind != frozenString {
+		panic(fmt.Errorf("not a string: %v", ToString(f)))
+	}
+	return f.str
+}
+
+// Unstructured returns a new, mutable, copy of the frozen value.
+func (f *frozenValue) Unstructured() interface{} {
+	switch f.kind {
+	case frozenBool:
+		return f.AsBool()
+	case frozenInt:
+		return f.AsInt()
+	case frozenFloat:
+		return f.AsFloat()
+	case frozenString:
+		return f.str
+	case frozenList:
+		out := make([]interface{}, len(f.items))
+		for i := range f.items {
+			out[i] = f.items[i].Unstructured()
+		}
+		return out
+	case frozenMap:
+		out := make(map[string]interface{}, len(f.keys))
+		for i, key := range f.keys {
+			out[key] = f.items[i].Unstructured()
+		}
+		return out
+	}
+	return nil
+}
+
+// frozenMapValue implements Map for a frozen map.
+type frozenMapValue frozenValue
+
+func (m *frozenMapValue) find(key string) (int, bool) {
+	i := sort.SearchStrings(m.keys, key)
+	return i, i < len(m.keys) && m.keys[i] == key
+}
+
+func (m *frozenMapValue) Set(key string, val Value) {
+	panic("Set called on a frozen map")
+}
+
+func (m *frozenMapValue) Delete(key string) {
+	panic("Delete called on a frozen map")
+}
+
+func (m *frozenMapValue) Get(key string) (Value, bool) {
+	return m.GetUsing(HeapAllocator, key)
+}
+
+func (m *frozenMapValue) GetUsing(_ Allocator, key string) (Value, bool) {
+	if i, ok := m.find(key); ok {
+		return &m.items[i], true
+	}
+	return nil, false
+}
+
+func (m *frozenMapValue) Has(key string) bool {
+	_, ok := m.find(key)
+	return ok
+}
+
+func (m *frozenMapValue) Equals(other Map) bool {
+	return m.EqualsUsing(HeapAllocator, other)
+}
+
+func (m *frozenMapValue) EqualsUsing(a Allocator, other Map) bool {
+	return MapEqualsUsing(a, m, other)
+}
+
+// Iterate runs the given function for each key/value in the map, in
+// lexical key order.
+func (m *frozenMapValue) Iterate(fn func(key string, value Value) bool) bool {
+	return m.IterateUsing(HeapAllocator, fn)
+}
+
+func (m *frozenMapValue) IterateUsing(_ Allocator, fn func(key string, value Value) bool) bool {
+	for i, key := range m.keys {
+		if !fn(key, &m.items[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *frozenMapValue) Length() int {
+	return len(m.keys)
+}
+
+// Keys returns a copy of the keys of the map, in lexical order.
+func (m *frozenMapValue) Keys() []string {
+	return append([]string(nil), m.keys...)
+}
+
+func (m *frozenMapValue) Empty() bool {
+	return len(m.keys) == 0
+}
+
+func (m *frozenMapValue) Zip(other Map, order MapTraverseOrder, fn func(key string, lhs, rhs Value) bool) bool {
+	return m.ZipUsing(HeapAllocator, other, order, fn)
+}
+
+func (m *frozenMapValue) ZipUsing(a Allocator, other Map, order MapTraverseOrder, fn func(key string, lhs, rhs Value) bool) bool {
+	return defaultMapZip(a, m, other, order, fn)
+}
+
+// frozenListValue implements List for a frozen list.
+type frozenListValue frozenValue
+
+func (l *frozenListValue) Length() int {
+	return len(l.items)
+}
+
+func (l *frozenListValue) At(i int) Value {
+	return &l.items[i]
+}
+
+func (l *frozenListValue) AtUsing(_ Allocator, i int) Value {
+	return &l.items[i]
+}
+
+func (l *frozenListValue) Range() ListRange {
+	return l.RangeUsing(HeapAllocator)
+}
+
+func (l *frozenListValue) RangeUsing(_ Allocator) ListRange {
+	if len(l.items) == 0 {
+		return EmptyRange
+	}
+	return &frozenListRange{items: l.items, i: -1}
+}
+
+func (l *frozenListValue) Equals(other List) bool {
+	return l.EqualsUsing(HeapAllocator, other)
+}
+
+func (l *frozenListValue) EqualsUsing(a Allocator, other List) bool {
+	return ListEqualsUsing(a, l, other)
+}
+
+type frozenListRange struct {
+	items []frozenValue
+	i     int
+}
+
+func (r *frozenListRange) Next() bool {
+	r.i += 1
+	return r.i < len(r.items)
+}
+
+func (r *frozenListRange) Item() (index int, value Value) {
+	if r.i < 0 {
+		panic("Item() called before first calling Next()")
+	}
+	if r.i >= len(r.items) {
+		panic("Item() called on ListRange with no more items")
+	}
+	return r.i, &r.items[r.i]
+}
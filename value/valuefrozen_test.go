@@ -0,0 +1,196 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value_test
+
+import (
+	"runtime"
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+	yaml "sigs.k8s.io/yaml/goyaml.v2"
+)
+
+var frozenTestdata = []string{
+	"pod.yaml",
+	"endpoints.yaml",
+	"list.yaml",
+	"node.yaml",
+	"prometheus-crd.yaml",
+}
+
+func readUnstructured(t testing.TB, filename string) interface{} {
+	var obj interface{}
+	if err := yaml.Unmarshal(read(testdata(filename)), &obj); err != nil {
+		t.Fatalf("Failed to unmarshal object: %v", err)
+	}
+	return obj
+}
+
+// assertSameReads checks that every read operation returns the same result
+// on both values.
+func assertSameReads(t *testing.T, path string, expected, got value.Value) {
+	t.Helper()
+	if expected.IsNull() != got.IsNull() || expected.IsBool() != got.IsBool() ||
+		expected.IsInt() != got.IsInt() || expected.IsFloat() != got.IsFloat() ||
+		expected.IsString() != got.IsString() || expected.IsList() != got.IsList() ||
+		expected.IsMap() != got.IsMap() || expected.IsScalar() != got.IsScalar() {
+		t.Fatalf("%v: expected %v, got %v", path, value.ToString(expected), value.ToString(got))
+	}
+	switch {
+	case expected.IsBool():
+		if expected.AsBool() != got.AsBool() {
+			t.Errorf("%v: expected %v, got %v", path, expected.AsBool(), got.AsBool())
+		}
+	case expected.IsInt():
+		if expected.AsInt() != got.AsInt() {
+			t.Errorf("%v: expected %v, got %v", path, expected.AsInt(), got.AsInt())
+		}
+	case expected.IsFloat():
+		if expected.AsFloat() != got.AsFloat() {
+			t.Errorf("%v: expected %v, got %v", path, expected.AsFloat(), got.AsFloat())
+		}
+	case expected.IsString():
+		if expected.AsString() != got.AsString() {
+			t.Errorf("%v: expected %q, got %q", path, expected.AsString(), got.AsString())
+		}
+	case expected.IsList():
+		el, gl := expected.AsList(), got.AsList()
+		if el.Length() != gl.Length() {
+			t.Fatalf("%v: expected length %v, got %v", path, el.Length(), gl.Length())
+		}
+		for i := 0; i < el.Length(); i++ {
+			assertSameReads(t, path+"[]", el.At(i), gl.At(i))
+		}
+	case expected.IsMap():
+		em, gm := expected.AsMap(), got.AsMap()
+		if em.Length() != gm.Length() {
+			t.Fatalf("%v: expected length %v, got %v", path, em.Length(), gm.Length())
+		}
+		em.Iterate(func(key string, ev value.Value) bool {
+			if !gm.Has(key) {
+				t.Errorf("%v: missing key %q", path, key)
+				return true
+			}
+			gv, _ := gm.Get(key)
+			assertSameReads(t, path+"."+key, ev, gv)
+			return true
+		})
+		if gm.Has("__missing__") {
+			t.Errorf("%v: unexpected key", path)
+		}
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	for _, filename := range frozenTestdata {
+		filename := filename
+		t.Run(filename, func(t *testing.T) {
+			v := value.NewValueInterface(readUnstructured(t, filename))
+			frozen := value.Freeze(v)
+
+			assertSameReads(t, "", v, frozen)
+			if !value.Equals(v, frozen) || !value.Equals(frozen, v) {
+				t.Errorf("expected frozen value to be equal to the original")
+			}
+			if value.Compare(v, frozen) != 0 {
+				t.Errorf("expected frozen value to compare equal to the original")
+			}
+			expectedJSON, err := value.ToJSON(v)
+			if err != nil {
+				t.Fatalf("failed to serialize original: %v", err)
+			}
+			gotJSON, err := value.ToJSON(frozen)
+			if err != nil {
+				t.Fatalf("failed to serialize frozen value: %v", err)
+			}
+			if string(expectedJSON) != string(gotJSON) {
+				t.Errorf("expected JSON\n%s\ngot\n%s", expectedJSON, gotJSON)
+			}
+			if value.Freeze(frozen) != frozen {
+				t.Errorf("expected freezing a frozen value to be a no-op")
+			}
+		})
+	}
+}
+
+func TestFreezeIsImmutable(t *testing.T) {
+	m := value.Freeze(value.NewValueInterface(map[string]interface{}{"a": int64(1)})).AsMap()
+	for name, mutate := range map[string]func(){
+		"Set":    func() { m.Set("b", value.NewValueInterface(int64(2))) },
+		"Delete": func() { m.Delete("a") },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected %v to panic", name)
+				}
+			}()
+			mutate()
+		}()
+	}
+
+	// Mutating the unstructured copy doesn't affect the frozen value.
+	frozen := value.Freeze(value.NewValueInterface(map[string]interface{}{"a": int64(1)}))
+	frozen.Unstructured().(map[string]interface{})["b"] = int64(2)
+	if frozen.AsMap().Length() != 1 {
+		t.Errorf("expected frozen value to be unchanged, got %v", value.ToString(frozen))
+	}
+}
+
+// heapAllocated returns how many bytes build keeps allocated on the heap.
+func heapAllocated(build func() interface{}) uint64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	v := build()
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	runtime.KeepAlive(v)
+	return after.HeapAlloc - before.HeapAlloc
+}
+
+func BenchmarkFreeze(b *testing.B) {
+	for _, filename := range frozenTestdata {
+		filename := filename
+		b.Run(filename, func(b *testing.B) {
+			data := read(testdata(filename))
+			unstructured := func() interface{} {
+				var obj interface{}
+				if err := yaml.Unmarshal(data, &obj); err != nil {
+					b.Fatalf("Failed to unmarshal object: %v", err)
+				}
+				return value.NewValueInterface(obj)
+			}
+			b.Run("Unstructured", func(b *testing.B) {
+				var retained uint64
+				for i := 0; i < b.N; i++ {
+					retained += heapAllocated(unstructured)
+				}
+				b.ReportMetric(float64(retained)/float64(b.N), "retained-B/op")
+			})
+			b.Run("Frozen", func(b *testing.B) {
+				var retained uint64
+				for i := 0; i < b.N; i++ {
+					v := unstructured().(value.Value)
+					retained += heapAllocated(func() interface{} { return value.Freeze(v) })
+					runtime.KeepAlive(v)
+				}
+				b.ReportMetric(float64(retained)/float64(b.N), "retained-B/op")
+			})
+		})
+	}
+}
@@ -19,15 +19,42 @@ package value
 import (
 	"encoding/base64"
 	"fmt"
+	"math"
 	"reflect"
 )
 
+// ReflectOption changes how NewValueReflect reflects on a value.
+type ReflectOption int
+
+const (
+	// LazyConversion defers the conversion of the values that are converted
+	// to Values by the jsonMarshaler interface until they are accessed,
+	// rather than when they are reached, e.g. when iterating over the fields
+	// of their parent. Errors that occur during a deferred conversion cause
+	// a panic. The root value is always converted right away.
+	//
+	// Since a value converts itself when first read, a lazily reflected
+	// value must not be read from multiple goroutines at once, unlike
+	// other reflected values. Values reflected separately from the same
+	// object may be read concurrently.
+	LazyConversion ReflectOption = iota
+)
+
+// reflectMode holds the options that reflected values pass on to the
+// values reflected from them.
+type reflectMode uint8
+
+const (
+	reflectReadOnly reflectMode = 1 << iota
+	reflectLazy
+)
+
 // NewValueReflect creates a Value backed by an "interface{}" type,
 // typically an structured object in Kubernetes world that is uses reflection to expose.
 // The provided "interface{}" value must be a pointer so that the value can be modified via reflection.
 // The provided "interface{}" may contain structs and types that are converted to Values
 // by the jsonMarshaler interface.
-func NewValueReflect(value interface{}) (Value, error) {
+func NewValueReflect(value interface{}, opts ...ReflectOption) (Value, error) {
 	if value == nil {
 		return NewValueInterface(nil), nil
 	}
@@ -36,20 +63,50 @@ func NewValueReflect(value interface{}) (Value, error) {
 		// The root value to reflect on must be a pointer so that map.Set() and map.Delete() operations are possible.
 		return nil, fmt.Errorf("value provided to NewValueReflect must be a pointer")
 	}
-	return wrapValueReflect(v, nil, nil)
+	var mode reflectMode
+	for _, opt := range opts {
+		switch opt {
+		case LazyConversion:
+			mode |= reflectLazy
+		}
+	}
+	// The root value is converted right away, so that errors are returned.
+	return wrapValueReflectNow(v, nil, nil, nil, mode)
+}
+
+// NewValueReflectReadOnly is like NewValueReflect, but also accepts values
+// that aren't pointers, e.g. a struct passed by value, for callers that only
+// read them. Calling Set or Delete on any map of the returned value panics.
+func NewValueReflectReadOnly(value interface{}) (Value, error) {
+	if value == nil {
+		return NewValueInterface(nil), nil
+	}
+	return wrapValueReflect(reflect.ValueOf(value), nil, nil, reflectReadOnly)
 }
 
 // wrapValueReflect wraps the provide reflect.Value as a value. If parent in the data tree is a map, parentMap
-// and parentMapKey must be provided so that the returned value may be set and deleted.
-func wrapValueReflect(value reflect.Value, parentMap, parentMapKey *reflect.Value) (Value, error) {
+// and parentMapKey must be provided so that the returned value may be set and deleted. The mode is passed on to the
+// values reflected from the returned value.
+func wrapValueReflect(value reflect.Value, parentMap, parentMapKey *reflect.Value, mode reflectMode) (Value, error) {
 	val := HeapAllocator.allocValueReflect()
-	return val.reuse(value, nil, parentMap, parentMapKey)
+	return val.reuse(value, nil, parentMap, parentMapKey, mode)
+}
+
+// wrapValueReflectNow is like wrapValueReflect, but converts the value right away even if the mode includes
+// reflectLazy. The mode is still passed on to the values reflected from the returned value.
+func wrapValueReflectNow(value reflect.Value, cacheEntry *TypeReflectCacheEntry, parentMap, parentMapKey *reflect.Value, mode reflectMode) (*valueReflect, error) {
+	val := HeapAllocator.allocValueReflect()
+	if _, err := val.reuse(value, cacheEntry, parentMap, parentMapKey, mode&^reflectLazy); err != nil {
+		return nil, err
+	}
+	val.mode = mode
+	return val, nil
 }
 
 // wrapValueReflect wraps the provide reflect.Value as a value, and panics if there is an error. If parent in the data
 // tree is a map, parentMap and parentMapKey must be provided so that the returned value may be set and deleted.
-func mustWrapValueReflect(value reflect.Value, parentMap, parentMapKey *reflect.Value) Value {
-	v, err := wrapValueReflect(value, parentMap, parentMapKey)
+func mustWrapValueReflect(value reflect.Value, parentMap, parentMapKey *reflect.Value, mode reflectMode) Value {
+	v, err := wrapValueReflect(value, parentMap, parentMapKey, mode)
 	if err != nil {
 		panic(err)
 	}
@@ -61,11 +118,20 @@ var nilType = reflect.TypeOf(&struct{}{})
 
 // reuse replaces the value of the valueReflect. If parent in the data tree is a map, parentMap and parentMapKey
 // must be provided so that the returned value may be set and deleted.
-func (r *valueReflect) reuse(value reflect.Value, cacheEntry *TypeReflectCacheEntry, parentMap, parentMapKey *reflect.Value) (Value, error) {
+func (r *valueReflect) reuse(value reflect.Value, cacheEntry *TypeReflectCacheEntry, parentMap, parentMapKey *reflect.Value, mode reflectMode) (Value, error) {
 	if cacheEntry == nil {
 		cacheEntry = TypeReflectEntryOf(value.Type())
 	}
 	if cacheEntry.CanConvertToUnstructured() {
+		if mode&reflectLazy != 0 {
+			return &valueLazy{
+				value:        value,
+				entry:        cacheEntry,
+				parentMap:    parentMap,
+				parentMapKey: parentMapKey,
+				mode:         mode,
+			}, nil
+		}
 		u, err := cacheEntry.ToUnstructured(value)
 		if err != nil {
 			return nil, err
@@ -80,13 +146,14 @@ func (r *valueReflect) reuse(value reflect.Value, cacheEntry *TypeReflectCacheEn
 	r.ParentMap = parentMap
 	r.ParentMapKey = parentMapKey
 	r.kind = kind(r.Value)
+	r.mode = mode
 	return r, nil
 }
 
 // mustReuse replaces the value of the valueReflect and panics if there is an error. If parent in the data tree is a
 // map, parentMap and parentMapKey must be provided so that the returned value may be set and deleted.
-func (r *valueReflect) mustReuse(value reflect.Value, cacheEntry *TypeReflectCacheEntry, parentMap, parentMapKey *reflect.Value) Value {
-	v, err := r.reuse(value, cacheEntry, parentMap, parentMapKey)
+func (r *valueReflect) mustReuse(value reflect.Value, cacheEntry *TypeReflectCacheEntry, parentMap, parentMapKey *reflect.Value, mode reflectMode) Value {
+	v, err := r.reuse(value, cacheEntry, parentMap, parentMapKey, mode)
 	if err != nil {
 		panic(err)
 	}
@@ -106,6 +173,9 @@ type valueReflect struct {
 	ParentMapKey *reflect.Value
 	Value        reflect.Value
 	kind         reflectType
+	// mode is passed on to the values reflected from this one.
+	// Maps may not be modified if it includes reflectReadOnly.
+	mode reflectMode
 }
 
 func (r valueReflect) IsMap() bool {
@@ -132,6 +202,14 @@ func (r valueReflect) IsString() bool {
 	return r.kind == stringType || r.kind == byteStringType
 }
 
+func (r valueReflect) IsScalar() bool {
+	switch r.kind {
+	case stringType, byteStringType, intType, uintType, floatType, boolType:
+		return true
+	}
+	return false
+}
+
 func (r valueReflect) IsNull() bool {
 	return r.kind == nullType
 }
@@ -165,7 +243,13 @@ func kind(v reflect.Value) reflectType {
 	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
 		return intType
 	case reflect.Uint, reflect.Uint32, reflect.Uint16, reflect.Uint8:
-		// Uint64 deliberately excluded, see valueUnstructured.Int.
+		return uintType
+	case reflect.Uint64:
+		// Like valueUnstructured, uint64 values that overflow an
+		// int64 are represented as floats.
+		if v.Uint() > math.MaxInt64 {
+			return floatType
+		}
 		return uintType
 	case reflect.Float64, reflect.Float32:
 		return floatType
@@ -229,6 +313,7 @@ func (r valueReflect) AsListUsing(a Allocator) List {
 	if r.IsList() {
 		v := a.allocListReflect()
 		v.Value = r.Value
+		v.mode = r.mode
 		return v
 	}
 	panic("value is not a list")
@@ -254,6 +339,9 @@ func (r valueReflect) AsInt() int64 {
 
 func (r valueReflect) AsFloat() float64 {
 	if r.IsFloat() {
+		if r.Value.Kind() == reflect.Uint64 {
+			return float64(r.Value.Uint())
+		}
 		return r.Value.Float()
 	}
 	panic("value is not a float")
@@ -279,7 +367,7 @@ func (r valueReflect) Unstructured() interface{} {
 	case val.Kind() == reflect.Map:
 		return mapReflect{valueReflect: r}.Unstructured()
 	case r.IsList():
-		return listReflect{r.Value}.Unstructured()
+		return listReflect{Value: r.Value, mode: r.mode}.Unstructured()
 	case r.IsString():
 		return r.AsString()
 	case r.IsInt():
@@ -292,3 +380,98 @@ func (r valueReflect) Unstructured() interface{} {
 		panic(fmt.Sprintf("value of type %s is not a supported by value reflector", val.Type()))
 	}
 }
+
+// valueLazy is a value whose conversion by its jsonMarshaler or
+// UnstructuredConverter was deferred until it is accessed. It is only used
+// for values reflected with LazyConversion, and isn't safe for concurrent
+// reads since it converts itself when first read.
+type valueLazy struct {
+	value        reflect.Value
+	entry        *TypeReflectCacheEntry
+	parentMap    *reflect.Value
+	parentMapKey *reflect.Value
+	mode         reflectMode
+
+	converted *valueReflect
+}
+
+// get converts the value if it hasn't been converted yet, and panics if
+// the conversion fails.
+func (l *valueLazy) get() *valueReflect {
+	if l.converted == nil {
+		v, err := wrapValueReflectNow(l.value, l.entry, l.parentMap, l.parentMapKey, l.mode)
+		if err != nil {
+			panic(err)
+		}
+		l.converted = v
+	}
+	return l.converted
+}
+
+func (l *valueLazy) IsMap() bool {
+	return l.get().IsMap()
+}
+
+func (l *valueLazy) IsList() bool {
+	return l.get().IsList()
+}
+
+func (l *valueLazy) IsBool() bool {
+	return l.get().IsBool()
+}
+
+func (l *valueLazy) IsInt() bool {
+	return l.get().IsInt()
+}
+
+func (l *valueLazy) IsFloat() bool {
+	return l.get().IsFloat()
+}
+
+func (l *valueLazy) IsString() bool {
+	return l.get().IsString()
+}
+
+func (l *valueLazy) IsScalar() bool {
+	return l.get().IsScalar()
+}
+
+func (l *valueLazy) IsNull() bool {
+	return l.get().IsNull()
+}
+
+func (l *valueLazy) AsMap() Map {
+	return l.get().AsMap()
+}
+
+func (l *valueLazy) AsMapUsing(a Allocator) Map {
+	return l.get().AsMapUsing(a)
+}
+
+func (l *valueLazy) AsList() List {
+	return l.get().AsList()
+}
+
+func (l *valueLazy) AsListUsing(a Allocator) List {
+	return l.get().AsListUsing(a)
+}
+
+func (l *valueLazy) AsBool() bool {
+	return l.get().AsBool()
+}
+
+func (l *valueLazy) AsInt() int64 {
+	return l.get().AsInt()
+}
+
+func (l *valueLazy) AsFloat() float64 {
+	return l.get().AsFloat()
+}
+
+func (l *valueLazy) AsString() string {
+	return l.get().AsString()
+}
+
+func (l *valueLazy) Unstructured() interface{} {
+	return l.get().Unstructured()
+}
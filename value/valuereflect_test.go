@@ -20,8 +20,11 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
 	"sort"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -30,7 +33,7 @@ func MustReflect(i interface{}) Value {
 	if i == nil {
 		return NewValueInterface(nil)
 	}
-	v, err := wrapValueReflect(reflect.ValueOf(i), nil, nil)
+	v, err := wrapValueReflect(reflect.ValueOf(i), nil, nil, 0)
 	if err != nil {
 		panic(err)
 	}
@@ -74,6 +77,22 @@ func TestReflectPrimitives(t *testing.T) {
 		t.Errorf("expected rv.Int to be 3000000000 but got %v", rv.Unstructured())
 	}
 
+	rv = MustReflect(uint64(42))
+	if !rv.IsInt() {
+		t.Error("expected IsInt to be true")
+	}
+	if rv.AsInt() != 42 {
+		t.Errorf("expected rv.Int to be 42 but got %v", rv.Unstructured())
+	}
+
+	rv = MustReflect(uint64(math.MaxUint64))
+	if !rv.IsFloat() {
+		t.Error("expected IsFloat to be true")
+	}
+	if rv.AsFloat() != float64(math.MaxUint64) {
+		t.Errorf("expected rv.Float to be %v but got %v", float64(math.MaxUint64), rv.Unstructured())
+	}
+
 	rv = MustReflect(1.5)
 	if !rv.IsFloat() {
 		t.Error("expected IsFloat to be true")
@@ -510,6 +529,251 @@ func TestReflectMutateNestedStruct(t *testing.T) {
 	}
 }
 
+func TestReflectReadOnly(t *testing.T) {
+	type item struct {
+		Name string `json:"name"`
+	}
+	type object struct {
+		Labels map[string]string `json:"labels,omitempty"`
+		Items  []item            `json:"items,omitempty"`
+		Nested *item             `json:"nested,omitempty"`
+	}
+	obj := object{
+		Labels: map[string]string{"app": "web"},
+		Items:  []item{{Name: "a"}, {Name: "b"}},
+		Nested: &item{Name: "c"},
+	}
+	if _, err := NewValueReflect(obj); err == nil {
+		t.Fatal("expected NewValueReflect to reject a non-pointer value")
+	}
+	rv, err := NewValueReflectReadOnly(obj)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := MustReflect(&obj)
+	if !reflect.DeepEqual(rv.Unstructured(), expected.Unstructured()) {
+		t.Errorf("expected %v but got: %v", expected.Unstructured(), rv.Unstructured())
+	}
+	if !Equals(rv, expected) {
+		t.Errorf("expected %v to equal %v", rv, expected)
+	}
+	a := NewFreelistAllocator()
+	count := 0
+	rv.AsMapUsing(a).IterateUsing(a, func(key string, v Value) bool {
+		count++
+		return true
+	})
+	if count != 3 {
+		t.Errorf("expected to iterate over 3 fields, got %v", count)
+	}
+
+	expectPanic := func(name string, fn func()) {
+		t.Helper()
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("expected %v to panic", name)
+			} else if !strings.Contains(fmt.Sprint(r), "read-only") {
+				t.Errorf("expected %v to panic with a read-only message, got %v", name, r)
+			}
+		}()
+		fn()
+	}
+	root := rv.AsMap()
+	labels, _ := root.Get("labels")
+	items, _ := root.Get("items")
+	nested, _ := root.Get("nested")
+	expectPanic("set on root", func() { root.Set("labels", NewValueInterface(nil)) })
+	expectPanic("delete on root", func() { root.Delete("nested") })
+	expectPanic("set on map", func() { labels.AsMap().Set("app", NewValueInterface("db")) })
+	expectPanic("delete on map", func() { labels.AsMap().Delete("app") })
+	expectPanic("set on list item", func() { items.AsList().At(0).AsMap().Set("name", NewValueInterface("z")) })
+	expectPanic("set on list item with allocator", func() {
+		items.AsListUsing(a).AtUsing(a, 1).AsMapUsing(a).Set("name", NewValueInterface("z"))
+	})
+	expectPanic("set on ranged list item", func() {
+		r := items.AsList().Range()
+		r.Next()
+		_, v := r.Item()
+		v.AsMap().Set("name", NewValueInterface("z"))
+	})
+	expectPanic("set on pointer", func() { nested.AsMap().Set("name", NewValueInterface("z")) })
+	root.Iterate(func(key string, v Value) bool {
+		if key == "labels" {
+			expectPanic("set on iterated map", func() { v.AsMap().Set("app", NewValueInterface("db")) })
+		}
+		return true
+	})
+
+	if obj.Labels["app"] != "web" || obj.Items[0].Name != "a" || obj.Nested.Name != "c" {
+		t.Errorf("expected the object to be left untouched, got %v", obj)
+	}
+
+	// Values reflected from pointers remain mutable after a read-only one
+	// was freed to the same allocator.
+	a.Free(rv.AsMapUsing(a))
+	mutable, _ := MustReflect(&obj).AsMapUsing(a).GetUsing(a, "labels")
+	mutable.AsMapUsing(a).Set("app", NewValueInterface("db"))
+	if obj.Labels["app"] != "db" {
+		t.Errorf("expected labels to be updated, got %v", obj.Labels)
+	}
+}
+
+// conversions counts the calls to countingConvertable.MarshalJSON.
+var conversions int
+
+type countingConvertable struct {
+	Value interface{}
+}
+
+func (t countingConvertable) MarshalJSON() ([]byte, error) {
+	conversions++
+	return json.Marshal(t.Value)
+}
+
+type lazyTestStruct struct {
+	Name  string                `json:"name"`
+	Large countingConvertable   `json:"large"`
+	Items []countingConvertable `json:"items"`
+}
+
+func newLazyTestStruct(n int) *lazyTestStruct {
+	large := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		large[fmt.Sprintf("key%d", i)] = i
+	}
+	return &lazyTestStruct{
+		Name:  "name",
+		Large: countingConvertable{Value: large},
+		Items: []countingConvertable{{Value: "a"}, {Value: []interface{}{"b"}}},
+	}
+}
+
+func TestReflectLazyConversion(t *testing.T) {
+	obj := newLazyTestStruct(10)
+	eager, err := NewValueReflect(obj)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	conversions = 0
+	lazy, err := NewValueReflect(obj, LazyConversion)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	readName := func(v Value) string {
+		var name string
+		v.AsMap().Iterate(func(key string, v Value) bool {
+			if key == "name" {
+				name = v.AsString()
+			}
+			return true
+		})
+		return name
+	}
+	conversions = 0
+	if name := readName(eager); name != "name" {
+		t.Errorf("expected name, got %v", name)
+	}
+	if conversions != 1 {
+		t.Errorf("expected the large field to be converted when iterating eagerly, got %v conversions", conversions)
+	}
+	conversions = 0
+	if name := readName(lazy); name != "name" {
+		t.Errorf("expected name, got %v", name)
+	}
+	if conversions != 0 {
+		t.Errorf("expected no conversion when iterating lazily, got %v conversions", conversions)
+	}
+
+	// Accessing a field converts it, once.
+	large, _ := lazy.AsMap().Get("large")
+	if !large.IsMap() || large.AsMap().Length() != 10 {
+		t.Errorf("expected the large field to be a map of 10 items, got %v", large.Unstructured())
+	}
+	if conversions != 1 {
+		t.Errorf("expected the large field to be converted once, got %v conversions", conversions)
+	}
+
+	// List items are converted lazily too.
+	conversions = 0
+	items, _ := lazy.AsMap().Get("items")
+	if l := items.AsList(); l.Length() != 2 || !l.At(1).IsList() {
+		t.Errorf("expected a list of two items, got %v", items.Unstructured())
+	}
+	if conversions != 1 {
+		t.Errorf("expected only the accessed item to be converted, got %v conversions", conversions)
+	}
+
+	if !reflect.DeepEqual(lazy.Unstructured(), eager.Unstructured()) {
+		t.Errorf("expected %v, got %v", eager.Unstructured(), lazy.Unstructured())
+	}
+	if !Equals(lazy, eager) {
+		t.Errorf("expected lazy and eager values to be equal")
+	}
+
+	// The root is converted right away.
+	conversions = 0
+	if _, err := NewValueReflect(&countingConvertable{Value: "a"}, LazyConversion); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conversions != 1 {
+		t.Errorf("expected the root to be converted, got %v conversions", conversions)
+	}
+}
+
+// TestReflectLazyConversionConcurrent is meant to be run with -race: lazy
+// values aren't safe for concurrent reads, but separate lazy values of the
+// same object are.
+func TestReflectLazyConversionConcurrent(t *testing.T) {
+	obj := &struct {
+		Large Convertable `json:"large"`
+	}{Large: Convertable{Value: map[string]interface{}{"a": "b"}}}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := NewValueReflect(obj, LazyConversion)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			large, _ := v.AsMap().Get("large")
+			if !large.IsMap() || large.AsMap().Length() != 1 {
+				t.Errorf("expected the large field to be a map of 1 item, got %v", large.Unstructured())
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkReflectReadOneField(b *testing.B) {
+	obj := newLazyTestStruct(1000)
+	for _, bc := range []struct {
+		name string
+		opts []ReflectOption
+	}{
+		{"eager", nil},
+		{"lazy", []ReflectOption{LazyConversion}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				v, err := NewValueReflect(obj, bc.opts...)
+				if err != nil {
+					b.Fatal(err)
+				}
+				v.AsMap().Iterate(func(key string, v Value) bool {
+					if key == "name" {
+						_ = v.AsString()
+					}
+					return true
+				})
+			}
+		})
+	}
+}
+
 func TestReflectMap(t *testing.T) {
 	cases := []struct {
 		name                 string
@@ -760,3 +1024,69 @@ func TestMapZip(t *testing.T) {
 		})
 	}
 }
+
+func TestMapKeys(t *testing.T) {
+	type s struct {
+		A string  `json:"a"`
+		B string  `json:"b,omitempty"`
+		C *string `json:"c,omitempty"`
+		I T       `json:",inline"`
+		D string  `json:"-"`
+	}
+	c := "c"
+	cases := []struct {
+		name     string
+		value    Value
+		expected []string
+	}{
+		{
+			name:     "struct",
+			value:    MustReflect(&s{A: "a", C: &c, D: "d"}),
+			expected: []string{"a", "c", "int"},
+		},
+		{
+			name:     "emptyStruct",
+			value:    MustReflect(&emptyStruct{}),
+			expected: nil,
+		},
+		{
+			name:     "map",
+			value:    MustReflect(&map[string]int{"x": 1, "y": 2}),
+			expected: []string{"x", "y"},
+		},
+		{
+			name:     "mapString",
+			value:    NewValueInterface(map[string]interface{}{"x": 1, "y": nil}),
+			expected: []string{"x", "y"},
+		},
+		{
+			name:     "mapInterface",
+			value:    NewValueInterface(map[interface{}]interface{}{"x": 1, "y": 2, 3: 4}),
+			expected: []string{"x", "y"},
+		},
+		{
+			name:     "frozen",
+			value:    Freeze(NewValueInterface(map[string]interface{}{"y": 1, "x": 2})),
+			expected: []string{"x", "y"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := tc.value.AsMap()
+			var iterated []string
+			m.Iterate(func(key string, _ Value) bool {
+				iterated = append(iterated, key)
+				return true
+			})
+			keys := m.Keys()
+			sort.Strings(keys)
+			sort.Strings(iterated)
+			if len(keys) != len(tc.expected) || (len(keys) > 0 && !reflect.DeepEqual(keys, tc.expected)) {
+				t.Errorf("expected keys %v, got %v", tc.expected, keys)
+			}
+			if len(keys) != len(iterated) || (len(keys) > 0 && !reflect.DeepEqual(keys, iterated)) {
+				t.Errorf("expected keys %v to match the iterated keys %v", keys, iterated)
+			}
+		})
+	}
+}
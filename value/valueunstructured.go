@@ -18,6 +18,7 @@ package value
 
 import (
 	"fmt"
+	"math"
 )
 
 // NewValueInterface creates a Value backed by an "interface{}" type,
@@ -88,6 +89,10 @@ func (v valueUnstructured) IsFloat() bool {
 		return true
 	} else if _, ok := v.Value.(float32); ok {
 		return true
+	} else if i, ok := v.Value.(uint64); ok {
+		// uint64 values that don't fit in an int64 are stored as
+		// floats, which is how they would be decoded from JSON.
+		return i > math.MaxInt64
 	}
 	return false
 }
@@ -95,6 +100,8 @@ func (v valueUnstructured) IsFloat() bool {
 func (v valueUnstructured) AsFloat() float64 {
 	if f, ok := v.Value.(float32); ok {
 		return float64(f)
+	} else if i, ok := v.Value.(uint64); ok {
+		return float64(i)
 	}
 	return v.Value.(float64)
 }
@@ -120,6 +127,8 @@ func (v valueUnstructured) IsInt() bool {
 		return true
 	} else if _, ok := v.Value.(uint32); ok {
 		return true
+	} else if i, ok := v.Value.(uint64); ok {
+		return i <= math.MaxInt64
 	}
 	return false
 }
@@ -141,6 +150,11 @@ func (v valueUnstructured) AsInt() int64 {
 		return int64(i)
 	} else if i, ok := v.Value.(uint32); ok {
 		return int64(i)
+	} else if i, ok := v.Value.(uint64); ok {
+		if i > math.MaxInt64 {
+			panic(fmt.Errorf("uint64 value %v overflows an int64", i))
+		}
+		return int64(i)
 	}
 	return v.Value.(int64)
 }
@@ -169,6 +183,16 @@ func (v valueUnstructured) AsBool() bool {
 	return v.Value.(bool)
 }
 
+func (v valueUnstructured) IsScalar() bool {
+	switch v.Value.(type) {
+	case string, bool, float64, float32,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64:
+		return true
+	}
+	return false
+}
+
 func (v valueUnstructured) IsNull() bool {
 	return v.Value == nil
 }
@@ -0,0 +1,105 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+func TestUnstructuredUint64(t *testing.T) {
+	v := value.NewValueInterface(uint64(42))
+	if !v.IsInt() || v.IsFloat() {
+		t.Fatalf("expected a small uint64 to be an int")
+	}
+	if v.AsInt() != 42 {
+		t.Errorf("expected 42, got %v", v.AsInt())
+	}
+
+	v = value.NewValueInterface(uint64(math.MaxUint64))
+	if v.IsInt() || !v.IsFloat() {
+		t.Fatalf("expected an overflowing uint64 to be a float")
+	}
+	if v.AsFloat() != float64(math.MaxUint64) {
+		t.Errorf("expected %v, got %v", float64(math.MaxUint64), v.AsFloat())
+	}
+
+	got, err := value.ToJSON(value.NewValueInterface(map[string]interface{}{
+		"small": uint64(42),
+		"large": uint64(math.MaxUint64),
+	}))
+	if err != nil {
+		t.Fatalf("failed to serialize: %v", err)
+	}
+	if expected := `{"large":18446744073709551615,"small":42}`; string(got) != expected {
+		t.Errorf("expected %v, got %v", expected, string(got))
+	}
+}
+
+func TestIsScalar(t *testing.T) {
+	var nilString *string
+	cases := []struct {
+		name     string
+		value    interface{}
+		isScalar bool
+	}{
+		{name: "string", value: "a", isScalar: true},
+		{name: "bytes", value: []byte("a"), isScalar: true},
+		{name: "bool", value: true, isScalar: true},
+		{name: "int", value: 1, isScalar: true},
+		{name: "int32", value: int32(1), isScalar: true},
+		{name: "uint64", value: uint64(math.MaxUint64), isScalar: true},
+		{name: "float", value: 1.5, isScalar: true},
+		{name: "float32", value: float32(1.5), isScalar: true},
+		{name: "null", value: nilString, isScalar: false},
+		{name: "list", value: []interface{}{1}, isScalar: false},
+		{name: "map", value: map[string]interface{}{"a": 1}, isScalar: false},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ptr := reflect.New(reflect.TypeOf(tc.value))
+			ptr.Elem().Set(reflect.ValueOf(tc.value))
+			reflected, err := value.NewValueReflect(ptr.Interface())
+			if err != nil {
+				t.Fatal(err)
+			}
+			values := map[string]value.Value{
+				"reflect": reflected,
+				"frozen":  value.Freeze(reflected),
+			}
+			if _, ok := tc.value.([]byte); !ok && tc.value != interface{}(nilString) {
+				values["unstructured"] = value.NewValueInterface(tc.value)
+			}
+			for backing, v := range values {
+				if got := v.IsScalar(); got != tc.isScalar {
+					t.Errorf("%v: expected IsScalar to be %v, got %v", backing, tc.isScalar, got)
+				}
+				if v.IsScalar() && (v.IsNull() || v.IsMap() || v.IsList()) {
+					t.Errorf("%v: expected a scalar not to be null, a map or a list", backing)
+				}
+			}
+		})
+	}
+
+	if value.NewValueInterface(nil).IsScalar() {
+		t.Errorf("expected null not to be a scalar")
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value
+
+import (
+	"bytes"
+	"fmt"
+
+	yaml "sigs.k8s.io/yaml/goyaml.v2"
+	yamlv3 "sigs.k8s.io/yaml/goyaml.v3"
+)
+
+// FromYAML reads a YAML document.
+//
+// Aliases are resolved to copies of the values of their anchors, and an
+// alias within the value of its own anchor is an error.
+//
+// Merge keys ("<<") are expanded deterministically: the entries of the
+// merged maps are inserted in place of the merge key, in the order of the
+// merge sequence if there are several maps to merge, and a key that
+// appears more than once in the resulting map takes the value of its last
+// occurrence. Later keys therefore always win, whether they are explicit
+// or merged, e.g. the value of "a" is 2 in
+//
+//	{a: 1, <<: {a: 2}}
+//
+// and 1 in
+//
+//	{<<: {a: 2}, a: 1}
+//
+// Note that this differs from the YAML merge key specification, where
+// explicit keys always override merged ones and earlier merged maps
+// override later ones.
+func FromYAML(input []byte) (Value, error) {
+	if bytes.Contains(input, []byte("<<")) {
+		expanded, err := expandYAMLMergeKeys(input)
+		if err != nil {
+			return nil, err
+		}
+		input = expanded
+	}
+	var v interface{}
+	if err := yaml.Unmarshal(input, &v); err != nil {
+		return nil, err
+	}
+	return NewValueInterface(v), nil
+}
+
+// expandYAMLMergeKeys rewrites the YAML document input without merge keys,
+// as documented by FromYAML. Scalars keep their style, so that they are
+// resolved to the same values once the document is unmarshaled.
+func expandYAMLMergeKeys(input []byte) ([]byte, error) {
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(input, &doc); err != nil {
+		return nil, err
+	}
+	if doc.IsZero() {
+		return input, nil
+	}
+	if err := expandMergeKeys(&doc, map[*yamlv3.Node]bool{}, map[*yamlv3.Node]bool{}); err != nil {
+		return nil, err
+	}
+	return yamlv3.Marshal(&doc)
+}
+
+// expandMergeKeys replaces the merge keys of the maps within n, once per
+// node since aliases share them. Aliases to the nodes that are being
+// expanded, i.e. n and its parents, are recursive and rejected.
+func expandMergeKeys(n *yamlv3.Node, expanded, expanding map[*yamlv3.Node]bool) error {
+	if n == nil {
+		return nil
+	}
+	if n.Kind == yamlv3.AliasNode && expanding[n.Alias] {
+		return fmt.Errorf("yaml: anchor '%s' value contains itself", n.Value)
+	}
+	if expanded[n] {
+		return nil
+	}
+	expanded[n] = true
+	expanding[n] = true
+	defer delete(expanding, n)
+	switch n.Kind {
+	case yamlv3.AliasNode:
+		return expandMergeKeys(n.Alias, expanded, expanding)
+	case yamlv3.DocumentNode, yamlv3.SequenceNode:
+		for _, child := range n.Content {
+			if err := expandMergeKeys(child, expanded, expanding); err != nil {
+				return err
+			}
+		}
+	case yamlv3.MappingNode:
+		content := make([]*yamlv3.Node, 0, len(n.Content))
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, val := n.Content[i], n.Content[i+1]
+			if err := expandMergeKeys(val, expanded, expanding); err != nil {
+				return err
+			}
+			if key.Kind != yamlv3.ScalarNode || key.ShortTag() != "!!merge" {
+				content = append(content, key, val)
+				continue
+			}
+			maps, ok := mergedMaps(val)
+			if !ok {
+				return fmt.Errorf("yaml: line %d: map merge requires map or sequence of maps as the value", val.Line)
+			}
+			for _, m := range maps {
+				content = append(content, m.Content...)
+			}
+		}
+		n.Content = content
+	}
+	return nil
+}
+
+// mergedMaps returns the maps merged by the value of a merge key, which
+// must be a map or a sequence of maps, whose own merge keys have been
+// expanded.
+func mergedMaps(n *yamlv3.Node) ([]*yamlv3.Node, bool) {
+	n = unalias(n)
+	switch n.Kind {
+	case yamlv3.MappingNode:
+		return []*yamlv3.Node{n}, true
+	case yamlv3.SequenceNode:
+		maps := make([]*yamlv3.Node, 0, len(n.Content))
+		for _, item := range n.Content {
+			if item = unalias(item); item.Kind != yamlv3.MappingNode {
+				return nil, false
+			}
+			maps = append(maps, item)
+		}
+		return maps, true
+	}
+	return nil, false
+}
+
+func unalias(n *yamlv3.Node) *yamlv3.Node {
+	if n.Kind == yamlv3.AliasNode {
+		return n.Alias
+	}
+	return n
+}
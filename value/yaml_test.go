@@ -0,0 +1,169 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFromYAMLMergeKeys(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{
+			name: "explicit key after the merge key overrides",
+			input: `
+base: &base {a: 1, b: 2}
+m:
+  <<: *base
+  a: 3
+`,
+			expected: map[string]interface{}{
+				"base": map[string]interface{}{"a": 1, "b": 2},
+				"m":    map[string]interface{}{"a": 3, "b": 2},
+			},
+		},
+		{
+			name: "merge key after the explicit key overrides",
+			input: `
+base: &base {a: 1, b: 2}
+m:
+  a: 3
+  <<: *base
+`,
+			expected: map[string]interface{}{
+				"base": map[string]interface{}{"a": 1, "b": 2},
+				"m":    map[string]interface{}{"a": 1, "b": 2},
+			},
+		},
+		{
+			name: "later merged maps override",
+			input: `
+x: &x {a: 1, b: 1}
+z: &z {a: 2, c: 2}
+m:
+  <<: [*x, *z]
+`,
+			expected: map[string]interface{}{
+				"x": map[string]interface{}{"a": 1, "b": 1},
+				"z": map[string]interface{}{"a": 2, "c": 2},
+				"m": map[string]interface{}{"a": 2, "b": 1, "c": 2},
+			},
+		},
+		{
+			name: "merged maps are expanded first",
+			input: `
+x: &x {a: 1}
+z: &z {<<: *x, b: 2}
+m: {<<: *z, c: 3}
+`,
+			expected: map[string]interface{}{
+				"x": map[string]interface{}{"a": 1},
+				"z": map[string]interface{}{"a": 1, "b": 2},
+				"m": map[string]interface{}{"a": 1, "b": 2, "c": 3},
+			},
+		},
+		{
+			name: "duplicate keys",
+			input: `
+m:
+  a: 1
+  a: 2
+`,
+			expected: map[string]interface{}{
+				"m": map[string]interface{}{"a": 2},
+			},
+		},
+		{
+			name: "scalars are resolved as without merge keys",
+			input: `
+base: &base {plain: yes, quoted: "yes", octal: 0755, string: "0755", shift: 1 << 2}
+m:
+  <<: *base
+  items: [*base]
+`,
+			expected: map[string]interface{}{
+				"base": map[string]interface{}{"plain": true, "quoted": "yes", "octal": 493, "string": "0755", "shift": "1 << 2"},
+				"m": map[string]interface{}{
+					"plain": true, "quoted": "yes", "octal": 493, "string": "0755", "shift": "1 << 2",
+					"items": []interface{}{
+						map[string]interface{}{"plain": true, "quoted": "yes", "octal": 493, "string": "0755", "shift": "1 << 2"},
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := FromYAML([]byte(tc.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expected := NewValueInterface(tc.expected); !Equals(v, expected) {
+				t.Errorf("expected %v, got %v", ToString(expected), ToString(v))
+			}
+		})
+	}
+}
+
+func TestFromYAMLInvalidMergeKey(t *testing.T) {
+	for _, input := range []string{
+		"m: {<<: 1}",
+		"m: {<<: [{a: 1}, 2]}",
+	} {
+		if _, err := FromYAML([]byte(input)); err == nil || !strings.Contains(err.Error(), "map merge requires map or sequence of maps") {
+			t.Errorf("expected an invalid merge error for %q, got %v", input, err)
+		}
+	}
+}
+
+func TestFromYAMLAliases(t *testing.T) {
+	v, err := FromYAML([]byte(`
+base: &base {a: 1, b: [x, z]}
+m: *base
+c: {d: *base}
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	base := map[string]interface{}{"a": 1, "b": []interface{}{"x", "z"}}
+	expected := NewValueInterface(map[string]interface{}{
+		"base": base,
+		"m":    base,
+		"c":    map[string]interface{}{"d": base},
+	})
+	if !Equals(v, expected) {
+		t.Errorf("expected %v, got %v", ToString(expected), ToString(v))
+	}
+}
+
+func TestFromYAMLRecursiveAliases(t *testing.T) {
+	for _, input := range []string{
+		"m: &m {a: *m}",
+		"l: &l [1, *l]",
+		"m: &m {<<: *m}",
+		"m: &m {a: {<<: *m}}",
+		"- &m {<<: [{a: 1}, *m]}",
+	} {
+		if _, err := FromYAML([]byte(input)); err == nil || !strings.Contains(err.Error(), "value contains itself") {
+			t.Errorf("expected a recursive alias error for %q, got %v", input, err)
+		}
+	}
+}